@@ -2,149 +2,327 @@
 package config
 
 import (
-	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
-
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/jmoiron/sqlx"
-	"github.com/joho/godotenv"
 )
 
 // Config struct คือโครงสร้างหลักที่เก็บการตั้งค่าทั้งหมดของแอปพลิเคชัน
-// ประกอบด้วย 3 ส่วนหลัก: Database, JWT, และ Server configuration
+// ค่าแต่ละ field ถูกโหลดจากหลายแหล่งตามลำดับ (แหล่งหลังทับแหล่งก่อน):
+// ค่า default → ไฟล์ YAML/TOML → ไฟล์ .env → environment variables → CLI flags
+//
+// struct tags ที่ใช้:
+//   - env:      ชื่อตัวแปร environment (และใช้สร้างชื่อ flag เช่น DB_HOST → --db-host)
+//   - default:  ค่าเริ่มต้นเมื่อไม่มีแหล่งใดกำหนดค่า
+//   - validate: กฎตรวจสอบของ go-playground/validator
+//   - secret:   ค่าที่ต้องซ่อนเมื่อแสดงผลด้วยคำสั่ง `config print`
+//   - reload:   "false" = ค่าที่ไม่สามารถเปลี่ยนขณะรันได้ (ต้อง restart) ใช้ได้ทั้งกับ field และทั้ง section
 type Config struct {
-	Database *DatabaseConfig // การตั้งค่าเกี่ยวกับฐานข้อมูล
-	JWT      *JWTConfig      // การตั้งค่าเกี่ยวกับ JSON Web Token
-	Server   *ServerConfig   // การตั้งค่าเกี่ยวกับเซิร์ฟเวอร์
-	App      *AppConfig      // เพิ่มการตั้งค่าเกี่ยวกับแอปพลิเคชัน
+	Database     *DatabaseConfig     `yaml:"database" toml:"database" reload:"false"` // การตั้งค่าเกี่ยวกับฐานข้อมูล
+	JWT          *JWTConfig          `yaml:"jwt" toml:"jwt"`                          // การตั้งค่าเกี่ยวกับ JSON Web Token
+	Server       *ServerConfig       `yaml:"server" toml:"server"`                    // การตั้งค่าเกี่ยวกับเซิร์ฟเวอร์
+	App          *AppConfig          `yaml:"app" toml:"app"`                          // เพิ่มการตั้งค่าเกี่ยวกับแอปพลิเคชัน
+	Tracing      *TracingConfig      `yaml:"tracing" toml:"tracing" reload:"false"`   // การตั้งค่า OpenTelemetry tracing
+	CORS         *CORSConfig         `yaml:"cors" toml:"cors"`                        // นโยบาย CORS ของ /api/v1 และ /swagger
+	Security     *SecurityConfig     `yaml:"security" toml:"security"`                // security headers (HSTS, CSP, X-Frame-Options, ...)
+	TLS          *TLSConfig          `yaml:"tls" toml:"tls" reload:"false"`           // การให้บริการผ่าน HTTPS
+	OAuth        *OAuthConfig        `yaml:"oauth" toml:"oauth"`                      // การเข้าสู่ระบบผ่าน OIDC/OAuth2 (Google, GitHub)
+	AuthServer   *AuthServerConfig   `yaml:"auth_server" toml:"auth_server"`          // OAuth2 authorization server สำหรับแอปของพาร์ทเนอร์
+	Session      *SessionConfig      `yaml:"session" toml:"session"`                  // การเก็บ token ใน cookie สำหรับ browser และการป้องกัน CSRF
+	Password     *PasswordConfig     `yaml:"password" toml:"password"`                // นโยบายรหัสผ่านและอัลกอริธึม hash
+	Registration *RegistrationConfig `yaml:"registration" toml:"registration"`        // การลงทะเบียนด้วยตัวเองและคำเชิญผู้ใช้
+	Mail         *MailConfig         `yaml:"mail" toml:"mail"`                        // การส่งอีเมลของระบบ (เช่น คำเชิญ)
+	Tenant       *TenantConfig       `yaml:"tenant" toml:"tenant"`                    // การเลือกองค์กร (tenant) ของ request
+	Storage      *StorageConfig      `yaml:"storage" toml:"storage" reload:"false"`   // ที่เก็บไฟล์ที่อัปโหลด (เช่น รูปโปรไฟล์)
+	Avatar       *AvatarConfig       `yaml:"avatar" toml:"avatar"`                    // ข้อจำกัดและขนาด thumbnail ของรูปโปรไฟล์
+	Events       *EventsConfig       `yaml:"events" toml:"events"`                    // worker ของ event bus ที่ส่ง domain event จาก outbox ให้ subscriber
+	Webhook      *WebhookConfig      `yaml:"webhook" toml:"webhook"`                  // การส่ง webhook ของ event ผู้ใช้
 }
 
-// DatabaseConfig struct เก็บข้อมูลการเชื่อมต่อฐานข้อมูล MySQL
+// DatabaseConfig struct เก็บข้อมูลการเชื่อมต่อฐานข้อมูล (MySQL, PostgreSQL หรือ SQLite)
+// เป็นเพียงค่าการตั้งค่าเท่านั้น การเปิดการเชื่อมต่อจริงอยู่ใน package database
 type DatabaseConfig struct {
-	Host     string   // ที่อยู่ของเซิร์ฟเวอร์ฐานข้อมูล (เช่น localhost)
-	Port     string   // พอร์ตที่ใช้เชื่อมต่อ (เช่น 3306)
-	User     string   // ชื่อผู้ใช้สำหรับเข้าสู่ฐานข้อมูล
-	Password string   // รหัสผ่านสำหรับเข้าสู่ฐานข้อมูล
-	DBName   string   // ชื่อของฐานข้อมูลที่จะใช้งาน
-	DB       *sqlx.DB // ตัวแปรเก็บการเชื่อมต่อฐานข้อมูลจริง
+	Driver   string `yaml:"driver" toml:"driver" env:"DB_DRIVER" default:"mysql" validate:"oneof=mysql postgres sqlite"`              // ชนิดฐานข้อมูล: mysql, postgres หรือ sqlite
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST" default:"localhost" validate:"required_unless=Driver sqlite"`              // ที่อยู่ของเซิร์ฟเวอร์ฐานข้อมูล (เช่น localhost)
+	Port     string `yaml:"port" toml:"port" env:"DB_PORT" default:"3306" validate:"required_unless=Driver sqlite,omitempty,numeric"` // พอร์ตที่ใช้เชื่อมต่อ (เช่น 3306, 5432)
+	User     string `yaml:"user" toml:"user" env:"DB_USER" default:"root" validate:"required_unless=Driver sqlite"`                   // ชื่อผู้ใช้สำหรับเข้าสู่ฐานข้อมูล
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD" secret:"true"`                                                 // รหัสผ่านสำหรับเข้าสู่ฐานข้อมูล
+	DBName   string `yaml:"name" toml:"name" env:"DB_NAME" default:"gotemplate" validate:"required"`                                  // ชื่อฐานข้อมูล (สำหรับ sqlite คือ path ของไฟล์ หรือ :memory:)
+	Params   string `yaml:"params" toml:"params" env:"DB_PARAMS"`                                                                     // พารามิเตอร์เพิ่มเติมของ DSN (query string) ว่าง = ใช้ค่าเริ่มต้นของแต่ละ driver
+
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"DB_AUTO_MIGRATE" default:"true"` // รัน migration ที่ยังไม่ได้รันอัตโนมัติตอนเริ่มระบบ
+
+	// การเชื่อมต่อแบบเข้ารหัส TLS
+	TLS         string `yaml:"tls" toml:"tls" env:"DB_TLS" validate:"omitempty,oneof=false true skip-verify preferred custom"` // โหมด TLS: false, true, skip-verify, preferred หรือ custom (ใช้ไฟล์ด้านล่าง)
+	TLSCAFile   string `yaml:"tls_ca_file" toml:"tls_ca_file" env:"DB_TLS_CA_FILE" validate:"required_if=TLS custom"`          // ไฟล์ CA สำหรับตรวจสอบใบรับรองของเซิร์ฟเวอร์ (โหมด custom)
+	TLSCertFile string `yaml:"tls_cert_file" toml:"tls_cert_file" env:"DB_TLS_CERT_FILE"`                                      // ไฟล์ใบรับรองของ client (ถ้าเซิร์ฟเวอร์ต้องการ)
+	TLSKeyFile  string `yaml:"tls_key_file" toml:"tls_key_file" env:"DB_TLS_KEY_FILE" validate:"required_with=TLSCertFile"`    // ไฟล์ private key ของ client
+
+	// Connection Pool
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25" validate:"min=0"` // จำนวนการเชื่อมต่อสูงสุดที่เปิดพร้อมกัน (0 = ไม่จำกัด)
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"5" validate:"min=0"`  // จำนวนการเชื่อมต่อที่เก็บไว้ในสถานะรอ
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"5m"`         // อายุสูงสุดของการเชื่อมต่อหนึ่ง (0 = ไม่จำกัด)
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"0s"`      // เวลาสูงสุดที่การเชื่อมต่อว่างได้ก่อนถูกปิด (0 = ไม่จำกัด)
+
+	// การลองเชื่อมต่อใหม่ตอนเริ่มระบบ
+	ConnectRetries    int           `yaml:"connect_retries" toml:"connect_retries" env:"DB_CONNECT_RETRIES" default:"10" validate:"min=0"`              // จำนวนครั้งที่ลองใหม่หลังครั้งแรกล้มเหลว
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" toml:"connect_backoff" env:"DB_CONNECT_BACKOFF" default:"1s" validate:"min=0"`              // เวลารอก่อนลองใหม่ครั้งแรก (เพิ่มเป็นสองเท่าทุกครั้ง)
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" toml:"connect_max_backoff" env:"DB_CONNECT_MAX_BACKOFF" default:"30s" validate:"min=0"` // เวลารอสูงสุดระหว่างการลองแต่ละครั้ง
 }
 
 // JWTConfig struct เก็บการตั้งค่าเกี่ยวกับ JWT (JSON Web Token)
 type JWTConfig struct {
-	Secret string        // กุญแจลับสำหรับเซ็น JWT token
-	Expire time.Duration // ระยะเวลาที่ token จะหมดอายุ
+	Secret string        `yaml:"secret" toml:"secret" env:"JWT_SECRET" default:"default-secret" validate:"required" secret:"true"` // กุญแจลับสำหรับเซ็น JWT token
+	Expire time.Duration `yaml:"expire" toml:"expire" env:"JWT_EXPIRE" default:"24h" validate:"min=1m"`                            // ระยะเวลาที่ token จะหมดอายุ
+
+	ImpersonationExpire time.Duration `yaml:"impersonation_expire" toml:"impersonation_expire" env:"JWT_IMPERSONATION_EXPIRE" default:"15m" validate:"min=1m,max=2h"` // อายุของ token ที่ Admin ออกเพื่อเข้าสู่ระบบแทนผู้ใช้
+	StatusCacheTTL      time.Duration `yaml:"status_cache_ttl" toml:"status_cache_ttl" env:"JWT_STATUS_CACHE_TTL" default:"30s" validate:"min=0"`                     // ระยะเวลาที่จำสถานะบัญชีของผู้ใช้ระหว่าง request (0 = อ่านจากฐานข้อมูลทุก request)
 }
 
 // ServerConfig struct เก็บการตั้งค่าเกี่ยวกับเซิร์ฟเวอร์
 type ServerConfig struct {
-	Port        string // พอร์ตที่เซิร์ฟเวอร์จะรัน (เช่น 8080)
-	Environment string // สภาพแวดล้อมการทำงาน (development, production)
+	Port        string `yaml:"port" toml:"port" env:"PORT" default:"8080" validate:"required,numeric" reload:"false"`                                                      // พอร์ตที่เซิร์ฟเวอร์จะรัน (เช่น 8080)
+	Environment string `yaml:"environment" toml:"environment" env:"ENVIRONMENT" default:"development" validate:"oneof=development test staging production" reload:"false"` // สภาพแวดล้อมการทำงาน (development, production)
+	LogLevel    string `yaml:"log_level" toml:"log_level" env:"LOG_LEVEL" default:"info" validate:"oneof=debug info warn error"`                                           // ระดับการบันทึก log ของ request: debug, info, warn (เฉพาะ 4xx/5xx) หรือ error (เฉพาะ 5xx)
 }
 
 // เพิ่ม AppConfig struct สำหรับข้อมูลแอปพลิเคชัน
 type AppConfig struct {
-	Name        string // ชื่อแอปพลิเคชัน
-	Version     string // เวอร์ชันของแอปพลิเคชัน
-	Description string // คำอธิบายแอปพลิเคชัน
+	Name        string `yaml:"name" toml:"name" env:"APP_NAME" default:"GoTemplate API"`                                                                          // ชื่อแอปพลิเคชัน
+	Version     string `yaml:"version" toml:"version" env:"APP_VERSION" default:"1.0.0"`                                                                          // เวอร์ชันของแอปพลิเคชัน
+	Description string `yaml:"description" toml:"description" env:"APP_DESCRIPTION" default:"A complete Go template API with authentication and user management"` // คำอธิบายแอปพลิเคชัน
 }
 
-// LoadConfig ฟังก์ชันหลักสำหรับโหลดการตั้งค่าทั้งหมด
-// จะอ่านค่าจากไฟล์ .env และสร้าง Config object พร้อมเชื่อมต่อฐานข้อมูล
-func LoadConfig() *Config {
-	// โหลดไฟล์ .env เพื่ออ่านตัวแปร environment variables
-	// หากไม่พบไฟล์ .env จะแสดงข้อความแจ้งเตือน แต่จะไม่หยุดการทำงาน
-	if err := godotenv.Load(); err != nil {
-		log.Println("ไม่พบไฟล์ .env / No .env file found")
-	}
+// TracingConfig struct เก็บการตั้งค่า OpenTelemetry tracing
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" default:"none" validate:"oneof=otlp stdout none"`         // ปลายทางของ span: otlp, stdout หรือ none (ปิดการส่งออก)
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT" default:"localhost:4318"`                  // ที่อยู่ของ OTLP collector แบบ HTTP (เช่น localhost:4318)
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure" env:"TRACING_OTLP_INSECURE" default:"true"`                            // ส่งข้อมูลไปยัง collector โดยไม่ใช้ TLS
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1" validate:"min=0,max=1"`           // สัดส่วนของ trace ที่จะเก็บ (0.0 - 1.0)
+	ServiceName  string  `yaml:"service_name" toml:"service_name" env:"TRACING_SERVICE_NAME" default:"gotemplate-api" validate:"required"` // ชื่อ service ที่แสดงใน trace
+}
 
-	// สร้าง Config object ใหม่โดยอ่านค่าจากตัวแปร environment
-	// หากไม่มีค่าใน environment จะใช้ค่า default ที่กำหนดไว้
-	config := &Config{
-		Database: &DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),  // ค่าเริ่มต้น: localhost
-			Port:     getEnv("DB_PORT", "3306"),       // ค่าเริ่มต้น: 3306 (MySQL default port)
-			User:     getEnv("DB_USER", "root"),       // ค่าเริ่มต้น: root
-			Password: getEnv("DB_PASSWORD", ""),       // ค่าเริ่มต้น: ว่าง (ไม่มีรหัสผ่าน)
-			DBName:   getEnv("DB_NAME", "gotemplate"), // ค่าเริ่มต้น: gotemplate
-		},
-		JWT: &JWTConfig{
-			Secret: getEnv("JWT_SECRET", "default-secret"),     // ค่าเริ่มต้น: default-secret
-			Expire: parseDuration(getEnv("JWT_EXPIRE", "24h")), // ค่าเริ่มต้น: 24 ชั่วโมง
-		},
-		Server: &ServerConfig{
-			Port:        getEnv("PORT", "8080"),               // ค่าเริ่มต้น: 8080
-			Environment: getEnv("ENVIRONMENT", "development"), // ค่าเริ่มต้น: development
-		},
-		App: &AppConfig{
-			Name:        getEnv("APP_NAME", "GoTemplate API"),                                                            // ค่าเริ่มต้น: GoTemplate API
-			Version:     getEnv("APP_VERSION", "1.0.0"),                                                                  // ค่าเริ่มต้น: 1.0.0
-			Description: getEnv("APP_DESCRIPTION", "A complete Go template API with authentication and user management"), // ค่าเริ่มต้น: คำอธิบายแอปพลิเคชัน
-		},
-	}
+// CORSConfig struct เก็บนโยบาย Cross-Origin Resource Sharing
+// origin รองรับรูปแบบ wildcard subdomain เช่น https://*.example.com (ตรงกับทุก subdomain แต่ไม่รวม example.com)
+// ห้ามใช้ "*" ร่วมกับ CORS_ALLOW_CREDENTIALS=true และห้ามใช้ "*" ใน production
+type CORSConfig struct {
+	AllowOrigins     []string      `yaml:"allow_origins" toml:"allow_origins" env:"CORS_ALLOW_ORIGINS" default:"*" validate:"dive,cors_origin"`                                  // origin ที่อนุญาตให้เรียก /api/v1 (คั่นด้วย comma)
+	AllowMethods     []string      `yaml:"allow_methods" toml:"allow_methods" env:"CORS_ALLOW_METHODS" default:"GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS" validate:"min=1"`        // HTTP methods ที่อนุญาต
+	AllowHeaders     []string      `yaml:"allow_headers" toml:"allow_headers" env:"CORS_ALLOW_HEADERS" default:"Origin,Content-Type,Accept,Authorization,X-CSRF-Token,X-Tenant"` // request headers ที่อนุญาตให้ส่งมา
+	ExposeHeaders    []string      `yaml:"expose_headers" toml:"expose_headers" env:"CORS_EXPOSE_HEADERS"`                                                                       // response headers ที่ JavaScript ฝั่ง browser อ่านได้ (เช่น traceparent)
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" default:"false"`                                              // อนุญาตให้ส่ง cookie/credentials ข้าม origin
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" default:"10m" validate:"min=0"`                                                             // ระยะเวลาที่ browser cache ผล preflight (0 = ไม่ส่ง header)
+	SwaggerOrigins   []string      `yaml:"swagger_allow_origins" toml:"swagger_allow_origins" env:"CORS_SWAGGER_ALLOW_ORIGINS" validate:"dive,cors_origin"`                      // origin ที่อนุญาตให้อ่าน /swagger (ว่าง = เฉพาะ origin เดียวกัน)
+}
 
-	// เริ่มการเชื่อมต่อกับฐานข้อมูล
-	config.Database.ConnectDB()
+// SecurityConfig struct เก็บการตั้งค่า security headers ที่ส่งกลับในทุก response
+// /swagger ใช้ CSP แยกต่างหาก เพราะ Swagger UI ต้องรัน inline script และโหลด style/font
+type SecurityConfig struct {
+	Headers               bool          `yaml:"headers" toml:"headers" env:"SECURITY_HEADERS" default:"true"`                                                                                                                                                                                                                                          // เปิด/ปิดการส่ง security headers ทั้งหมด
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" toml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE" default:"8760h" validate:"min=0"`                                                                                                                                                                                                         // อายุของ Strict-Transport-Security (ส่งเฉพาะ HTTPS, 0 = ปิด)
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" toml:"hsts_include_subdomains" env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS" default:"true"`                                                                                                                                                                                          // ใช้ HSTS กับทุก subdomain
+	HSTSPreload           bool          `yaml:"hsts_preload" toml:"hsts_preload" env:"SECURITY_HSTS_PRELOAD" default:"false"`                                                                                                                                                                                                                          // เพิ่ม preload เพื่อส่งเข้ารายการ HSTS preload ของ browser
+	FrameOptions          string        `yaml:"frame_options" toml:"frame_options" env:"SECURITY_FRAME_OPTIONS" default:"DENY" validate:"oneof=DENY SAMEORIGIN"`                                                                                                                                                                                       // X-Frame-Options: DENY หรือ SAMEORIGIN
+	ReferrerPolicy        string        `yaml:"referrer_policy" toml:"referrer_policy" env:"SECURITY_REFERRER_POLICY" default:"strict-origin-when-cross-origin" validate:"required"`                                                                                                                                                                   // Referrer-Policy
+	ContentSecurityPolicy string        `yaml:"csp" toml:"csp" env:"SECURITY_CSP" default:"default-src 'none'; frame-ancestors 'none'"`                                                                                                                                                                                                                // CSP ของ API (response เป็น JSON จึงไม่ต้องโหลดอะไร)
+	SwaggerCSP            string        `yaml:"swagger_csp" toml:"swagger_csp" env:"SECURITY_SWAGGER_CSP" default:"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; font-src 'self' https://fonts.gstatic.com; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"` // CSP ของ /swagger ที่ยังให้ Swagger UI ทำงานได้
+	CSPReportOnly         bool          `yaml:"csp_report_only" toml:"csp_report_only" env:"SECURITY_CSP_REPORT_ONLY" default:"false"`                                                                                                                                                                                                                 // ส่งเป็น Content-Security-Policy-Report-Only เพื่อทดสอบก่อนบังคับใช้
+}
 
-	return config
+// TLSConfig struct เก็บการตั้งค่า HTTPS
+// เมื่อกำหนด TLS_CERT_FILE และ TLS_KEY_FILE เซิร์ฟเวอร์จะให้บริการผ่าน HTTPS บน PORT
+// ไฟล์ใบรับรองที่ถูกแทนที่ (เช่น ต่ออายุด้วย certbot) จะถูกโหลดใหม่อัตโนมัติโดยไม่ต้อง restart
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE" validate:"required_with=KeyFile"`             // ไฟล์ใบรับรอง (PEM, รวม intermediate chain)
+	KeyFile      string `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE" validate:"required_with=CertFile"`               // ไฟล์ private key (PEM)
+	MinVersion   string `yaml:"min_version" toml:"min_version" env:"TLS_MIN_VERSION" default:"1.2" validate:"oneof=1.2 1.3"` // เวอร์ชัน TLS ต่ำสุดที่ยอมรับ: 1.2 หรือ 1.3
+	RedirectPort string `yaml:"redirect_port" toml:"redirect_port" env:"TLS_REDIRECT_PORT" validate:"omitempty,numeric"`     // พอร์ต HTTP ที่ redirect ทุก request ไปยัง HTTPS (ว่าง = ปิด)
 }
 
-// ConnectDB method สำหรับสร้างการเชื่อมต่อกับฐานข้อมูล MySQL
-// ใช้กับ DatabaseConfig struct
-func (d *DatabaseConfig) ConnectDB() {
-	// สร้าง DSN (Data Source Name) string สำหรับเชื่อมต่อ MySQL
-	// รูปแบบ: user:password@tcp(host:port)/database?parameters
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		d.User,     // ชื่อผู้ใช้
-		d.Password, // รหัสผ่าน
-		d.Host,     // ที่อยู่เซิร์ฟเวอร์
-		d.Port,     // พอร์ต
-		d.DBName)   // ชื่อฐานข้อมูล
-
-	// พารามิเตอร์เพิ่มเติม:
-	// charset=utf8mb4: รองรับการเข้ารหัส UTF-8 แบบเต็ม (รวม emoji)
-	// parseTime=True: แปลง MySQL time/date เป็น Go time.Time อัตโนมัติ
-	// loc=Local: ใช้ timezone ของเครื่องท้องถิ่น
-
-	// เชื่อมต่อกับฐานข้อมูลโดยใช้ sqlx library
-	db, err := sqlx.Connect("mysql", dsn)
-	if err != nil {
-		// หากเชื่อมต่อไม่สำเร็จ จะหยุดการทำงานของโปรแกรมทันที
-		log.Fatal("ไม่สามารถเชื่อมต่อกับฐานข้อมูล/Failed to connect to database:", err)
-	}
+// Enabled คืนค่า true เมื่อกำหนดใบรับรองไว้ (ให้บริการผ่าน HTTPS)
+func (t *TLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// OAuthConfig struct เก็บการตั้งค่าการเข้าสู่ระบบผ่านผู้ให้บริการภายนอก
+// provider จะเปิดใช้งานเมื่อกำหนด CLIENT_ID ไว้ และ endpoint ทั้งหมดเปลี่ยนได้
+// เพื่อให้ใช้ mock OIDC server แทนผู้ให้บริการจริงระหว่างทดสอบ
+type OAuthConfig struct {
+	RedirectBaseURL string        `yaml:"redirect_base_url" toml:"redirect_base_url" env:"OAUTH_REDIRECT_BASE_URL" default:"http://localhost:8080" validate:"required,url"` // URL ภายนอกของเซิร์ฟเวอร์ ใช้สร้าง callback: <base>/api/v1/auth/oauth/<provider>/callback
+	StateTTL        time.Duration `yaml:"state_ttl" toml:"state_ttl" env:"OAUTH_STATE_TTL" default:"10m" validate:"min=1m"`                                                 // เวลาที่ผู้ใช้มีเพื่อเข้าสู่ระบบที่ provider ให้เสร็จ (อายุของ state/nonce)
 
-	// ตั้งค่า Connection Pool เพื่อจัดการการเชื่อมต่อแบบมีประสิทธิภาพ
-	db.SetMaxOpenConns(25)                 // จำนวนการเชื่อมต่อสูงสุดที่เปิดพร้อมกัน
-	db.SetMaxIdleConns(5)                  // จำนวนการเชื่อมต่อที่เก็บไว้ในสถานะรอ
-	db.SetConnMaxLifetime(5 * time.Minute) // ระยะเวลาที่การเชื่อมต่อหนึ่งจะมีชีวิตอยู่สูงสุด
+	Google OAuthGoogleConfig `yaml:"google" toml:"google"` // Google หรือ OIDC provider ใดก็ได้ที่รองรับ discovery
+	GitHub OAuthGitHubConfig `yaml:"github" toml:"github"` // GitHub (OAuth2 + REST API)
+}
+
+// OAuthGoogleConfig struct เก็บการตั้งค่า OIDC provider (ค่าเริ่มต้นเป็น Google)
+// endpoint อ่านจาก <issuer>/.well-known/openid-configuration
+type OAuthGoogleConfig struct {
+	ClientID     string   `yaml:"client_id" toml:"client_id" env:"OAUTH_GOOGLE_CLIENT_ID"`                                                             // client ID (ว่าง = ปิดการใช้งาน)
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret" env:"OAUTH_GOOGLE_CLIENT_SECRET" validate:"required_with=ClientID" secret:"true"` // client secret
+	Issuer       string   `yaml:"issuer" toml:"issuer" env:"OAUTH_GOOGLE_ISSUER" default:"https://accounts.google.com" validate:"required,url"`        // issuer ของ OIDC (เปลี่ยนเป็น mock server ได้)
+	Scopes       []string `yaml:"scopes" toml:"scopes" env:"OAUTH_GOOGLE_SCOPES" default:"openid,email,profile"`                                       // scope ที่ขอ (ต้องมี openid และ email)
+}
 
-	// เก็บการเชื่อมต่อไว้ใน struct เพื่อใช้งานต่อไป
-	d.DB = db
+// OAuthGitHubConfig struct เก็บการตั้งค่า GitHub OAuth App
+// GitHub ไม่รองรับ OIDC สำหรับผู้ใช้ จึงอ่านข้อมูลผู้ใช้และอีเมลที่ยืนยันแล้วจาก REST API
+type OAuthGitHubConfig struct {
+	ClientID     string   `yaml:"client_id" toml:"client_id" env:"OAUTH_GITHUB_CLIENT_ID"`                                                                               // client ID (ว่าง = ปิดการใช้งาน)
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret" env:"OAUTH_GITHUB_CLIENT_SECRET" validate:"required_with=ClientID" secret:"true"`                   // client secret
+	AuthURL      string   `yaml:"auth_url" toml:"auth_url" env:"OAUTH_GITHUB_AUTH_URL" default:"https://github.com/login/oauth/authorize" validate:"required,url"`       // authorization endpoint
+	TokenURL     string   `yaml:"token_url" toml:"token_url" env:"OAUTH_GITHUB_TOKEN_URL" default:"https://github.com/login/oauth/access_token" validate:"required,url"` // token endpoint
+	APIURL       string   `yaml:"api_url" toml:"api_url" env:"OAUTH_GITHUB_API_URL" default:"https://api.github.com" validate:"required,url"`                            // REST API สำหรับอ่าน /user และ /user/emails
+	Scopes       []string `yaml:"scopes" toml:"scopes" env:"OAUTH_GITHUB_SCOPES" default:"read:user,user:email"`                                                         // scope ที่ขอ
+}
+
+// AuthServerConfig struct เก็บการตั้งค่า OAuth2 authorization server
+// access token เป็น JWT ที่เซ็นด้วย JWT_SECRET เช่นเดียวกับการเข้าสู่ระบบปกติ จึงควรมีอายุสั้น
+// และให้ resource server ภายนอกตรวจ token ผ่าน introspection endpoint
+type AuthServerConfig struct {
+	Issuer           string        `yaml:"issuer" toml:"issuer" env:"OAUTH_SERVER_ISSUER" default:"http://localhost:8080" validate:"required,url"`           // URL ของ authorization server (ใช้ใน discovery และ claim iss)
+	AccessTokenTTL   time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl" env:"OAUTH_SERVER_ACCESS_TOKEN_TTL" default:"15m" validate:"min=1m"`     // อายุของ access token
+	RefreshTokenTTL  time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl" env:"OAUTH_SERVER_REFRESH_TOKEN_TTL" default:"720h" validate:"min=1h"` // อายุของ refresh token (ได้ token ใหม่ทุกครั้งที่ใช้)
+	CodeTTL          time.Duration `yaml:"code_ttl" toml:"code_ttl" env:"OAUTH_SERVER_CODE_TTL" default:"5m" validate:"min=10s,max=10m"`                     // อายุของ authorization code (ใช้ได้ครั้งเดียว)
+	AuthorizationURL string        `yaml:"authorization_url" toml:"authorization_url" env:"OAUTH_SERVER_AUTHORIZATION_URL" validate:"omitempty,url"`         // หน้าเข้าสู่ระบบ/ขออนุญาตของ frontend ที่ประกาศใน discovery (ว่าง = /api/v1/oauth/authorize)
+}
 
-	// แสดงข้อความยืนยันการเชื่อมต่อสำเร็จ
-	log.Println("Database connected successfully")
-	log.Println("ฐานข้อมูลเชื่อมต่อสำเร็จแล้ว")
+// SessionConfig struct เก็บการตั้งค่าการเข้าสู่ระบบแบบ cookie สำหรับ browser
+// โหมด header (ค่าเริ่มต้น) ส่ง token ใน response ให้ client แนบ Authorization: Bearer เอง
+// โหมด cookie เก็บ token ใน cookie แบบ HttpOnly (JavaScript อ่านไม่ได้) และไม่ส่ง token ใน response
+// โหมด both ทำทั้งสองอย่าง สำหรับ deployment ที่มีทั้ง web และ mobile client
+// request ที่ยืนยันตัวตนด้วย cookie ต้องส่ง CSRF token (double-submit) ใน header สำหรับ method ที่แก้ไขข้อมูล
+type SessionConfig struct {
+	Mode           string `yaml:"mode" toml:"mode" env:"SESSION_MODE" default:"header" validate:"oneof=header cookie both"`                            // header, cookie หรือ both
+	CookieName     string `yaml:"cookie_name" toml:"cookie_name" env:"SESSION_COOKIE_NAME" default:"gt_session" validate:"required"`                   // ชื่อ cookie ที่เก็บ token
+	CookieDomain   string `yaml:"cookie_domain" toml:"cookie_domain" env:"SESSION_COOKIE_DOMAIN"`                                                      // domain ของ cookie (ว่าง = เฉพาะ host ที่ตอบกลับ)
+	CookiePath     string `yaml:"cookie_path" toml:"cookie_path" env:"SESSION_COOKIE_PATH" default:"/" validate:"required"`                            // path ของ cookie
+	CookieSecure   bool   `yaml:"cookie_secure" toml:"cookie_secure" env:"SESSION_COOKIE_SECURE" default:"true"`                                       // ส่ง cookie ผ่าน HTTPS เท่านั้น (ปิดได้เฉพาะตอนพัฒนาบน http://localhost)
+	CookieSameSite string `yaml:"cookie_samesite" toml:"cookie_samesite" env:"SESSION_COOKIE_SAMESITE" default:"Lax" validate:"oneof=Strict Lax None"` // SameSite ของ cookie (None ต้องใช้คู่กับ Secure)
+	CSRFCookieName string `yaml:"csrf_cookie_name" toml:"csrf_cookie_name" env:"SESSION_CSRF_COOKIE_NAME" default:"gt_csrf" validate:"required"`       // cookie ของ CSRF token (JavaScript อ่านได้)
+	CSRFHeader     string `yaml:"csrf_header" toml:"csrf_header" env:"SESSION_CSRF_HEADER" default:"X-CSRF-Token" validate:"required"`                 // header ที่ต้องส่ง CSRF token กลับมา
 }
 
-// getEnv ฟังก์ชันช่วยสำหรับอ่านค่าจากตัวแปร environment
-// หากไม่พบค่าที่ต้องการ จะคืนค่า default ที่กำหนดไว้
-func getEnv(key, defaultValue string) string {
-	// ตรวจสอบว่ามีตัวแปร environment ชื่อ 'key' หรือไม่
-	if value := os.Getenv(key); value != "" {
-		return value // หากมีค่า ให้คืนค่าที่พบ
+// CookieEnabled คืนค่า true เมื่อเข้าสู่ระบบแล้วตั้ง cookie และ middleware ยอมรับ token จาก cookie
+func (s *SessionConfig) CookieEnabled() bool {
+	return s.Mode == "cookie" || s.Mode == "both"
+}
+
+// ReturnsToken คืนค่า true เมื่อส่ง token กลับใน response ของการเข้าสู่ระบบ
+func (s *SessionConfig) ReturnsToken() bool {
+	return s.Mode != "cookie"
+}
+
+// PasswordConfig struct เก็บนโยบายรหัสผ่านที่ใช้ตอนลงทะเบียนและเปลี่ยนรหัสผ่าน (ไม่มีผลกับการเข้าสู่ระบบด้วยรหัสผ่านเดิม)
+// และอัลกอริธึมสำหรับ hash รหัสผ่านใหม่ hash ที่ใช้อัลกอริธึมหรือพารามิเตอร์เก่าจะถูก hash ใหม่เมื่อผู้ใช้เข้าสู่ระบบสำเร็จ
+// ความยาวสูงสุดถูกจำกัดที่ 72 bytes เสมอ เพราะ bcrypt ใช้เฉพาะ 72 bytes แรก
+// ใช้คำสั่ง `password benchmark` เพื่อวัดเวลาของพารามิเตอร์แต่ละชุดบนเครื่องที่ใช้งานจริง
+type PasswordConfig struct {
+	MinLength         int    `yaml:"min_length" toml:"min_length" env:"PASSWORD_MIN_LENGTH" default:"8" validate:"min=1,max=72"`                          // ความยาวขั้นต่ำ (ตัวอักษร)
+	RequireUpper      bool   `yaml:"require_upper" toml:"require_upper" env:"PASSWORD_REQUIRE_UPPER" default:"true"`                                      // ต้องมีตัวพิมพ์ใหญ่อย่างน้อยหนึ่งตัว
+	RequireLower      bool   `yaml:"require_lower" toml:"require_lower" env:"PASSWORD_REQUIRE_LOWER" default:"true"`                                      // ต้องมีตัวพิมพ์เล็กอย่างน้อยหนึ่งตัว
+	RequireDigit      bool   `yaml:"require_digit" toml:"require_digit" env:"PASSWORD_REQUIRE_DIGIT" default:"true"`                                      // ต้องมีตัวเลขอย่างน้อยหนึ่งตัว
+	RequireSymbol     bool   `yaml:"require_symbol" toml:"require_symbol" env:"PASSWORD_REQUIRE_SYMBOL" default:"false"`                                  // ต้องมีสัญลักษณ์อย่างน้อยหนึ่งตัว
+	DenyUserInfo      bool   `yaml:"deny_user_info" toml:"deny_user_info" env:"PASSWORD_DENY_USER_INFO" default:"true"`                                   // ห้ามมีชื่อผู้ใช้หรืออีเมลอยู่ในรหัสผ่าน
+	History           int    `yaml:"history" toml:"history" env:"PASSWORD_HISTORY" default:"5" validate:"min=0,max=24"`                                   // ห้ามใช้ซ้ำกับรหัสผ่าน N ครั้งล่าสุด (0 = ไม่ตรวจ)
+	BreachedFile      string `yaml:"breached_file" toml:"breached_file" env:"PASSWORD_BREACHED_FILE" validate:"omitempty,file"`                           // ไฟล์ SHA-1 ของรหัสผ่านที่รั่วไหล (ว่าง = ไม่ตรวจ)
+	Algorithm         string `yaml:"algorithm" toml:"algorithm" env:"PASSWORD_ALGORITHM" default:"argon2id" validate:"oneof=argon2id bcrypt"`             // อัลกอริธึมสำหรับ hash รหัสผ่านใหม่
+	BcryptCost        int    `yaml:"bcrypt_cost" toml:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" default:"12" validate:"min=4,max=31"`                      // cost ของ bcrypt (ยิ่งสูงยิ่งช้า เพิ่ม 1 = ช้าลงสองเท่า)
+	Argon2Memory      int    `yaml:"argon2_memory" toml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" default:"47104" validate:"min=1024,max=4194304"`     // หน่วยความจำของ argon2id ต่อการ hash หนึ่งครั้ง (KiB)
+	Argon2Iterations  int    `yaml:"argon2_iterations" toml:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS" default:"2" validate:"min=1,max=100"`    // จำนวนรอบของ argon2id
+	Argon2Parallelism int    `yaml:"argon2_parallelism" toml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM" default:"1" validate:"min=1,max=255"` // จำนวน thread ของ argon2id
+}
+
+// RegistrationConfig struct เก็บการตั้งค่าการสร้างบัญชีผู้ใช้
+// ปิด REGISTRATION_ENABLED เพื่อให้สร้างบัญชีได้ผ่านคำเชิญของ Admin เท่านั้น
+// (รวมถึงการสร้างบัญชีใหม่จากการเข้าสู่ระบบผ่าน Google/GitHub ส่วนบัญชีที่มีอยู่แล้วยังเชื่อมได้ตามปกติ)
+type RegistrationConfig struct {
+	Enabled   bool          `yaml:"enabled" toml:"enabled" env:"REGISTRATION_ENABLED" default:"true"`                       // เปิดให้ลงทะเบียนด้วยตัวเองผ่าน /auth/register
+	InviteTTL time.Duration `yaml:"invite_ttl" toml:"invite_ttl" env:"INVITE_TTL" default:"72h" validate:"min=1h,max=720h"` // อายุของ token คำเชิญ (นับใหม่ทุกครั้งที่ส่งซ้ำ)
+	InviteURL string        `yaml:"invite_url" toml:"invite_url" env:"INVITE_URL" validate:"omitempty,url"`                 // หน้ารับคำเชิญของ frontend ลิงก์ในอีเมลเป็น <url>?token=<token> (ว่าง = ส่งเฉพาะ token)
+}
+
+// MailConfig struct เก็บการตั้งค่าการส่งอีเมล
+// driver log เขียนอีเมลลง log ของเซิร์ฟเวอร์แทนการส่งจริง (สำหรับการพัฒนา เพราะ log จะมี token คำเชิญ)
+// driver smtp ส่งผ่าน SMTP server ใช้ STARTTLS เมื่อ server รองรับ หรือ TLS ตั้งแต่ต้นเมื่อใช้พอร์ต 465
+type MailConfig struct {
+	Driver       string `yaml:"driver" toml:"driver" env:"MAIL_DRIVER" default:"log" validate:"oneof=log smtp"`                                  // log หรือ smtp
+	From         string `yaml:"from" toml:"from" env:"MAIL_FROM" default:"GoTemplate <no-reply@localhost>" validate:"required"`                  // ผู้ส่ง เช่น "GoTemplate <no-reply@example.com>"
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"MAIL_SMTP_HOST" validate:"required_if=Driver smtp"`                              // ที่อยู่ของ SMTP server
+	SMTPPort     string `yaml:"smtp_port" toml:"smtp_port" env:"MAIL_SMTP_PORT" default:"587" validate:"numeric"`                                // พอร์ตของ SMTP server (587 = STARTTLS, 465 = TLS)
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username" env:"MAIL_SMTP_USERNAME"`                                                     // ชื่อผู้ใช้สำหรับ SMTP AUTH (ว่าง = ไม่ยืนยันตัวตน)
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"MAIL_SMTP_PASSWORD" validate:"required_with=SMTPUsername" secret:"true"` // รหัสผ่านสำหรับ SMTP AUTH
+}
+
+// TenantConfig struct เก็บวิธีเลือกองค์กร (tenant) ของ request
+// ลำดับ: header TENANT_HEADER → subdomain ของ TENANT_BASE_DOMAIN → องค์กรใน token (claim tid)
+// ผู้ใช้ที่ไม่ใช่ Admin ของระบบต้องเป็นสมาชิกขององค์กร และเปลี่ยนไปองค์กรอื่นจาก token ไม่ได้
+type TenantConfig struct {
+	Header     string `yaml:"header" toml:"header" env:"TENANT_HEADER" default:"X-Tenant" validate:"required"`   // header ที่ระบุ slug ขององค์กร
+	BaseDomain string `yaml:"base_domain" toml:"base_domain" env:"TENANT_BASE_DOMAIN" validate:"omitempty,fqdn"` // โดเมนหลัก เช่น example.com แล้ว acme.example.com = องค์กร acme (ว่าง = ไม่ใช้ subdomain)
+}
+
+// StorageConfig struct เก็บการตั้งค่าที่เก็บไฟล์ (blob storage) ของไฟล์ที่อัปโหลด
+// driver local เขียนไฟล์ลง STORAGE_LOCAL_DIR และให้บริการไฟล์ที่ STORAGE_PUBLIC_URL (เมื่อเป็น path เช่น /uploads)
+// หรือกำหนด STORAGE_PUBLIC_URL เป็น URL เต็มของ CDN/web server ที่ให้บริการโฟลเดอร์เดียวกัน
+type StorageConfig struct {
+	Driver    string `yaml:"driver" toml:"driver" env:"STORAGE_DRIVER" default:"local" validate:"oneof=local"`             // ชนิดที่เก็บไฟล์ (ปัจจุบันรองรับ local)
+	LocalDir  string `yaml:"local_dir" toml:"local_dir" env:"STORAGE_LOCAL_DIR" default:"uploads" validate:"required"`     // โฟลเดอร์เก็บไฟล์ของ driver local
+	PublicURL string `yaml:"public_url" toml:"public_url" env:"STORAGE_PUBLIC_URL" default:"/uploads" validate:"required"` // URL หรือ path ที่ใช้สร้างลิงก์ของไฟล์ (เช่น /uploads หรือ https://cdn.example.com)
+}
+
+// AvatarConfig struct เก็บข้อจำกัดของรูปโปรไฟล์ที่อัปโหลดผ่าน /auth/profile/avatar
+// รูปถูกตัดเป็นสี่เหลี่ยมจัตุรัสตรงกลางและย่อเป็น thumbnail ทุกขนาดใน AVATAR_SIZES (ขนาดแรกคือรูปหลักของ avatar_url)
+type AvatarConfig struct {
+	MaxSize      int      `yaml:"max_size" toml:"max_size" env:"AVATAR_MAX_SIZE" default:"2097152" validate:"min=1024,max=4194304"`                                                                  // ขนาดไฟล์สูงสุด (bytes) ไม่เกิน 4 MiB ซึ่งเป็น body limit ของเซิร์ฟเวอร์
+	AllowedTypes []string `yaml:"allowed_types" toml:"allowed_types" env:"AVATAR_ALLOWED_TYPES" default:"image/jpeg,image/png,image/gif" validate:"min=1,dive,oneof=image/jpeg image/png image/gif"` // ชนิดไฟล์ที่รับ (ตรวจจากเนื้อหาไฟล์ ไม่ใช่ Content-Type ที่ client ส่งมา)
+	Sizes        []string `yaml:"sizes" toml:"sizes" env:"AVATAR_SIZES" default:"256,64" validate:"min=1,dive,avatar_size"`                                                                          // ขนาด thumbnail (pixel ด้านละ 16-1024)
+}
+
+// EventsConfig struct เก็บการตั้งค่าของ worker ที่ส่ง domain event จาก outbox (ตาราง event_outbox) ให้ subscriber ในโปรเซส
+// event ที่ subscriber บางตัวล้มเหลวถูกส่งซ้ำเฉพาะ subscriber นั้นหลัง EVENTS_BACKOFF × 2^(ครั้งที่-1) ไม่เกิน EVENTS_BACKOFF_MAX
+type EventsConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"EVENTS_POLL_INTERVAL" default:"1s" validate:"min=100ms,max=1m"` // ความถี่ที่ worker ตรวจ outbox
+	BatchSize    int           `yaml:"batch_size" toml:"batch_size" env:"EVENTS_BATCH_SIZE" default:"100" validate:"min=1,max=1000"`           // จำนวน event สูงสุดต่อรอบ
+	MaxAttempts  int           `yaml:"max_attempts" toml:"max_attempts" env:"EVENTS_MAX_ATTEMPTS" default:"10" validate:"min=1,max=50"`        // จำนวนรอบที่ส่งก่อนเปลี่ยนเป็น failed
+	Backoff      time.Duration `yaml:"backoff" toml:"backoff" env:"EVENTS_BACKOFF" default:"5s" validate:"min=100ms"`                          // เวลารอก่อนส่งซ้ำครั้งแรก (เพิ่มเป็นสองเท่าทุกครั้ง)
+	BackoffMax   time.Duration `yaml:"backoff_max" toml:"backoff_max" env:"EVENTS_BACKOFF_MAX" default:"1h" validate:"gtefield=Backoff"`       // เวลารอสูงสุดระหว่างการส่งซ้ำ
+}
+
+// WebhookConfig struct เก็บการตั้งค่าของ worker ที่ส่ง webhook จาก outbox (ตาราง webhook_deliveries)
+// การส่งที่ไม่สำเร็จ (ไม่ตอบ 2xx หรือเกิน WEBHOOK_TIMEOUT) ส่งซ้ำหลัง WEBHOOK_BACKOFF × 2^(ครั้งที่-1) ไม่เกิน WEBHOOK_BACKOFF_MAX
+type WebhookConfig struct {
+	Enabled      bool          `yaml:"enabled" toml:"enabled" env:"WEBHOOK_ENABLED" default:"true"`                                             // เปิด worker ส่ง webhook ใน instance นี้ (event ยังถูกบันทึกใน outbox เสมอ)
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL" default:"5s" validate:"min=100ms,max=1m"` // ความถี่ที่ worker ตรวจ outbox
+	BatchSize    int           `yaml:"batch_size" toml:"batch_size" env:"WEBHOOK_BATCH_SIZE" default:"20" validate:"min=1,max=500"`             // จำนวนการส่งสูงสุดต่อรอบ
+	Timeout      time.Duration `yaml:"timeout" toml:"timeout" env:"WEBHOOK_TIMEOUT" default:"10s" validate:"min=1s,max=1m"`                     // เวลารอคำตอบของปลายทาง
+	MaxAttempts  int           `yaml:"max_attempts" toml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" default:"8" validate:"min=1,max=20"`         // จำนวนครั้งที่ส่งก่อนเปลี่ยนเป็น failed
+	Backoff      time.Duration `yaml:"backoff" toml:"backoff" env:"WEBHOOK_BACKOFF" default:"30s" validate:"min=1s"`                            // เวลารอก่อนส่งซ้ำครั้งแรก (เพิ่มเป็นสองเท่าทุกครั้ง)
+	BackoffMax   time.Duration `yaml:"backoff_max" toml:"backoff_max" env:"WEBHOOK_BACKOFF_MAX" default:"6h" validate:"gtefield=Backoff"`       // เวลารอสูงสุดระหว่างการส่งซ้ำ
+	AllowHTTP    bool          `yaml:"allow_http" toml:"allow_http" env:"WEBHOOK_ALLOW_HTTP" default:"false"`                                   // อนุญาต URL แบบ http (ไม่เข้ารหัส) นอก development
+}
+
+// ThumbnailSizes คืนค่าขนาด thumbnail จาก AVATAR_SIZES เป็นตัวเลข (ตรวจรูปแบบแล้วตอนโหลดการตั้งค่า)
+func (a *AvatarConfig) ThumbnailSizes() []int {
+	sizes := make([]int, 0, len(a.Sizes))
+	for _, size := range a.Sizes {
+		if n, err := strconv.Atoi(strings.TrimSpace(size)); err == nil {
+			sizes = append(sizes, n)
+		}
 	}
-	return defaultValue // หากไม่มีค่า ให้คืนค่า default
+	return sizes
 }
 
-// parseDuration ฟังก์ชันช่วยสำหรับแปลง string เป็น time.Duration
-// ใช้สำหรับการตั้งค่าระยะเวลาหมดอายุของ JWT token
-func parseDuration(s string) time.Duration {
-	// พยายามแปลง string เป็น Duration (เช่น "24h", "30m", "1h30m")
-	duration, err := time.ParseDuration(s)
+// LoadConfig ฟังก์ชันหลักสำหรับโหลดการตั้งค่าทั้งหมด
+// จะโหลดค่าจากทุกแหล่งด้วย Load(os.Args[1:]) และตรวจสอบความถูกต้อง
+// ไม่มีการเชื่อมต่อฐานข้อมูลในขั้นตอนนี้ (ดู database.Connect)
+// หากการตั้งค่าไม่ถูกต้อง (เช่น JWT_EXPIRE พิมพ์ผิด หรือใช้ secret เริ่มต้นใน production)
+// จะหยุดการทำงานทันทีแทนการใช้ค่า default แบบเงียบๆ
+func LoadConfig() *Config {
+	config, err := Load(os.Args[1:])
 	if err != nil {
-		// หากแปลงไม่สำเร็จ ให้คืนค่า default เป็น 24 ชั่วโมง
-		return 24 * time.Hour
+		log.Fatal("การตั้งค่าไม่ถูกต้อง/Invalid configuration: ", err)
 	}
-	return duration // หากแปลงสำเร็จ ให้คืนค่าที่แปลงได้
+	return config
 }
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load ฟังก์ชันสำหรับโหลดการตั้งค่าจากทุกแหล่งโดยไม่เชื่อมต่อฐานข้อมูล
+// ลำดับความสำคัญ (แหล่งหลังทับแหล่งก่อน):
+//  1. ค่า default จาก struct tag `default`
+//  2. ไฟล์ YAML หรือ TOML ที่ระบุด้วย --config หรือ CONFIG_FILE
+//  3. ไฟล์ .env (หรือไฟล์ที่ระบุด้วย --env-file)
+//  4. environment variables ของ process
+//  5. CLI flags เช่น --port=9090 --jwt-expire=1h
+//
+// ค่าที่แปลงไม่ได้ (เช่น JWT_EXPIRE=24hh) จะคืนค่า error แทนการใช้ค่า default
+func Load(args []string) (*Config, error) {
+	config := newConfig()
+
+	// 1. ค่า default
+	if err := applyDefaults(config); err != nil {
+		return nil, err
+	}
+
+	// แยก CLI flags ก่อน เพื่อให้รู้ตำแหน่งไฟล์ config และ .env
+	flags, err := parseFlags(config, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. ไฟล์ YAML/TOML
+	if configFile := flags.configPath(); configFile != "" {
+		if err := loadFile(config, configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. ไฟล์ .env - อ่านเป็น map โดยไม่เขียนลง environment ของ process
+	// เพื่อให้การ reload อ่านค่าใหม่จากไฟล์ได้ และ environment variables จริงยังมีลำดับสูงกว่า
+	dotenv, err := godotenv.Read(flags.envFile)
+	if err != nil {
+		if flags.envFileSet {
+			return nil, fmt.Errorf("ไม่สามารถอ่านไฟล์ %s ได้: %w", flags.envFile, err)
+		}
+		log.Println("ไม่พบไฟล์ .env / No .env file found")
+	}
+	if err := applyValues(config, dotenv, ".env"); err != nil {
+		return nil, err
+	}
+
+	// 4. environment variables
+	if err := applyEnv(config); err != nil {
+		return nil, err
+	}
+
+	// 5. CLI flags
+	if err := applyValues(config, flags.values, "flag"); err != nil {
+		return nil, err
+	}
+
+	if err := Validate(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// newConfig สร้าง Config เปล่าที่จอง struct ย่อยทุกส่วนไว้แล้ว
+// เพื่อให้ decoder ของ YAML/TOML เขียนทับเฉพาะ key ที่มีอยู่ในไฟล์
+func newConfig() *Config {
+	config := &Config{}
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+	}
+	return config
+}
+
+// cliFlags ผลลัพธ์จากการแยก CLI flags
+type cliFlags struct {
+	configFile string            // ไฟล์ YAML/TOML จาก --config
+	envFile    string            // ไฟล์ .env จาก --env-file
+	envFileSet bool              // ผู้ใช้ระบุ --env-file เองหรือไม่ (ถ้าระบุแล้วไม่พบไฟล์ถือเป็น error)
+	values     map[string]string // ค่าที่ได้จาก flag อื่นๆ โดยใช้ชื่อ env เป็น key
+}
+
+// configPath คืนค่า path ของไฟล์ YAML/TOML จาก --config หรือ CONFIG_FILE
+func (f *cliFlags) configPath() string {
+	if f.configFile != "" {
+		return f.configFile
+	}
+	return os.Getenv("CONFIG_FILE")
+}
+
+// sourceFiles คืนค่า path ของไฟล์การตั้งค่าที่ args อ้างถึง (ไฟล์ YAML/TOML และ .env)
+// ใช้โดย Store.Watch เพื่อเฝ้าดูการเปลี่ยนแปลงของไฟล์
+func sourceFiles(args []string) ([]string, error) {
+	flags, err := parseFlags(newConfig(), args)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{flags.envFile}
+	if configFile := flags.configPath(); configFile != "" {
+		files = append(files, configFile)
+	}
+	return files, nil
+}
+
+// parseFlags สร้าง flag ให้กับทุก field ที่มี tag `env` แล้วแยกค่าจาก args
+// ชื่อ flag สร้างจากชื่อ env เช่น DB_HOST → --db-host, JWT_EXPIRE → --jwt-expire
+func parseFlags(config *Config, args []string) (*cliFlags, error) {
+	result := &cliFlags{envFile: ".env", values: map[string]string{}}
+
+	fs := flag.NewFlagSet("gotemplate", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&result.configFile, "config", "", "path to a YAML or TOML config file")
+	fs.Func("env-file", "path to a .env file", func(value string) error {
+		result.envFile = value
+		result.envFileSet = true
+		return nil
+	})
+
+	walkFields(config, func(field reflect.StructField, _ reflect.Value) {
+		env := field.Tag.Get("env")
+		if env == "" {
+			return
+		}
+		fs.Func(flagName(env), "overrides "+env, func(value string) error {
+			result.values[env] = value
+			return nil
+		})
+	})
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("CLI flag ไม่ถูกต้อง: %w", err)
+	}
+	return result, nil
+}
+
+// flagName แปลงชื่อ env เป็นชื่อ flag เช่น DB_HOST → db-host
+func flagName(env string) string {
+	return strings.ToLower(strings.ReplaceAll(env, "_", "-"))
+}
+
+// loadFile อ่านไฟล์การตั้งค่าตามนามสกุล (.yaml, .yml, .toml)
+func loadFile(config *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถอ่านไฟล์การตั้งค่า %s ได้: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+		decoder.KnownFields(true) // key ที่สะกดผิดต้องเป็น error ไม่ใช่ถูกละเลย
+		if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("ไฟล์ YAML %s ไม่ถูกต้อง: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), config)
+		if err != nil {
+			return fmt.Errorf("ไฟล์ TOML %s ไม่ถูกต้อง: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("ไฟล์ TOML %s มี key ที่ไม่รู้จัก: %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("ไม่รองรับไฟล์การตั้งค่านามสกุล %q (รองรับ .yaml, .yml, .toml)", filepath.Ext(path))
+	}
+	return nil
+}
+
+// applyDefaults กำหนดค่าเริ่มต้นจาก struct tag `default`
+func applyDefaults(config *Config) error {
+	var errs []error
+	walkFields(config, func(field reflect.StructField, value reflect.Value) {
+		def, ok := field.Tag.Lookup("default")
+		if !ok {
+			return
+		}
+		if err := setValue(value, def); err != nil {
+			errs = append(errs, fmt.Errorf("default ของ %s: %w", field.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// applyEnv อ่านค่าจาก environment variables ตาม struct tag `env`
+// ตัวแปรที่ไม่มีหรือเป็นค่าว่างจะไม่ทับค่าจากแหล่งก่อนหน้า
+func applyEnv(config *Config) error {
+	values := map[string]string{}
+	walkFields(config, func(field reflect.StructField, _ reflect.Value) {
+		env := field.Tag.Get("env")
+		if value := os.Getenv(env); env != "" && value != "" {
+			values[env] = value
+		}
+	})
+	return applyValues(config, values, "env")
+}
+
+// applyValues เขียนค่าที่เป็น string ลงใน field ที่มีชื่อ env ตรงกัน
+// source ใช้ระบุแหล่งที่มาในข้อความ error เช่น "env" หรือ "flag"
+func applyValues(config *Config, values map[string]string, source string) error {
+	var errs []error
+	walkFields(config, func(field reflect.StructField, value reflect.Value) {
+		env := field.Tag.Get("env")
+		raw, ok := values[env]
+		if env == "" || !ok {
+			return
+		}
+		if err := setValue(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s=%q: %w", source, env, raw, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// walkFields เรียก fn กับทุก field ในทุกส่วนของ Config (Database, JWT, Server, ...)
+func walkFields(config *Config, fn func(field reflect.StructField, value reflect.Value)) {
+	root := reflect.ValueOf(config).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Field(i)
+		if section.Kind() != reflect.Pointer || section.IsNil() || section.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		walkStruct(section.Elem(), fn)
+	}
+}
+
+// walkStruct เรียก fn กับทุก field ของ struct และลงไปใน struct ย่อยที่ไม่มี tag `env`
+func walkStruct(value reflect.Value, fn func(field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Struct && field.Tag.Get("env") == "" && field.Type != durationType {
+			walkStruct(fieldValue, fn)
+			continue
+		}
+		fn(field, fieldValue)
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue แปลง string เป็นชนิดข้อมูลของ field แล้วกำหนดค่า
+// รองรับ string, bool, int, float64, time.Duration และ []string (คั่นด้วย comma)
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("ไม่ใช่ระยะเวลาที่ถูกต้อง (ตัวอย่าง: 30m, 24h): %w", err)
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("ไม่ใช่ค่า true/false: %w", err)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("ไม่ใช่ตัวเลขจำนวนเต็ม: %w", err)
+		}
+		value.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("ไม่ใช่ตัวเลข: %w", err)
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("ไม่รองรับชนิดข้อมูล %s", value.Type())
+		}
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("ไม่รองรับชนิดข้อมูล %s", value.Type())
+	}
+	return nil
+}
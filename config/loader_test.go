@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadLayering ตรวจลำดับความสำคัญของแหล่งการตั้งค่า default → YAML/TOML → .env → env → flags
+// แต่ละ key ถูกกำหนดถึงชั้นที่ต่างกัน ค่าที่ได้ต้องมาจากชั้นสุดท้ายที่กำหนด key นั้น
+func TestLoadLayering(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+app:
+  version: "from-file"
+  description: "from-file"
+server:
+  log_level: "debug"
+  port: "7001"
+`,
+		"config.toml": `
+[app]
+version = "from-file"
+description = "from-file"
+
+[server]
+log_level = "debug"
+port = "7001"
+`,
+	}
+
+	for name, content := range files {
+		t.Run(filepath.Ext(name), func(t *testing.T) {
+			dir := t.TempDir()
+			configFile := writeFile(t, dir, name, content)
+			envFile := writeFile(t, dir, ".env", "APP_DESCRIPTION=from-dotenv\nLOG_LEVEL=warn\nPORT=7002\n")
+
+			// ชั้น env: ค่าว่างไม่ทับชั้นก่อนหน้า (ENVIRONMENT ว่างเพื่อให้ตรวจแบบ development เสมอ)
+			t.Setenv("ENVIRONMENT", "")
+			t.Setenv("APP_NAME", "")
+			t.Setenv("APP_VERSION", "")
+			t.Setenv("APP_DESCRIPTION", "")
+			t.Setenv("LOG_LEVEL", "error")
+			t.Setenv("PORT", "7003")
+
+			config, err := Load([]string{"--config", configFile, "--env-file", envFile, "--port=7004"})
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			tests := []struct {
+				key, got, want string
+			}{
+				{"APP_NAME (default)", config.App.Name, "GoTemplate API"},
+				{"APP_VERSION (file)", config.App.Version, "from-file"},
+				{"APP_DESCRIPTION (.env)", config.App.Description, "from-dotenv"},
+				{"LOG_LEVEL (env)", config.Server.LogLevel, "error"},
+				{"PORT (flag)", config.Server.Port, "7004"},
+			}
+			for _, tt := range tests {
+				if tt.got != tt.want {
+					t.Errorf("%s = %q, ต้องการ %q", tt.key, tt.got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadErrors ตรวจว่าค่าที่แปลงไม่ได้และไฟล์ที่ไม่ถูกต้องคืนค่า error ที่บอกแหล่งที่มา แทนการใช้ค่า default
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	envFile := writeFile(t, dir, ".env", "")
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{"env value", nil, map[string]string{"JWT_EXPIRE": "24hh"}, `env JWT_EXPIRE="24hh"`},
+		{"flag value", []string{"--jwt-expire=soon"}, nil, `flag JWT_EXPIRE="soon"`},
+		{".env value", []string{"--env-file", writeFile(t, dir, "bad.env", "DB_PORT=x\nJWT_EXPIRE=1\n")}, nil, `.env JWT_EXPIRE="1"`},
+		{"unknown flag", []string{"--no-such-flag=1"}, nil, "CLI flag"},
+		{"unknown YAML key", []string{"--config", writeFile(t, dir, "typo.yaml", "server:\n  prot: 1\n")}, nil, "prot"},
+		{"unknown TOML key", []string{"--config", writeFile(t, dir, "typo.toml", "[server]\nprot = 1\n")}, nil, "prot"},
+		{"unsupported file", []string{"--config", writeFile(t, dir, "config.json", "{}")}, nil, ".json"},
+		{"missing env file", []string{"--env-file", filepath.Join(dir, "missing.env")}, nil, "missing.env"},
+		{"invalid value", []string{"--log-level=verbose"}, nil, "LOG_LEVEL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if !strings.Contains(strings.Join(args, " "), "--env-file") {
+				args = append([]string{"--env-file", envFile}, args...)
+			}
+			_, err := Load(args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error = %v, ต้องการ error ที่มี %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// writeFile เขียนไฟล์ชั่วคราวสำหรับการทดสอบและคืนค่า path
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted ข้อความที่ใช้แทนค่าความลับเมื่อแสดงผล
+const redacted = "******"
+
+// Redacted สร้างสำเนาของ Config ที่ซ่อนค่าความลับทั้งหมด (field ที่มี tag `secret:"true"`)
+// ใช้สำหรับแสดงผลหรือบันทึก log โดยไม่เปิดเผยรหัสผ่านและ secret
+func (c *Config) Redacted() *Config {
+	clone := newConfig()
+	source := reflect.ValueOf(c).Elem()
+	target := reflect.ValueOf(clone).Elem()
+	for i := 0; i < source.NumField(); i++ {
+		if section := source.Field(i); !section.IsNil() {
+			target.Field(i).Elem().Set(section.Elem())
+		}
+	}
+
+	walkFields(clone, func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" && value.Kind() == reflect.String && value.String() != "" {
+			value.SetString(redacted)
+		}
+	})
+	return clone
+}
+
+// Print เขียนการตั้งค่าที่มีผลจริง (หลังรวมทุกแหล่งแล้ว) ในรูปแบบ YAML โดยซ่อนค่าความลับ
+// ใช้กับคำสั่ง `config print`
+func (c *Config) Print(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return fmt.Errorf("ไม่สามารถแสดงการตั้งค่าได้: %w", err)
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestPrintRedactsSecrets ตรวจว่า config print ซ่อนทุก field ที่มี tag `secret:"true"` โดยไม่แก้ไขการตั้งค่าต้นฉบับ
+// และค่าความลับที่ว่างยังแสดงเป็นค่าว่าง (บอกได้ว่ายังไม่ได้ตั้ง)
+func TestPrintRedactsSecrets(t *testing.T) {
+	config := defaultConfig(t)
+	secrets := map[string]*string{
+		"JWT_SECRET":                 &config.JWT.Secret,
+		"DB_PASSWORD":                &config.Database.Password,
+		"OAUTH_GOOGLE_CLIENT_SECRET": &config.OAuth.Google.ClientSecret,
+		"MAIL_SMTP_PASSWORD":         &config.Mail.SMTPPassword,
+	}
+	for name, field := range secrets {
+		*field = "value-of-" + name
+	}
+	config.OAuth.GitHub.ClientSecret = ""
+
+	var out bytes.Buffer
+	if err := config.Print(&out); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	printed := out.String()
+
+	for name, field := range secrets {
+		if strings.Contains(printed, "value-of-"+name) {
+			t.Errorf("%s ถูกแสดงในผลลัพธ์", name)
+		}
+		if *field != "value-of-"+name {
+			t.Errorf("Print แก้ไข %s ของการตั้งค่าต้นฉบับเป็น %q", name, *field)
+		}
+	}
+	if got := strings.Count(printed, redacted); got != len(secrets) {
+		t.Errorf("จำนวนค่าที่ถูกซ่อน = %d, ต้องการ %d\n%s", got, len(secrets), printed)
+	}
+	// ค่าที่ไม่ใช่ความลับยังแสดงตามจริง
+	if !strings.Contains(printed, "name: GoTemplate API") {
+		t.Errorf("ผลลัพธ์ไม่มีค่า app.name:\n%s", printed)
+	}
+
+	// ทุก field ที่มี tag secret ต้องถูกซ่อน (รวม field ที่เพิ่มในอนาคต)
+	clone := config.Redacted()
+	for name := range secretNames(config) {
+		if _, listed := secrets[name]; !listed && name != "OAUTH_GITHUB_CLIENT_SECRET" {
+			t.Errorf("field ความลับ %s ไม่อยู่ในการทดสอบนี้", name)
+		}
+	}
+	if clone.OAuth.GitHub.ClientSecret != "" {
+		t.Errorf("ค่าความลับที่ว่างถูกแสดงเป็น %q", clone.OAuth.GitHub.ClientSecret)
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Store เก็บการตั้งค่าปัจจุบันและรองรับการโหลดใหม่ขณะรัน (hot reload)
+// ผู้ใช้งานควรเรียก Get() ทุกครั้งที่ต้องการค่า แทนการเก็บ *Config ไว้เอง
+// เพื่อให้ได้ค่าล่าสุดหลังการ reload เสมอ
+type Store struct {
+	current   atomic.Pointer[Config]
+	args      []string                 // CLI args ที่ใช้โหลดการตั้งค่า (ใช้ซ้ำตอน reload)
+	mu        sync.Mutex               // ป้องกันการ reload พร้อมกันและลำดับการเรียก callback
+	listeners []func(old, new *Config) // callback ที่ถูกเรียกหลังสลับการตั้งค่าสำเร็จ
+}
+
+// NewStore สร้าง Store จากการตั้งค่าที่โหลดแล้ว และ args ที่ใช้โหลด
+func NewStore(cfg *Config, args []string) *Store {
+	store := &Store{args: args}
+	store.current.Store(cfg)
+	return store
+}
+
+// Get คืนค่าการตั้งค่าปัจจุบัน ห้ามแก้ไขค่าที่ได้รับ (ใช้ร่วมกันระหว่างหลาย goroutine)
+func (s *Store) Get() *Config {
+	return s.current.Load()
+}
+
+// OnChange ลงทะเบียน callback ที่จะถูกเรียกหลังการ reload สำเร็จทุกครั้ง
+// ใช้ให้ middleware (เช่น log level, CORS) ปรับค่าตามการตั้งค่าใหม่
+func (s *Store) OnChange(fn func(old, new *Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// Reload อ่านการตั้งค่าจากทุกแหล่งใหม่ ตรวจสอบความถูกต้อง แล้วสลับเข้าแทนที่แบบ atomic
+// หากการตั้งค่าใหม่ไม่ถูกต้องจะคืนค่า error และใช้การตั้งค่าเดิมต่อไป
+// ค่าที่มี tag `reload:"false"` (เช่น PORT, การตั้งค่าฐานข้อมูล) จะคงค่าเดิมและแสดงคำเตือนแทน
+func (s *Store) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := Load(s.args)
+	if err != nil {
+		return err
+	}
+
+	old := s.current.Load()
+	if changed := keepRestartOnly(old, next); len(changed) > 0 {
+		log.Printf("⚠️  การตั้งค่าต่อไปนี้เปลี่ยนแปลงไม่ได้ขณะรัน ต้อง restart เพื่อให้มีผล/Restart required: %s",
+			strings.Join(changed, ", "))
+	}
+
+	s.current.Store(next)
+	for _, fn := range s.listeners {
+		fn(old, next)
+	}
+	log.Println("🔄 โหลดการตั้งค่าใหม่สำเร็จ/Configuration reloaded")
+	return nil
+}
+
+// keepRestartOnly คัดลอกค่าที่ reload ไม่ได้จาก current ไปยัง next
+// และคืนค่าชื่อ env ของค่าที่ถูกเปลี่ยนในแหล่งการตั้งค่า
+func keepRestartOnly(current, next *Config) []string {
+	var changed []string
+	currentRoot := reflect.ValueOf(current).Elem()
+	nextRoot := reflect.ValueOf(next).Elem()
+	for i := 0; i < currentRoot.NumField(); i++ {
+		currentSection, nextSection := currentRoot.Field(i), nextRoot.Field(i)
+		if currentSection.IsNil() || nextSection.IsNil() {
+			continue
+		}
+		wholeSection := currentRoot.Type().Field(i).Tag.Get("reload") == "false"
+		currentSection, nextSection = currentSection.Elem(), nextSection.Elem()
+
+		for j := 0; j < currentSection.NumField(); j++ {
+			field := currentSection.Type().Field(j)
+			if !wholeSection && field.Tag.Get("reload") != "false" {
+				continue
+			}
+			if reflect.DeepEqual(currentSection.Field(j).Interface(), nextSection.Field(j).Interface()) {
+				continue
+			}
+			name := field.Tag.Get("env")
+			if name == "" {
+				name = field.Name
+			}
+			changed = append(changed, name)
+			nextSection.Field(j).Set(currentSection.Field(j))
+		}
+	}
+	return changed
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MinSecretLength ความยาวขั้นต่ำของ JWT_SECRET ใน production (32 bytes = 256 bits สำหรับ HS256)
+const MinSecretLength = 32
+
+// MinBcryptCost cost ขั้นต่ำของ bcrypt ใน production (ค่า bcrypt.DefaultCost) ค่าที่ต่ำกว่านี้ใช้เพื่อให้ test เร็วขึ้นเท่านั้น
+const MinBcryptCost = 10
+
+// MinArgon2Work ค่าขั้นต่ำของ หน่วยความจำ (KiB) x จำนวนรอบ ของ argon2id ใน production
+// ตามชุดพารามิเตอร์ขั้นต่ำที่ OWASP แนะนำ (เช่น 19 MiB x 2 รอบ หรือ 7 MiB x 5 รอบ)
+const MinArgon2Work = 7168 * 5
+
+// insecureSecrets ค่า secret ที่รู้จักกันทั่วไป (ค่า default และค่าตัวอย่างใน .env)
+// ห้ามใช้ใน production เพราะใครก็ปลอม token ได้
+var insecureSecrets = map[string]bool{
+	"default-secret":                        true,
+	"your-super-secret-jwt-key-here":        true,
+	"very-strong-secret-key-for-production": true,
+	"secret":                                true,
+	"changeme":                              true,
+}
+
+// Validate ตรวจสอบความถูกต้องของการตั้งค่าทั้งหมด
+// - ตรวจตามกฎใน struct tag `validate` (ชื่อ field ในข้อความ error ใช้ชื่อ env เช่น DB_PORT)
+// - ห้ามใช้ CORS origin "*" ร่วมกับ CORS_ALLOW_CREDENTIALS=true ในทุกสภาพแวดล้อม
+// - cookie แบบ SameSite=None ต้องเป็น Secure
+// - ใน production จะไม่ยอมให้ใช้ JWT_SECRET ค่าเริ่มต้นหรือสั้นเกินไป ต้องตั้ง DB_PASSWORD, ต้องระบุ CORS origin และพารามิเตอร์ของ bcrypt/argon2id ต้องไม่ต่ำเกินไป
+// - ในสภาพแวดล้อมอื่นจะแสดงคำเตือนแทนการหยุดทำงาน
+func Validate(config *Config) error {
+	validate := validator.New()
+	validate.RegisterValidation("cors_origin", validateOrigin)
+	validate.RegisterValidation("avatar_size", validateAvatarSize)
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if env := field.Tag.Get("env"); env != "" {
+			return env
+		}
+		return field.Name
+	})
+
+	secrets := secretNames(config)
+	var errs []error
+	if err := validate.Struct(config); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return err
+		}
+		for _, fieldErr := range validationErrors {
+			value := fieldErr.Value()
+			if secrets[fieldErr.Field()] {
+				value = redacted
+			}
+			errs = append(errs, fmt.Errorf("%s: ไม่ผ่านกฎ %q (ค่า: %v)", fieldErr.Field(), describeRule(fieldErr), value))
+		}
+	}
+
+	if config.CORS.AllowCredentials && containsWildcard(config.CORS.AllowOrigins) {
+		errs = append(errs, errors.New(`CORS_ALLOW_ORIGINS: ห้ามใช้ "*" ร่วมกับ CORS_ALLOW_CREDENTIALS=true ต้องระบุ origin ที่แน่นอน`))
+	}
+
+	// browser ปฏิเสธ cookie ที่เป็น SameSite=None แต่ไม่มี Secure
+	if config.Session.CookieEnabled() && config.Session.CookieSameSite == "None" && !config.Session.CookieSecure {
+		errs = append(errs, errors.New("SESSION_COOKIE_SAMESITE: ค่า None ต้องใช้คู่กับ SESSION_COOKIE_SECURE=true"))
+	}
+
+	insecure := securityProblems(config)
+	if config.Server.Environment == "production" {
+		errs = append(errs, insecure...)
+	} else {
+		for _, problem := range insecure {
+			log.Printf("⚠️  คำเตือนการตั้งค่า/Config warning: %v", problem)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// securityProblems ค้นหาการตั้งค่าที่ไม่ปลอดภัยสำหรับการใช้งานจริง
+func securityProblems(config *Config) []error {
+	var problems []error
+	secret := config.JWT.Secret
+	if insecureSecrets[secret] {
+		problems = append(problems, errors.New("JWT_SECRET: ใช้ค่าเริ่มต้นหรือค่าตัวอย่าง ต้องกำหนด secret ของตัวเอง"))
+	} else if len(secret) < MinSecretLength {
+		problems = append(problems, fmt.Errorf("JWT_SECRET: สั้นเกินไป (%d ตัวอักษร) ต้องมีอย่างน้อย %d ตัวอักษร", len(secret), MinSecretLength))
+	}
+	if config.Database.Driver != "sqlite" && config.Database.Password == "" {
+		problems = append(problems, errors.New("DB_PASSWORD: ไม่ได้กำหนดรหัสผ่านฐานข้อมูล"))
+	}
+	if containsWildcard(config.CORS.AllowOrigins) {
+		problems = append(problems, errors.New(`CORS_ALLOW_ORIGINS: อนุญาตทุก origin ("*") ต้องระบุ origin ของ frontend`))
+	}
+	if config.Session.CookieEnabled() && !config.Session.CookieSecure {
+		problems = append(problems, errors.New("SESSION_COOKIE_SECURE: cookie ของ session ถูกส่งผ่าน HTTP ได้ ต้องเปิดใช้ใน production"))
+	}
+	if config.Password.Algorithm == "bcrypt" && config.Password.BcryptCost < MinBcryptCost {
+		problems = append(problems, fmt.Errorf("PASSWORD_BCRYPT_COST: ต่ำเกินไป (%d) ต้องมีอย่างน้อย %d", config.Password.BcryptCost, MinBcryptCost))
+	}
+	if config.Password.Algorithm == "argon2id" && config.Password.Argon2Memory*config.Password.Argon2Iterations < MinArgon2Work {
+		problems = append(problems, fmt.Errorf("PASSWORD_ARGON2_MEMORY x PASSWORD_ARGON2_ITERATIONS: ต่ำเกินไป (%d KiB x %d) ต้องได้อย่างน้อย %d",
+			config.Password.Argon2Memory, config.Password.Argon2Iterations, MinArgon2Work))
+	}
+	return problems
+}
+
+// validateOrigin ตรวจสอบรูปแบบ origin ของ CORS: "*" หรือ scheme://host[:port]
+// host ขึ้นต้นด้วย "*." ได้เพื่ออนุญาตทุก subdomain เช่น https://*.example.com
+func validateOrigin(fl validator.FieldLevel) bool {
+	origin := fl.Field().String()
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(strings.Replace(origin, "://*.", "://wildcard.", 1))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != "" &&
+		!strings.Contains(u.Host, "*") && u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == ""
+}
+
+// validateAvatarSize ตรวจขนาด thumbnail ของรูปโปรไฟล์: ตัวเลข 16-1024 pixel
+func validateAvatarSize(fl validator.FieldLevel) bool {
+	size, err := strconv.Atoi(strings.TrimSpace(fl.Field().String()))
+	return err == nil && size >= 16 && size <= 1024
+}
+
+// containsWildcard ตรวจว่ารายการ origin มี "*" (อนุญาตทุก origin) หรือไม่
+func containsWildcard(origins []string) bool {
+	for _, origin := range origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRule แสดงกฎที่ไม่ผ่านพร้อมพารามิเตอร์ เช่น oneof=otlp stdout none
+func describeRule(fieldErr validator.FieldError) string {
+	if fieldErr.Param() == "" {
+		return fieldErr.Tag()
+	}
+	return fieldErr.Tag() + "=" + fieldErr.Param()
+}
+
+// secretNames คืนค่าชื่อ env ของ field ที่มี tag `secret:"true"`
+func secretNames(config *Config) map[string]bool {
+	names := map[string]bool{}
+	walkFields(config, func(field reflect.StructField, _ reflect.Value) {
+		if field.Tag.Get("secret") == "true" {
+			names[field.Tag.Get("env")] = true
+		}
+	})
+	return names
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// defaultConfig คืนค่าการตั้งค่าจากค่า default เท่านั้น (ผ่าน Validate ใน development)
+func defaultConfig(t *testing.T) *Config {
+	t.Helper()
+	config := newConfig()
+	if err := applyDefaults(config); err != nil {
+		t.Fatalf("applyDefaults: %v", err)
+	}
+	return config
+}
+
+// TestValidateOrigin ตรวจรูปแบบ origin ของ CORS: รับเฉพาะ "*" หรือ http(s)://host[:port] ที่ไม่มี path, query หรือ / ต่อท้าย
+func TestValidateOrigin(t *testing.T) {
+	tests := []struct {
+		origin string
+		valid  bool
+	}{
+		{"*", true},
+		{"https://app.example.com", true},
+		{"http://localhost:3000", true},
+		{"https://*.example.com", true},
+		{"http://[::1]:8080", true},
+		{"https://app.example.com/", false},
+		{"https://app.example.com/admin", false},
+		{"https://app.example.com?next=1", false},
+		{"https://app.example.com#top", false},
+		{"https://user@app.example.com", false},
+		{"ftp://app.example.com", false},
+		{"javascript:alert(1)", false},
+		{"file:///etc/passwd", false},
+		{"null", false},
+		{"app.example.com", false},
+		{"https://", false},
+		{"https://app.*.example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			for _, env := range []string{"CORS_ALLOW_ORIGINS", "CORS_SWAGGER_ALLOW_ORIGINS"} {
+				config := defaultConfig(t)
+				if env == "CORS_ALLOW_ORIGINS" {
+					config.CORS.AllowOrigins = []string{tt.origin}
+				} else {
+					config.CORS.SwaggerOrigins = []string{tt.origin}
+				}
+				err := Validate(config)
+				if tt.valid && err != nil {
+					t.Errorf("%s=%q: %v", env, tt.origin, err)
+				}
+				if !tt.valid && (err == nil || !strings.Contains(err.Error(), env)) {
+					t.Errorf("%s=%q: error = %v, ต้องการ error ของ %s", env, tt.origin, err, env)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateCORSCredentials ตรวจว่า "*" ใช้ร่วมกับ CORS_ALLOW_CREDENTIALS=true ไม่ได้ในทุกสภาพแวดล้อม
+func TestValidateCORSCredentials(t *testing.T) {
+	const credentialsError = `ห้ามใช้ "*" ร่วมกับ CORS_ALLOW_CREDENTIALS=true`
+
+	tests := []struct {
+		name        string
+		environment string
+		origins     []string
+		credentials bool
+		wantError   bool
+	}{
+		{"wildcard with credentials", "development", []string{"*"}, true, true},
+		{"wildcard among origins with credentials", "development", []string{"https://app.example.com", "*"}, true, true},
+		{"wildcard with credentials in production", "production", []string{"*"}, true, true},
+		{"wildcard without credentials", "development", []string{"*"}, false, false},
+		{"explicit origin with credentials", "development", []string{"https://app.example.com"}, true, false},
+		{"subdomain wildcard with credentials", "development", []string{"https://*.example.com"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultConfig(t)
+			config.Server.Environment = tt.environment
+			config.CORS.AllowOrigins = tt.origins
+			config.CORS.AllowCredentials = tt.credentials
+
+			err := Validate(config)
+			if got := err != nil && strings.Contains(err.Error(), credentialsError); got != tt.wantError {
+				t.Errorf("Validate error = %v, ต้องการ error ของ credentials = %v", err, tt.wantError)
+			}
+			// development ไม่มี error อื่นจากค่า default
+			if !tt.wantError && tt.environment == "development" && err != nil {
+				t.Errorf("Validate: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce เวลารอหลังไฟล์เปลี่ยนก่อน reload
+// editor หลายตัวเขียนไฟล์หลายครั้งติดกัน (หรือเขียนไฟล์ชั่วคราวแล้ว rename) จึงรวมเป็นการ reload ครั้งเดียว
+const reloadDebounce = 500 * time.Millisecond
+
+// Watch เริ่มเฝ้าดูสัญญาณ SIGHUP และการเปลี่ยนแปลงของไฟล์ .env / ไฟล์ YAML/TOML
+// แล้วเรียก Reload อัตโนมัติ ทำงานเบื้องหลังจนกว่า ctx จะถูกยกเลิก
+// คืนค่า error เฉพาะกรณีเริ่มต้นการเฝ้าดูไม่สำเร็จ
+func (s *Store) Watch(ctx context.Context) error {
+	files, err := sourceFiles(s.args)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถเฝ้าดูไฟล์การตั้งค่าได้: %w", err)
+	}
+
+	// เฝ้าดูที่ระดับโฟลเดอร์ เพื่อให้ยังทำงานเมื่อไฟล์ถูกแทนที่ด้วยการ rename
+	targets := map[string]bool{}
+	watched := map[string]bool{}
+	for _, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			watcher.Close()
+			return err
+		}
+		targets[path] = true
+		dir := filepath.Dir(path)
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("ไม่สามารถเฝ้าดูโฟลเดอร์ %s ได้: %w", dir, err)
+		}
+		watched[dir] = true
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(hup)
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				log.Println("📨 ได้รับ SIGHUP กำลังโหลดการตั้งค่าใหม่...")
+				s.reloadAndLog()
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !targets[filepath.Clean(event.Name)] || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				debounce = time.After(reloadDebounce)
+			case <-debounce:
+				debounce = nil
+				log.Println("📝 ไฟล์การตั้งค่าเปลี่ยนแปลง กำลังโหลดใหม่...")
+				s.reloadAndLog()
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("⚠️  การเฝ้าดูไฟล์การตั้งค่าผิดพลาด: %v", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// reloadAndLog เรียก Reload และบันทึก error โดยไม่หยุดการทำงาน (ใช้การตั้งค่าเดิมต่อ)
+func (s *Store) reloadAndLog() {
+	if err := s.Reload(); err != nil {
+		log.Printf("❌ โหลดการตั้งค่าใหม่ไม่สำเร็จ ใช้การตั้งค่าเดิมต่อ/Reload failed, keeping current configuration: %v", err)
+	}
+}
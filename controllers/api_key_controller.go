@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// APIKeyController โครงสร้างสำหรับจัดการ API key ของ client แบบ service-to-service (เฉพาะ Admin)
+type APIKeyController struct {
+	Config    *config.Store                // การตั้งค่าระบบ
+	DB        *sqlx.DB                     // การเชื่อมต่อฐานข้อมูล
+	Keys      *repository.APIKeyRepository // การเข้าถึงตาราง api_keys
+	Validator *validator.Validate          // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewAPIKeyController ฟังก์ชันสร้าง APIKeyController ใหม่
+func NewAPIKeyController(cfg *config.Store, db *sqlx.DB) *APIKeyController {
+	return &APIKeyController{
+		Config:    cfg,
+		DB:        db,
+		Keys:      repository.NewAPIKeyRepository(db),
+		Validator: validator.New(),
+	}
+}
+
+// CreateAPIKey ฟังก์ชันสำหรับสร้าง API key ใหม่ (เฉพาะ Admin)
+// key เต็มจะถูกส่งกลับเพียงครั้งเดียวใน response นี้ ระบบเก็บเฉพาะ hash
+// @Summary Create API key
+// @Description Create a scoped API key for a user (Admin only). The full key is returned only once.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param key body models.APIKeyCreate true "API key data"
+// @Success 201 {object} utils.Response{data=models.APIKeyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api-keys [post]
+func (kc *APIKeyController) CreateAPIKey(c *fiber.Ctx) error {
+	var input models.APIKeyCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := kc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "เวลาหมดอายุต้องอยู่ในอนาคต", nil)
+	}
+
+	// ไม่ระบุ user_id = สร้าง key ให้ตัวเอง
+	if input.UserID == 0 {
+		input.UserID, _ = c.Locals("user_id").(int)
+	}
+
+	// ตรวจสอบว่าผู้ใช้ที่จะเป็นเจ้าของ key มีอยู่จริง
+	var owner models.User
+	query := "SELECT id FROM users WHERE id = ?"
+	if err := kc.DB.GetContext(c.UserContext(), &owner, kc.DB.Rebind(query), input.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้ที่จะเป็นเจ้าของ key", nil)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+
+	raw, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง API key ได้", err)
+	}
+
+	key := models.APIKey{
+		UserID:    input.UserID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    strings.Join(input.Scopes, ","),
+		ExpiresAt: input.ExpiresAt,
+	}
+	id, err := kc.Keys.Create(c.UserContext(), &key)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึก API key ได้", err)
+	}
+
+	created, err := kc.Keys.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล API key ได้", err)
+	}
+
+	response := created.ConvertToResponse()
+	response.Key = raw // แสดง key เต็มเพียงครั้งเดียว
+	return utils.CreatedResponse(c, "สร้าง API key สำเร็จ กรุณาเก็บ key ไว้ เนื่องจากจะไม่แสดงอีก", response)
+}
+
+// GetAPIKeys ฟังก์ชันสำหรับดูรายการ API key (เฉพาะ Admin)
+// @Summary List API keys
+// @Description List API keys, optionally filtered by owner (Admin only)
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param user_id query int false "Filter by owner user ID"
+// @Success 200 {object} utils.Response{data=[]models.APIKeyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api-keys [get]
+func (kc *APIKeyController) GetAPIKeys(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Query("user_id", "0"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "user_id ไม่ถูกต้อง", err)
+	}
+
+	keys, err := kc.Keys.List(c.UserContext(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล API key ได้", err)
+	}
+
+	responses := make([]models.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, key.ConvertToResponse())
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูล API key สำเร็จ", responses)
+}
+
+// GetAPIKey ฟังก์ชันสำหรับดูข้อมูล API key ตาม ID (เฉพาะ Admin)
+// @Summary Get API key
+// @Description Get API key metadata by ID (Admin only)
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "API key ID"
+// @Success 200 {object} utils.Response{data=models.APIKeyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /api-keys/{id} [get]
+func (kc *APIKeyController) GetAPIKey(c *fiber.Ctx) error {
+	key, err := kc.findKey(c)
+	if err != nil || key == nil {
+		return err
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูล API key สำเร็จ", key.ConvertToResponse())
+}
+
+// UpdateAPIKey ฟังก์ชันสำหรับแก้ไขชื่อ, scope หรือเวลาหมดอายุของ API key (เฉพาะ Admin)
+// @Summary Update API key
+// @Description Update name, scopes or expiry of an API key (Admin only)
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "API key ID"
+// @Param key body models.APIKeyUpdate true "Fields to update"
+// @Success 200 {object} utils.Response{data=models.APIKeyResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api-keys/{id} [patch]
+func (kc *APIKeyController) UpdateAPIKey(c *fiber.Ctx) error {
+	var input models.APIKeyUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := kc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	key, err := kc.findKey(c)
+	if err != nil || key == nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถแก้ไข API key ที่ถูกเพิกถอนแล้ว", nil)
+	}
+
+	if input.Name != nil {
+		key.Name = *input.Name
+	}
+	if input.Scopes != nil {
+		key.Scopes = strings.Join(input.Scopes, ",")
+	}
+	if input.ExpiresAt != nil {
+		key.ExpiresAt = input.ExpiresAt
+	}
+
+	if err := kc.Keys.Update(c.UserContext(), key); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถแก้ไข API key ได้", err)
+	}
+	updated, err := kc.Keys.GetByID(c.UserContext(), key.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล API key ได้", err)
+	}
+	return utils.SuccessResponse(c, "แก้ไข API key สำเร็จ", updated.ConvertToResponse())
+}
+
+// RevokeAPIKey ฟังก์ชันสำหรับเพิกถอน API key (เฉพาะ Admin)
+// key ที่ถูกเพิกถอนจะใช้งานไม่ได้ทันที แต่ยังเก็บข้อมูลไว้เพื่อตรวจสอบย้อนหลัง
+// @Summary Revoke API key
+// @Description Revoke an API key so it can no longer be used (Admin only)
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "API key ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /api-keys/{id} [delete]
+func (kc *APIKeyController) RevokeAPIKey(c *fiber.Ctx) error {
+	key, err := kc.findKey(c)
+	if err != nil || key == nil {
+		return err
+	}
+	if err := kc.Keys.Revoke(c.UserContext(), key.ID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเพิกถอน API key ได้", err)
+	}
+	return utils.SuccessResponse(c, "เพิกถอน API key สำเร็จ", nil)
+}
+
+// findKey อ่าน key ตาม :id ในเส้นทาง
+// คืนค่า key เป็น nil เมื่อส่ง response ข้อผิดพลาด (400/404/500) ไปแล้ว
+func (kc *APIKeyController) findKey(c *fiber.Ctx) (*models.APIKey, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของ API key ไม่ถูกต้อง", err)
+	}
+	key, err := kc.Keys.GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบ API key", nil)
+	}
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล API key ได้", err)
+	}
+	return key, nil
+}
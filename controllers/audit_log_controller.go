@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// auditPageSize จำนวนบันทึกต่อหน้าเริ่มต้นและสูงสุดของ GET /audit-logs
+const (
+	auditPageSize    = 50
+	auditMaxPageSize = 200
+)
+
+// AuditLogController โครงสร้างสำหรับดูบันทึกการกระทำของผู้ดูแล (audit log)
+type AuditLogController struct {
+	Config *config.Store               // การตั้งค่าระบบ
+	DB     *sqlx.DB                    // การเชื่อมต่อฐานข้อมูล
+	Audit  *repository.AuditRepository // การเข้าถึงตาราง audit_logs (จำกัดองค์กรด้วย ForTenant)
+}
+
+// NewAuditLogController ฟังก์ชันสร้าง AuditLogController ใหม่
+func NewAuditLogController(cfg *config.Store, db *sqlx.DB) *AuditLogController {
+	return &AuditLogController{
+		Config: cfg,
+		DB:     db,
+		Audit:  repository.NewAuditRepository(db),
+	}
+}
+
+// GetAuditLogs ฟังก์ชันสำหรับดูบันทึกการกระทำของผู้ดูแลจากใหม่ไปเก่า (เฉพาะ Admin ของระบบหรือขององค์กร)
+// แบ่งหน้าด้วย before = ID ของบันทึกสุดท้ายในหน้าก่อนหน้า
+// @Summary Get audit logs
+// @Description List administrative actions, newest first. Organization Admins see only their organization's entries. Page with before = the last ID of the previous page.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param limit query int false "Page size (max 200)" default(50)
+// @Param before query int false "Return entries older than this ID"
+// @Success 200 {object} utils.Response{data=[]models.AuditLogResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /audit-logs [get]
+func (ac *AuditLogController) GetAuditLogs(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", auditPageSize)
+	if limit <= 0 || limit > auditMaxPageSize {
+		limit = auditMaxPageSize
+	}
+	logs, err := ac.Audit.ForTenant(tenantID(c)).List(c.UserContext(), c.QueryInt("before"), limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงบันทึกการกระทำได้", err)
+	}
+
+	responses := make([]models.AuditLogResponse, 0, len(logs))
+	for i := range logs {
+		responses = append(responses, logs[i].ConvertToResponse())
+	}
+	return utils.SuccessResponse(c, "ดึงบันทึกการกระทำสำเร็จ", responses)
+}
@@ -1,29 +1,56 @@
 package controllers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"log"
+	"strconv"
 	"time"
 
 	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/database"
 	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/password"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
 	"github.com/Sing254463/GoTemplate/Backend/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
 )
 
+// rehashTimeout เวลาสูงสุดของการ hash รหัสผ่านใหม่และบันทึกลงฐานข้อมูลหลังเข้าสู่ระบบ
+const rehashTimeout = 30 * time.Second
+
 // AuthController โครงสร้างสำหรับจัดการการยืนยันตัวตน
-// ประกอบด้วย Config (การตั้งค่า) และ Validator (ตัวตรวจสอบข้อมูล)
+// ประกอบด้วย Config (การตั้งค่า), DB (การเชื่อมต่อฐานข้อมูล) และ Validator (ตัวตรวจสอบข้อมูล)
 type AuthController struct {
-	Config    *config.Config      // การตั้งค่าระบบ (ฐานข้อมูล, JWT, เซิร์ฟเวอร์)
-	Validator *validator.Validate // ตัวตรวจสอบความถูกต้องของข้อมูล
+	Config    *config.Store                  // การตั้งค่าระบบ (JWT, เซิร์ฟเวอร์) อ่านค่าล่าสุดด้วย Config.Get()
+	DB        *sqlx.DB                       // การเชื่อมต่อฐานข้อมูล
+	Validator *validator.Validate            // ตัวตรวจสอบความถูกต้องของข้อมูล
+	Sessions  *repository.SessionRepository  // การเข้าถึงตาราง sessions
+	Passwords *repository.PasswordRepository // การเปลี่ยนรหัสผ่านและตาราง password_history
+	Policy    *password.Checker              // นโยบายรหัสผ่าน (PASSWORD_*)
+
+	Invitations   *repository.InvitationRepository   // การรับคำเชิญและตาราง invitations
+	Organizations *repository.OrganizationRepository // การเลือกองค์กรตอนเข้าสู่ระบบ
+	Events        *repository.EventRepository        // outbox ของ domain event (UserRegistered, UserLoggedIn)
 }
 
 // NewAuthController ฟังก์ชันสร้าง AuthController ใหม่
-// รับพารามิเตอร์ cfg (การตั้งค่า) และคืนค่า pointer ของ AuthController
-func NewAuthController(cfg *config.Config) *AuthController {
+// รับพารามิเตอร์ cfg (การตั้งค่าที่ reload ได้) และ db (การเชื่อมต่อฐานข้อมูล) และคืนค่า pointer ของ AuthController
+func NewAuthController(cfg *config.Store, db *sqlx.DB) *AuthController {
 	return &AuthController{
 		Config:    cfg,             // เก็บการตั้งค่าที่ได้รับ
+		DB:        db,              // เก็บการเชื่อมต่อฐานข้อมูล
 		Validator: validator.New(), // สร้างตัวตรวจสอบข้อมูลใหม่
+		Sessions:  repository.NewSessionRepository(db),
+		Passwords: repository.NewPasswordRepository(db),
+		Policy:    password.NewChecker(cfg),
+
+		Invitations:   repository.NewInvitationRepository(db),
+		Organizations: repository.NewOrganizationRepository(db),
+		Events:        repository.NewEventRepository(db),
 	}
 }
 
@@ -36,13 +63,20 @@ func NewAuthController(cfg *config.Config) *AuthController {
 // @Produce json
 // @Param user body models.UserRegister true "User registration data"
 // @Success 201 {object} utils.Response
-// @Failure 400 {object} utils.Response
+// @Failure 400 {object} utils.Response{errors=[]utils.FieldError} "Invalid input or password policy violations"
+// @Failure 403 {object} utils.Response "Self-registration is disabled (REGISTRATION_ENABLED=false)"
 // @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /auth/register [post]
 func (ac *AuthController) Register(c *fiber.Ctx) error {
 	var userRegister models.UserRegister
 
+	// ปิดการลงทะเบียนด้วยตนเอง: สร้างบัญชีได้ผ่านคำเชิญของ Admin เท่านั้น
+	cfg := ac.Config.Get()
+	if !cfg.Registration.Enabled {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ปิดการลงทะเบียนด้วยตนเอง กรุณาติดต่อผู้ดูแลระบบเพื่อขอคำเชิญ", nil)
+	}
+
 	// แปลงข้อมูล JSON จาก request body เป็น struct
 	if err := c.BodyParser(&userRegister); err != nil {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
@@ -54,10 +88,19 @@ func (ac *AuthController) Register(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
 	}
 
+	// ตรวจรหัสผ่านตามนโยบาย (ความยาว, ชนิดตัวอักษร, ชื่อผู้ใช้/อีเมล, รายการรหัสผ่านที่รั่วไหล)
+	violations, err := ac.Policy.Check(userRegister.Password, password.UserInfo{Username: userRegister.Username, Email: userRegister.Email})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบรหัสผ่านได้", err)
+	}
+	if len(violations) > 0 {
+		return utils.ValidationErrorResponse(c, "รหัสผ่านไม่เป็นไปตามนโยบาย", passwordErrors("password", violations))
+	}
+
 	// ตรวจสอบว่ามีผู้ใช้ที่มี email หรือ username นี้อยู่แล้วหรือไม่
 	var existingUser models.User
 	query := "SELECT id FROM users WHERE email = ? OR username = ?"
-	err := ac.Config.Database.DB.Get(&existingUser, query, userRegister.Email, userRegister.Username)
+	err = ac.DB.GetContext(c.UserContext(), &existingUser, ac.DB.Rebind(query), userRegister.Email, userRegister.Username)
 	if err == nil {
 		// หากพบผู้ใช้ที่มีข้อมูลซ้ำ ให้ส่งข้อผิดพลาดกลับ
 		return utils.ErrorResponse(c, fiber.StatusConflict, "มีผู้ใช้นี้อยู่แล้ว", nil)
@@ -66,50 +109,134 @@ func (ac *AuthController) Register(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
 	}
 
-	// เข้ารหัสรหัสผ่านด้วย bcrypt เพื่ือความปลอดภัย
-	hashedPassword, err := utils.HashPassword(userRegister.Password)
+	// เข้ารหัสรหัสผ่านเพื่ือความปลอดภัย (อัลกอริธึมและพารามิเตอร์ตาม PASSWORD_ALGORITHM)
+	hashedPassword, err := password.NewHasher(cfg.Password).Hash(userRegister.Password)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเข้ารหัสรหัสผ่านได้", err)
 	}
 
 	// สร้าง struct ผู้ใช้ใหม่พร้อมข้อมูลที่จำเป็น
 	user := models.User{
-		Username:  userRegister.Username, // ชื่อผู้ใช้
-		Email:     userRegister.Email,    // อีเมล
-		Password:  hashedPassword,        // รหัสผ่านที่เข้ารหัสแล้ว
-		Role:      "user",                // สิทธิ์เริ่มต้นเป็น user (ไม่ใช่ admin)
-		CreatedAt: time.Now(),            // เวลาที่สร้างบัญชี
-		UpdatedAt: time.Now(),            // เวลาที่อัปเดตล่าสุด
+		Username:  userRegister.Username,   // ชื่อผู้ใช้
+		Email:     userRegister.Email,      // อีเมล
+		Password:  hashedPassword,          // รหัสผ่านที่เข้ารหัสแล้ว
+		Role:      "user",                  // สิทธิ์เริ่มต้นเป็น user (ไม่ใช่ admin)
+		Status:    models.UserStatusActive, // บัญชีใช้งานได้ทันที
+		CreatedAt: time.Now(),              // เวลาที่สร้างบัญชี
+		UpdatedAt: time.Now(),              // เวลาที่อัปเดตล่าสุด
+	}
+
+	// บันทึกข้อมูลผู้ใช้ใหม่, ประวัติรหัสผ่านแรก และ event UserRegistered ใน transaction เดียว (subscriber ได้รับ event ก็ต่อเมื่อสร้างบัญชีสำเร็จ)
+	// database.InsertID จะใช้ RETURNING id (PostgreSQL) หรือ LastInsertId (MySQL, SQLite) ตาม driver
+	tx, err := ac.DB.BeginTxx(c.UserContext(), nil)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
 	}
+	defer tx.Rollback()
 
-	// บันทึกข้อมูลผู้ใช้ใหม่ลงในฐานข้อมูล
 	query = `INSERT INTO users (username, email, password, role, created_at, updated_at) 
              VALUES (?, ?, ?, ?, ?, ?)`
-	result, err := ac.Config.Database.DB.Exec(query, user.Username, user.Email, user.Password, user.Role, user.CreatedAt, user.UpdatedAt)
+	userID, err := database.InsertID(c.UserContext(), tx, query, user.Username, user.Email, user.Password, user.Role, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
 	}
+	user.ID = userID
 
-	// ดึง ID ของผู้ใช้ที่เพิ่งสร้างจากฐานข้อมูล
-	userID, _ := result.LastInsertId()
-	user.ID = int(userID)
+	// เก็บรหัสผ่านแรกไว้ในประวัติใน transaction เดียวกัน เพื่อห้ามเปลี่ยนกลับมาใช้ซ้ำ
+	if err := ac.Passwords.Record(c.UserContext(), tx, user.ID, hashedPassword, cfg.Password.History); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
+	}
+	if err := ac.Events.Publish(c.UserContext(), tx, models.UserRegistered{User: user.ConvertToResponse()}); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
+	}
 
 	// ส่งผลลัพธ์การลงทะเบียนสำเร็จกลับไป (ไม่รวมรหัสผ่าน)
 	return utils.CreatedResponse(c, "ลงทะเบียนผู้ใช้สำเร็จ", user.ConvertToResponse())
 }
 
+// AcceptInvite ฟังก์ชันสำหรับรับคำเชิญและสร้างบัญชี
+// ผู้รับเชิญตั้งชื่อผู้ใช้และรหัสผ่านเอง ส่วนอีเมลและ role มาจากคำเชิญ ใช้ได้แม้ปิดการลงทะเบียนด้วยตนเอง
+// @Summary Accept invitation
+// @Description Create an account from an invitation token. Email and role come from the invitation; the token works once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param invitation body models.InvitationAccept true "Invitation token, username and password"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response{errors=[]utils.FieldError} "Invalid input or password policy violations"
+// @Failure 404 {object} utils.Response "Unknown token"
+// @Failure 409 {object} utils.Response "Username or email already taken"
+// @Failure 410 {object} utils.Response "Invitation already used, revoked or expired"
+// @Failure 500 {object} utils.Response
+// @Router /auth/accept-invite [post]
+func (ac *AuthController) AcceptInvite(c *fiber.Ctx) error {
+	var input models.InvitationAccept
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := ac.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	inv, err := ac.Invitations.GetByTokenHash(c.UserContext(), utils.HashInviteToken(input.Token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบคำเชิญ", nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	if inv.Status(time.Now()) != models.InvitationPending {
+		return utils.ErrorResponse(c, fiber.StatusGone, repository.ErrInvitationClosed.Error(), nil)
+	}
+
+	violations, err := ac.Policy.Check(input.Password, password.UserInfo{Username: input.Username, Email: inv.Email})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบรหัสผ่านได้", err)
+	}
+	if len(violations) > 0 {
+		return utils.ValidationErrorResponse(c, "รหัสผ่านไม่เป็นไปตามนโยบาย", passwordErrors("password", violations))
+	}
+
+	cfg := ac.Config.Get()
+	hashedPassword, err := password.NewHasher(cfg.Password).Hash(input.Password)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเข้ารหัสรหัสผ่านได้", err)
+	}
+
+	// สร้างผู้ใช้และปิดคำเชิญใน transaction เดียว (คำเชิญที่ถูกรับพร้อมกันจะสำเร็จเพียงครั้งเดียว)
+	user := models.User{Username: input.Username, Password: hashedPassword}
+	err = ac.Invitations.Accept(c.UserContext(), inv, &user, cfg.Password.History)
+	switch {
+	case errors.Is(err, repository.ErrInvitationClosed):
+		return utils.ErrorResponse(c, fiber.StatusGone, err.Error(), nil)
+	case errors.Is(err, repository.ErrUserExists):
+		return utils.ErrorResponse(c, fiber.StatusConflict, "มีผู้ใช้ที่ใช้ชื่อผู้ใช้หรืออีเมลนี้อยู่แล้ว", nil)
+	case err != nil:
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
+	}
+
+	return utils.CreatedResponse(c, "รับคำเชิญและสร้างบัญชีสำเร็จ", user.ConvertToResponse())
+}
+
 // Login ฟังก์ชันสำหรับเข้าสู่ระบบ
 // รับ email และ password แล้วตรวจสอบความถูกต้อง
-// หากถูกต้องจะสร้าง JWT token ให้
+// หากถูกต้องจะสร้าง JWT token ให้ โดย token ผูกกับองค์กรที่ระบุด้วย header X-Tenant หรือ subdomain
+// (ไม่ระบุ = องค์กรแรกที่เป็นสมาชิก)
 // @Summary Login user
-// @Description Login with email and password
+// @Description Login with email and password. The token is bound to the organization named by the tenant header or subdomain, or to the user's first organization.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param user body models.UserLogin true "User login data"
+// @Param X-Tenant header string false "Organization slug"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not a member of the requested organization"
+// @Failure 404 {object} utils.Response "Unknown organization"
 // @Failure 500 {object} utils.Response
 // @Router /auth/login [post]
 func (ac *AuthController) Login(c *fiber.Ctx) error {
@@ -127,8 +254,8 @@ func (ac *AuthController) Login(c *fiber.Ctx) error {
 
 	// ค้นหาผู้ใช้ในฐานข้อมูลด้วย email
 	var user models.User
-	query := "SELECT id, username, email, password, role FROM users WHERE email = ?"
-	err := ac.Config.Database.DB.Get(&user, query, userLogin.Email)
+	query := "SELECT id, username, email, password, role, status, suspended_until, display_name, bio, locale, timezone, phone, metadata, avatar_key FROM users WHERE email = ?"
+	err := ac.DB.GetContext(c.UserContext(), &user, ac.DB.Rebind(query), userLogin.Email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// หากไม่พบผู้ใช้ที่มี email นี้
@@ -138,23 +265,176 @@ func (ac *AuthController) Login(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
 	}
 
-	// ตรวจสอบรหัสผ่าน โดยเปรียบเทียบกับรหัสผ่านที่เข้ารหัสไว้ในฐานข้อมูล
-	if err := utils.CheckPassword(user.Password, userLogin.Password); err != nil {
+	// ตรวจสอบรหัสผ่าน โดยเปรียบเทียบกับรหัสผ่านที่เข้ารหัสไว้ในฐานข้อมูล (รองรับทั้ง argon2id และ bcrypt)
+	matched, err := password.Verify(user.Password, userLogin.Password)
+	if err != nil {
+		log.Printf("⚠️  ไม่สามารถตรวจ password hash ของผู้ใช้ %d: %v", user.ID, err)
+	}
+	if !matched {
 		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "อีเมลหรือรหัสผ่านไม่ถูกต้อง", nil)
 	}
+	// บัญชีที่ถูกระงับหรือยังไม่เปิดใช้ตรวจหลังรหัสผ่าน (ผู้ที่ไม่รู้รหัสผ่านจึงใช้ตรวจสถานะบัญชีไม่ได้)
+	if status := user.EffectiveStatus(time.Now()); status != models.UserStatusActive {
+		return utils.AccountStatusResponse(c, status, user.SuspendedUntil)
+	}
+
+	// hash ที่ใช้อัลกอริธึมหรือพารามิเตอร์เก่าจะถูก hash ใหม่เบื้องหลัง (ผู้ใช้ไม่ต้องรอ)
+	cfg := ac.Config.Get()
+	if hasher := password.NewHasher(cfg.Password); hasher.NeedsRehash(user.Password) {
+		go ac.rehashPassword(hasher, user.ID, user.Password, userLogin.Password)
+	}
+
+	// เลือกองค์กรหลังตรวจรหัสผ่านแล้ว (ผู้ที่ไม่รู้รหัสผ่านจึงใช้ตรวจว่ามีองค์กรใดบ้างไม่ได้)
+	tenant, err := chooseTenant(c.UserContext(), utils.TenantFromRequest(c, cfg.Tenant), ac.Organizations, &user)
+	if err != nil {
+		return tenantError(c, err)
+	}
+
+	// สร้าง JWT token และ session สำหรับผู้ใช้ที่เข้าสู่ระบบสำเร็จ
+	// token ถูกส่งใน response และ/หรือ cookie ตาม SESSION_MODE
+	data := fiber.Map{
+		"user": userResponse(cfg, &user), // ข้อมูลผู้ใช้ (ไม่รวมรหัสผ่าน)
+	}
+	if err := startSession(c, cfg, ac.Sessions, &user, tenant, data); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง session ได้", err)
+	}
+	// การเข้าสู่ระบบไม่เปลี่ยนข้อมูลผู้ใช้ จึงบันทึก event แยก (ล้มเหลว = subscriber ไม่ได้รับ แต่ยังเข้าสู่ระบบได้)
+	loggedIn := models.UserLoggedIn{User: user.ConvertToResponse()}
+	if tenant != nil {
+		loggedIn.OrganizationID = &tenant.ID
+	}
+	if err := ac.Events.Publish(c.UserContext(), ac.DB, loggedIn); err != nil {
+		log.Printf("⚠️  ไม่สามารถบันทึก event เข้าสู่ระบบของผู้ใช้ %d: %v", user.ID, err)
+	}
+	return utils.SuccessResponse(c, "เข้าสู่ระบบสำเร็จ", data)
+}
+
+// rehashPassword hash รหัสผ่านที่เพิ่งตรวจสำเร็จด้วยการตั้งค่าปัจจุบันแล้วบันทึกแทน hash เดิม
+// ทำงานใน goroutine แยกจาก request จึงใช้ context ของตัวเอง และไม่ทับรหัสผ่านที่ถูกเปลี่ยนไประหว่างนั้น
+func (ac *AuthController) rehashPassword(hasher password.Hasher, userID int, oldHash, plain string) {
+	ctx, cancel := context.WithTimeout(context.Background(), rehashTimeout)
+	defer cancel()
+
+	newHash, err := hasher.Hash(plain)
+	if err != nil {
+		log.Printf("⚠️  ไม่สามารถ hash รหัสผ่านใหม่ของผู้ใช้ %d: %v", userID, err)
+		return
+	}
+	updated, err := ac.Passwords.Rehash(ctx, userID, oldHash, newHash)
+	if err != nil {
+		log.Printf("⚠️  ไม่สามารถบันทึก hash ใหม่ของผู้ใช้ %d: %v", userID, err)
+		return
+	}
+	if !updated {
+		// รหัสผ่านถูกเปลี่ยนหรือ hash ใหม่โดย request อื่นไปแล้ว
+		return
+	}
+	log.Printf("🔐 hash รหัสผ่านของผู้ใช้ %d ใหม่ด้วย %s", userID, hasher.Algorithm())
+}
+
+// Logout ฟังก์ชันสำหรับออกจากระบบ
+// เพิกถอน session ของ token ที่ใช้เรียก (token ใช้งานไม่ได้อีก) และลบ cookie ของ session และ CSRF token (โหมด cookie)
+// @Summary Logout
+// @Description Revoke the current session and clear the session and CSRF cookies. Cookie-authenticated requests must send the CSRF header.
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param X-CSRF-Token header string false "CSRF token (required when authenticated by cookie)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *fiber.Ctx) error {
+	// API key และ token ของแอปไม่มี session ให้เพิกถอน
+	if sessionID, ok := c.Locals("session_id").(int); ok {
+		if _, err := ac.Sessions.Revoke(c.UserContext(), c.Locals("user_id").(int), sessionID); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเพิกถอน session ได้", err)
+		}
+	}
+	// token ของการ impersonation ไม่เคยอยู่ใน cookie (cookie ใน browser เป็น session ของ Admin เอง)
+	if _, impersonating := c.Locals("impersonator_id").(int); !impersonating {
+		utils.ClearSessionCookies(c, ac.Config.Get().Session)
+	}
+	return utils.SuccessResponse(c, "ออกจากระบบสำเร็จ", nil)
+}
+
+// ChangePassword ฟังก์ชันสำหรับเปลี่ยนรหัสผ่านของผู้ใช้ที่เข้าสู่ระบบ
+// รหัสผ่านใหม่ต้องผ่านนโยบายและไม่ซ้ำกับ PASSWORD_HISTORY รหัสผ่านล่าสุด
+// เมื่อสำเร็จ session อื่นทั้งหมดของผู้ใช้จะถูกเพิกถอน (session ปัจจุบันยังใช้งานได้)
+// @Summary Change password
+// @Description Change the current user's password. The new password must satisfy the password policy and must not match recent passwords. Other sessions are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param X-CSRF-Token header string false "CSRF token (required when authenticated by cookie)"
+// @Param password body models.PasswordChange true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response{errors=[]utils.FieldError} "Invalid input, wrong current password or password policy violations"
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/password [post]
+func (ac *AuthController) ChangePassword(c *fiber.Ctx) error {
+	// API key และ token ของแอปเปลี่ยนรหัสผ่านของผู้ใช้ไม่ได้
+	sessionID, ok := c.Locals("session_id").(int)
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเข้าสู่ระบบด้วยบัญชีผู้ใช้โดยตรงเพื่อเปลี่ยนรหัสผ่าน", nil)
+	}
+	userID := c.Locals("user_id").(int)
 
-	// สร้าง JWT token สำหรับผู้ใช้ที่เข้าสู่ระบบสำเร็จ
-	// token จะมีข้อมูล user ID, username, role และจะหมดอายุตามที่กำหนดใน config
-	token, err := utils.GenerateJWT(user.ID, user.Username, user.Role, ac.Config.JWT.Secret, ac.Config.JWT.Expire)
+	var input models.PasswordChange
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := ac.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	var user models.User
+	query := "SELECT id, username, email, password FROM users WHERE id = ?"
+	if err := ac.DB.GetContext(c.UserContext(), &user, ac.DB.Rebind(query), userID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
+	}
+	if matched, _ := password.Verify(user.Password, input.CurrentPassword); !matched {
+		return utils.ValidationErrorResponse(c, "รหัสผ่านปัจจุบันไม่ถูกต้อง", []utils.FieldError{
+			{Field: "current_password", Code: "incorrect", Message: "รหัสผ่านปัจจุบันไม่ถูกต้อง"},
+		})
+	}
+
+	violations, err := ac.Policy.Check(input.NewPassword, password.UserInfo{Username: user.Username, Email: user.Email})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบรหัสผ่านได้", err)
+	}
+
+	// ตรวจการใช้ซ้ำกับรหัสผ่านปัจจุบันและประวัติ (ผู้ใช้ที่สร้างก่อนมีตาราง password_history มีเพียงรหัสผ่านปัจจุบัน)
+	cfg := ac.Config.Get()
+	if cfg.Password.History > 0 {
+		history, err := ac.Passwords.Recent(c.UserContext(), userID, cfg.Password.History)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบประวัติรหัสผ่านได้", err)
+		}
+		if password.Reused(input.NewPassword, append([]string{user.Password}, history...)) {
+			violations = append(violations, password.ReusedViolation(cfg.Password.History))
+		}
+	}
+	if len(violations) > 0 {
+		return utils.ValidationErrorResponse(c, "รหัสผ่านไม่เป็นไปตามนโยบาย", passwordErrors("new_password", violations))
+	}
+
+	hashedPassword, err := password.NewHasher(cfg.Password).Hash(input.NewPassword)
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง token ได้", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเข้ารหัสรหัสผ่านได้", err)
+	}
+	if err := ac.Passwords.Change(c.UserContext(), userID, hashedPassword, cfg.Password.History); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเปลี่ยนรหัสผ่านได้", err)
 	}
 
-	// ส่งผลลัพธ์การเข้าสู่ระบบสำเร็จพร้อม token และข้อมูลผู้ใช้
-	return utils.SuccessResponse(c, "เข้าสู่ระบบสำเร็จ", fiber.Map{
-		"token": token,                    // JWT token สำหรับการยืนยันตัวตน
-		"user":  user.ConvertToResponse(), // ข้อมูลผู้ใช้ (ไม่รวมรหัสผ่าน)
-	})
+	// ผู้ที่อาจรู้รหัสผ่านเดิมต้องไม่สามารถใช้ token ที่ได้ไปแล้วต่อได้
+	if err := ac.Sessions.RevokeOthers(c.UserContext(), userID, sessionID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเพิกถอน session อื่นได้", err)
+	}
+	return utils.SuccessResponse(c, "เปลี่ยนรหัสผ่านสำเร็จ", nil)
 }
 
 // GetProfile ฟังก์ชันสำหรับดูข้อมูลโปรไฟล์ของผู้ใช้ที่เข้าสู่ระบบ
@@ -165,6 +445,7 @@ func (ac *AuthController) Login(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -175,12 +456,67 @@ func (ac *AuthController) GetProfile(c *fiber.Ctx) error {
 
 	// ค้นหาข้อมูลผู้ใช้ในฐานข้อมูลด้วย ID
 	var user models.User
-	query := "SELECT id, username, email, role, created_at, updated_at FROM users WHERE id = ?"
-	err := ac.Config.Database.DB.Get(&user, query, userID)
+	query := "SELECT id, username, email, role, status, suspension_reason, suspended_until, display_name, bio, locale, timezone, phone, metadata, avatar_key, created_at, updated_at FROM users WHERE id = ?"
+	err := ac.DB.GetContext(c.UserContext(), &user, ac.DB.Rebind(query), userID)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลโปรไฟล์ได้", err)
 	}
 
 	// ส่งข้อมูลโปรไฟล์กลับไป (ไม่รวมรหัสผ่าน)
-	return utils.SuccessResponse(c, "ดึงข้อมูลโปรไฟล์สำเร็จ", user.ConvertToResponse())
+	return utils.SuccessResponse(c, "ดึงข้อมูลโปรไฟล์สำเร็จ", userResponse(ac.Config.Get(), &user))
+}
+
+// startSession ออก JWT ให้ผู้ใช้ บันทึก session (อุปกรณ์, IP) และส่ง token ให้ client ตาม SESSION_MODE
+// header/both: เติม token ลงใน data ส่วน cookie/both: ตั้ง cookie ของ session และเติม csrf_token ลงใน data
+// tenant คือองค์กรที่เลือกจาก chooseTenant (nil = ไม่มีองค์กร) ถูกเก็บใน claim tid และเติมลงใน data
+func startSession(c *fiber.Ctx, cfg *config.Config, sessions *repository.SessionRepository, user *models.User, tenant *models.OrganizationMembership, data fiber.Map) error {
+	// token จะมีข้อมูล user ID, username, role, องค์กร และจะหมดอายุตามที่กำหนดใน config
+	// jti ของ token ผูกกับแถวในตาราง sessions เพื่อให้เพิกถอนได้ก่อนหมดอายุ
+	claims := &utils.JWTClaims{UserID: user.ID, Username: user.Username, Role: user.Role}
+	if tenant != nil {
+		claims.TenantID = tenant.ID
+		data["organization"] = tenant // องค์กรของ token พร้อม role ในองค์กร
+	}
+	claims.Issuer = "GoTemplate"
+	claims.Subject = strconv.Itoa(user.ID)
+	token, err := utils.GenerateAccessToken(claims, cfg.JWT.Secret, cfg.JWT.Expire)
+	if err != nil {
+		return err
+	}
+
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	if _, err := sessions.Create(c.UserContext(), &models.Session{
+		UserID:    user.ID,
+		JTI:       claims.ID,
+		Device:    utils.DeviceName(userAgent),
+		UserAgent: userAgent,
+		IPAddress: c.IP(),
+		ExpiresAt: claims.ExpiresAt.Time,
+	}); err != nil {
+		return err
+	}
+
+	if cfg.Session.ReturnsToken() {
+		data["token"] = token // JWT token สำหรับการยืนยันตัวตน
+	}
+	if cfg.Session.CookieEnabled() {
+		csrf, err := utils.SetSessionCookies(c, cfg.Session, cfg.JWT.Secret, token, cfg.JWT.Expire)
+		if err != nil {
+			return err
+		}
+		data["csrf_token"] = csrf // ส่งใน header X-CSRF-Token กับ request ที่แก้ไขข้อมูล
+	}
+	return nil
+}
+
+// passwordErrors แปลงการละเมิดนโยบายรหัสผ่านเป็นข้อผิดพลาดราย field ของ response
+func passwordErrors(field string, violations []password.Violation) []utils.FieldError {
+	errs := make([]utils.FieldError, 0, len(violations))
+	for _, v := range violations {
+		errs = append(errs, utils.FieldError{Field: field, Code: v.Code, Message: v.Message})
+	}
+	return errs
 }
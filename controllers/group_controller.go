@@ -0,0 +1,397 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// GroupController โครงสร้างสำหรับจัดการกลุ่ม (ทีม) ของผู้ใช้และสมาชิกของกลุ่ม
+// Admin ของระบบหรือขององค์กรสร้าง/แก้ไข/ลบกลุ่มในองค์กรที่เลือก ส่วนสมาชิกจัดการได้โดย Admin และ owner ของกลุ่ม
+type GroupController struct {
+	Config    *config.Store               // การตั้งค่าระบบ
+	DB        *sqlx.DB                    // การเชื่อมต่อฐานข้อมูล
+	Groups    *repository.GroupRepository // การเข้าถึงตาราง user_groups และ group_members
+	Users     *repository.UserRepository  // การเข้าถึงตาราง users
+	Validator *validator.Validate         // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewGroupController ฟังก์ชันสร้าง GroupController ใหม่
+func NewGroupController(cfg *config.Store, db *sqlx.DB) *GroupController {
+	return &GroupController{
+		Config:    cfg,
+		DB:        db,
+		Groups:    repository.NewGroupRepository(db),
+		Users:     repository.NewUserRepository(db),
+		Validator: validator.New(),
+	}
+}
+
+// CreateGroup ฟังก์ชันสำหรับสร้างกลุ่มในองค์กรที่เลือก
+// @Summary Create group
+// @Description Create a group in the selected organization (Admin or organization Admin). Without an organization a platform Admin creates a system-wide group.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param group body models.GroupCreate true "Group data"
+// @Success 201 {object} utils.Response{data=models.Group}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups [post]
+func (gc *GroupController) CreateGroup(c *fiber.Ctx) error {
+	var input models.GroupCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if err := gc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	groups := gc.Groups.ForTenant(tenantID(c))
+	id, err := groups.Create(c.UserContext(), &models.Group{Name: input.Name, Description: input.Description})
+	if errors.Is(err, repository.ErrGroupExists) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างกลุ่มได้", err)
+	}
+
+	group, err := groups.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลกลุ่มได้", err)
+	}
+	return utils.CreatedResponse(c, "สร้างกลุ่มสำเร็จ", group)
+}
+
+// GetGroups ฟังก์ชันสำหรับดูรายการกลุ่มในองค์กรที่เลือก
+// @Summary List groups
+// @Description List groups in the selected organization (Admin or organization Admin)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.Group}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups [get]
+func (gc *GroupController) GetGroups(c *fiber.Ctx) error {
+	groups, err := gc.Groups.ForTenant(tenantID(c)).List(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลกลุ่มได้", err)
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลกลุ่มสำเร็จ", groups)
+}
+
+// GetGroup ฟังก์ชันสำหรับดูข้อมูลกลุ่มตาม ID
+// @Summary Get group by ID
+// @Description Get a group in the selected organization (Admin or organization Admin)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Success 200 {object} utils.Response{data=models.Group}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id} [get]
+func (gc *GroupController) GetGroup(c *fiber.Ctx) error {
+	group, err := gc.findGroup(c)
+	if err != nil || group == nil {
+		return err
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลกลุ่มสำเร็จ", group)
+}
+
+// UpdateGroup ฟังก์ชันสำหรับแก้ไขชื่อและคำอธิบายของกลุ่ม
+// ชื่อกลุ่มใช้ใน middleware.RequireGroup การเปลี่ยนชื่อจึงมีผลกับสิทธิ์ของเส้นทางที่อ้างถึงชื่อเดิม
+// @Summary Update group
+// @Description Change a group's name or description (Admin or organization Admin)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Param group body models.GroupUpdate true "Fields to change"
+// @Success 200 {object} utils.Response{data=models.Group}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id} [patch]
+func (gc *GroupController) UpdateGroup(c *fiber.Ctx) error {
+	var input models.GroupUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		input.Name = &name
+	}
+	if err := gc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	group, err := gc.findGroup(c)
+	if err != nil || group == nil {
+		return err
+	}
+	if input.Name != nil {
+		group.Name = *input.Name
+	}
+	if input.Description != nil {
+		group.Description = *input.Description
+	}
+
+	groups := gc.Groups.ForTenant(tenantID(c))
+	err = groups.Update(c.UserContext(), group)
+	if errors.Is(err, repository.ErrGroupExists) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถแก้ไขกลุ่มได้", err)
+	}
+	updated, err := groups.GetByID(c.UserContext(), group.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลกลุ่มได้", err)
+	}
+	return utils.SuccessResponse(c, "แก้ไขกลุ่มสำเร็จ", updated)
+}
+
+// DeleteGroup ฟังก์ชันสำหรับลบกลุ่มพร้อมสมาชิกทั้งหมด
+// @Summary Delete group
+// @Description Delete a group and all of its memberships (Admin or organization Admin)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id} [delete]
+func (gc *GroupController) DeleteGroup(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID กลุ่มไม่ถูกต้อง", err)
+	}
+	deleted, err := gc.Groups.ForTenant(tenantID(c)).Delete(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถลบกลุ่มได้", err)
+	}
+	if !deleted {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบกลุ่ม", nil)
+	}
+	return utils.SuccessResponse(c, "ลบกลุ่มสำเร็จ", nil)
+}
+
+// GetGroupMembers ฟังก์ชันสำหรับดูสมาชิกของกลุ่ม (Admin หรือ owner ของกลุ่ม)
+// @Summary List group members
+// @Description List members of a group with their role (Admin, organization Admin or group owner)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Success 200 {object} utils.Response{data=[]models.GroupMember}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id}/members [get]
+func (gc *GroupController) GetGroupMembers(c *fiber.Ctx) error {
+	group, err := gc.findManagedGroup(c)
+	if err != nil || group == nil {
+		return err
+	}
+	members, err := gc.Groups.Members(c.UserContext(), group.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลสมาชิกได้", err)
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลสมาชิกสำเร็จ", members)
+}
+
+// SetGroupMember ฟังก์ชันสำหรับเพิ่มผู้ใช้เข้ากลุ่มหรือเปลี่ยน role ในกลุ่ม (Admin หรือ owner ของกลุ่ม)
+// ผู้ใช้ต้องเป็นสมาชิกขององค์กรเดียวกับกลุ่ม
+// @Summary Add or update group member
+// @Description Add a user of the group's organization to the group or change their role (Admin, organization Admin or group owner)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Param userId path int true "User ID"
+// @Param membership body models.GroupMemberUpdate true "Role in the group"
+// @Success 200 {object} utils.Response "Role updated"
+// @Success 201 {object} utils.Response "Member added"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id}/members/{userId} [put]
+func (gc *GroupController) SetGroupMember(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	var input models.GroupMemberUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := gc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if input.Role == "" {
+		input.Role = "member"
+	}
+
+	group, err := gc.findManagedGroup(c)
+	if err != nil || group == nil {
+		return err
+	}
+
+	// กลุ่มขององค์กรรับเฉพาะสมาชิกขององค์กรนั้น (ผู้ใช้ขององค์กรอื่นถือว่าไม่พบ)
+	orgID := 0
+	if group.OrganizationID != nil {
+		orgID = *group.OrganizationID
+	}
+	if _, err := gc.Users.ForTenant(orgID).GetByID(c.UserContext(), userID); errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	} else if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+
+	added, err := gc.Groups.SetMember(c.UserContext(), group.ID, userID, input.Role)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกสมาชิกได้", err)
+	}
+	data := fiber.Map{"group_id": group.ID, "user_id": userID, "role": input.Role}
+	if added {
+		return utils.CreatedResponse(c, "เพิ่มสมาชิกสำเร็จ", data)
+	}
+	return utils.SuccessResponse(c, "เปลี่ยน role ในกลุ่มสำเร็จ", data)
+}
+
+// RemoveGroupMember ฟังก์ชันสำหรับนำผู้ใช้ออกจากกลุ่ม (Admin หรือ owner ของกลุ่ม)
+// @Summary Remove group member
+// @Description Remove a user from a group (Admin, organization Admin or group owner)
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Group ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /groups/{id}/members/{userId} [delete]
+func (gc *GroupController) RemoveGroupMember(c *fiber.Ctx) error {
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	group, err := gc.findManagedGroup(c)
+	if err != nil || group == nil {
+		return err
+	}
+	removed, err := gc.Groups.RemoveMember(c.UserContext(), group.ID, userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถนำสมาชิกออกได้", err)
+	}
+	if !removed {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ผู้ใช้ไม่ได้เป็นสมาชิกของกลุ่มนี้", nil)
+	}
+	return utils.SuccessResponse(c, "นำสมาชิกออกจากกลุ่มสำเร็จ", nil)
+}
+
+// GetMyGroups ฟังก์ชันสำหรับดูกลุ่มในองค์กรที่เลือกซึ่งผู้ใช้เป็นสมาชิก พร้อม role ในแต่ละกลุ่ม
+// @Summary List my groups
+// @Description List groups of the selected organization the current user belongs to, with their role in each
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.GroupMembership}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/profile/groups [get]
+func (gc *GroupController) GetMyGroups(c *fiber.Ctx) error {
+	groups, err := gc.Groups.ForTenant(tenantID(c)).ForUser(c.UserContext(), c.Locals("user_id").(int))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลกลุ่มได้", err)
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลกลุ่มสำเร็จ", groups)
+}
+
+// findGroup อ่านกลุ่มในองค์กรที่เลือกตาม :id ของเส้นทาง
+// คืนค่า nil เมื่อ ID ไม่ถูกต้องหรือไม่พบกลุ่ม (ส่ง response ข้อผิดพลาดไปแล้ว)
+func (gc *GroupController) findGroup(c *fiber.Ctx) (*models.Group, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID กลุ่มไม่ถูกต้อง", err)
+	}
+	group, err := gc.Groups.ForTenant(tenantID(c)).GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบกลุ่ม", nil)
+	}
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	return group, nil
+}
+
+// findManagedGroup อ่านกลุ่มเหมือน findGroup และตรวจว่าผู้ใช้จัดการสมาชิกของกลุ่มได้
+// (Admin ของระบบ, Admin ขององค์กรที่เลือก หรือ owner ของกลุ่ม) คืนค่า nil เมื่อไม่มีสิทธิ์ (ส่ง 403 ไปแล้ว)
+func (gc *GroupController) findManagedGroup(c *fiber.Ctx) (*models.Group, error) {
+	group, err := gc.findGroup(c)
+	if err != nil || group == nil {
+		return nil, err
+	}
+	if c.Locals("role") == "admin" || (tenantID(c) != 0 && c.Locals("tenant_role") == "admin") {
+		return group, nil
+	}
+
+	role, err := gc.Groups.MemberRole(c.UserContext(), group.ID, c.Locals("user_id").(int))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบสิทธิ์ในกลุ่มได้", err)
+	}
+	if role != "owner" {
+		return nil, utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเป็น Admin หรือ owner ของกลุ่มเท่านั้น", nil)
+	}
+	return group, nil
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImpersonateUser ฟังก์ชันสำหรับให้ Admin ของระบบเข้าสู่ระบบแทนผู้ใช้ เพื่อให้ฝ่ายสนับสนุนตรวจปัญหาที่ผู้ใช้พบ
+// ออก token อายุสั้น (JWT_IMPERSONATION_EXPIRE) ของผู้ใช้เป้าหมายที่มี claim act เป็น Admin ผูกกับ session ใหม่ของผู้ใช้
+// ผู้ใช้เห็น session นี้ในรายการอุปกรณ์พร้อม impersonator_id และทุก request ของ token ถูกบันทึกใน audit log
+// ต้องเรียกด้วย token ที่ Admin เข้าสู่ระบบเอง (ไม่ใช่ API key หรือ token ของแอป) และเข้าสู่ระบบแทน Admin คนอื่นไม่ได้
+// @Summary Impersonate user
+// @Description Mint a short-lived token for the user with an act claim naming the calling Admin, for support staff to reproduce issues. The token is bound to a new session of the user (listed with impersonator_id) and is never set as a cookie. Every request made with it is audited, and it cannot change the password, revoke sessions or authorize apps. Only platform Admins logged in with their own token may call this, and other Admins cannot be impersonated
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param impersonation body models.ImpersonationRequest false "Reason (recorded in the audit log)"
+// @Success 201 {object} utils.Response{data=models.ImpersonationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "User is suspended or pending"
+// @Failure 500 {object} utils.Response
+// @Router /users/{id}/impersonate [post]
+func (uc *UserController) ImpersonateUser(c *fiber.Ctx) error {
+	// ผู้เรียกต้องเป็น Admin ของระบบที่เข้าสู่ระบบเอง (session_id มีเฉพาะ token ของผู้ใช้ ไม่มีใน API key และ token ของแอป)
+	if _, ok := c.Locals("session_id").(int); !ok || c.Locals("role") != "admin" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเป็น Admin ของระบบที่เข้าสู่ระบบด้วยบัญชีของตัวเองเท่านั้น", nil)
+	}
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	var input models.ImpersonationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+		}
+	}
+	if err := uc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	actorID := c.Locals("user_id").(int)
+	actorName, _ := c.Locals("username").(string)
+	if id == actorID {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถเข้าสู่ระบบแทนตัวเองได้", nil)
+	}
+
+	// ผู้ใช้เป้าหมายต้องอยู่ในองค์กรที่เลือก (ถ้ามี) token ที่ออกจะอยู่ในองค์กรเดียวกัน
+	tenant := tenantID(c)
+	user, err := uc.Users.ForTenant(tenant).GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
+	}
+	if user.Role == "admin" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ไม่สามารถเข้าสู่ระบบแทน Admin ได้", nil)
+	}
+	if user.EffectiveStatus(time.Now()) != models.UserStatusActive {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "บัญชีผู้ใช้ถูกระงับหรือยังไม่เปิดใช้งาน", nil)
+	}
+
+	cfg := uc.Config.Get()
+	claims := &utils.JWTClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		TenantID: tenant,
+		Act:      &utils.Actor{Subject: strconv.Itoa(actorID), UserID: actorID, Username: actorName},
+	}
+	claims.Issuer = "GoTemplate"
+	claims.Subject = strconv.Itoa(user.ID)
+	token, err := utils.GenerateAccessToken(claims, cfg.JWT.Secret, cfg.JWT.ImpersonationExpire)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง token ได้", err)
+	}
+
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	sessionID, err := uc.Sessions.Create(c.UserContext(), &models.Session{
+		UserID:         user.ID,
+		JTI:            claims.ID,
+		Device:         "Impersonation by " + actorName,
+		UserAgent:      userAgent,
+		IPAddress:      c.IP(),
+		ExpiresAt:      claims.ExpiresAt.Time,
+		ImpersonatorID: &actorID,
+	})
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง session ได้", err)
+	}
+
+	audit := &models.AuditLog{ActorID: &actorID, Action: "users.impersonate", IPAddress: c.IP()}
+	if tenant != 0 {
+		audit.OrganizationID = &tenant
+	}
+	details := map[string]interface{}{
+		"user_id":    user.ID,
+		"username":   user.Username,
+		"session_id": sessionID,
+		"expires_at": claims.ExpiresAt.Time.UTC(),
+	}
+	if input.Reason != "" {
+		details["reason"] = input.Reason
+	}
+	if err := uc.Audit.Record(c.UserContext(), audit, details); err != nil {
+		// ไม่ให้ใช้ token ที่ไม่มีบันทึกการออก
+		if _, revokeErr := uc.Sessions.Revoke(c.UserContext(), user.ID, sessionID); revokeErr != nil {
+			err = errors.Join(err, revokeErr)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึก audit log ได้", err)
+	}
+
+	return utils.CreatedResponse(c, "เข้าสู่ระบบแทนผู้ใช้สำเร็จ", models.ImpersonationResponse{
+		Token:        token,
+		ExpiresAt:    claims.ExpiresAt.Time,
+		User:         userResponse(cfg, user),
+		Impersonator: models.Impersonator{ID: actorID, Username: actorName},
+		AuditID:      audit.ID,
+	})
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/mail"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// InvitationController โครงสร้างสำหรับจัดการคำเชิญผู้ใช้ (เฉพาะ Admin ของระบบหรือขององค์กร)
+// การรับคำเชิญอยู่ที่ AuthController.AcceptInvite เพราะผู้รับเชิญยังไม่มีบัญชี
+// Admin ขององค์กรเห็นและสร้างได้เฉพาะคำเชิญขององค์กรที่เลือก (role ของคำเชิญเป็น role ในองค์กร)
+type InvitationController struct {
+	Config        *config.Store                      // การตั้งค่าระบบ
+	DB            *sqlx.DB                           // การเชื่อมต่อฐานข้อมูล
+	Invitations   *repository.InvitationRepository   // การเข้าถึงตาราง invitations
+	Organizations *repository.OrganizationRepository // ชื่อองค์กรในอีเมลคำเชิญ
+	Validator     *validator.Validate                // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewInvitationController ฟังก์ชันสร้าง InvitationController ใหม่
+func NewInvitationController(cfg *config.Store, db *sqlx.DB) *InvitationController {
+	return &InvitationController{
+		Config:        cfg,
+		DB:            db,
+		Invitations:   repository.NewInvitationRepository(db),
+		Organizations: repository.NewOrganizationRepository(db),
+		Validator:     validator.New(),
+	}
+}
+
+// CreateInvitation ฟังก์ชันสำหรับเชิญผู้ใช้ด้วยอีเมลและ role ที่กำหนดไว้ล่วงหน้า (เฉพาะ Admin ของระบบหรือขององค์กร)
+// token คำเชิญถูกส่งทางอีเมลเท่านั้น ไม่ส่งกลับใน response
+// @Summary Invite user
+// @Description Invite an email address with a preassigned role (Admin or organization Admin). The single-use token is sent by email.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param invitation body models.InvitationCreate true "Invitation data"
+// @Success 201 {object} utils.Response{data=models.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 502 {object} utils.Response "Invitation created but the email could not be sent"
+// @Router /users/invitations [post]
+func (ic *InvitationController) CreateInvitation(c *fiber.Ctx) error {
+	var input models.InvitationCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	input.Email = strings.TrimSpace(input.Email)
+	if err := ic.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if input.Role == "" {
+		input.Role = "user"
+	}
+
+	// อีเมลต้องยังไม่มีบัญชี และยังไม่มีคำเชิญที่รับได้อยู่ (ใช้การส่งใหม่แทนการเชิญซ้ำ)
+	var existing models.User
+	query := "SELECT id FROM users WHERE LOWER(email) = LOWER(?)"
+	err := ic.DB.GetContext(c.UserContext(), &existing, ic.DB.Rebind(query), input.Email)
+	if err == nil {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "มีผู้ใช้ที่ใช้อีเมลนี้อยู่แล้ว", nil)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	pending, err := ic.Invitations.HasPending(c.UserContext(), input.Email)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	if pending {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "มีคำเชิญที่ยังไม่หมดอายุสำหรับอีเมลนี้อยู่แล้ว ใช้การส่งคำเชิญใหม่แทน", nil)
+	}
+
+	token, hash, err := utils.GenerateInviteToken()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างคำเชิญได้", err)
+	}
+	cfg := ic.Config.Get()
+	inv := models.Invitation{
+		Email:     input.Email,
+		Role:      input.Role,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(cfg.Registration.InviteTTL),
+	}
+	if userID, ok := c.Locals("user_id").(int); ok {
+		inv.InvitedBy = &userID
+	}
+	invitations := ic.Invitations.ForTenant(tenantID(c))
+	id, err := invitations.Create(c.UserContext(), &inv)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกคำเชิญได้", err)
+	}
+	created, err := invitations.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลคำเชิญได้", err)
+	}
+
+	if err := mail.New(cfg.Mail).Send(c.UserContext(), ic.invitationMessage(c, cfg, created, token)); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "สร้างคำเชิญแล้วแต่ส่งอีเมลไม่สำเร็จ กรุณาส่งคำเชิญใหม่", err)
+	}
+	return utils.CreatedResponse(c, "ส่งคำเชิญสำเร็จ", created.ConvertToResponse())
+}
+
+// GetInvitations ฟังก์ชันสำหรับดูรายการคำเชิญ (เฉพาะ Admin ของระบบหรือขององค์กร)
+// @Summary List invitations
+// @Description List invitations, optionally filtered by status (Admin or organization Admin)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param status query string false "Filter by status" Enums(pending, accepted, revoked, expired)
+// @Success 200 {object} utils.Response{data=[]models.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/invitations [get]
+func (ic *InvitationController) GetInvitations(c *fiber.Ctx) error {
+	status := c.Query("status")
+	switch status {
+	case "", models.InvitationPending, models.InvitationAccepted, models.InvitationRevoked, models.InvitationExpired:
+	default:
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "status ไม่ถูกต้อง", nil)
+	}
+
+	invitations, err := ic.Invitations.ForTenant(tenantID(c)).List(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลคำเชิญได้", err)
+	}
+	responses := make([]models.InvitationResponse, 0, len(invitations))
+	for _, inv := range invitations {
+		response := inv.ConvertToResponse()
+		if status == "" || response.Status == status {
+			responses = append(responses, response)
+		}
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลคำเชิญสำเร็จ", responses)
+}
+
+// ResendInvitation ฟังก์ชันสำหรับส่งคำเชิญใหม่ (เฉพาะ Admin ของระบบหรือขององค์กร)
+// ออก token ใหม่และนับอายุใหม่ token เดิมใช้ไม่ได้อีก ใช้ได้กับคำเชิญที่ยังรออยู่หรือหมดอายุแล้ว
+// @Summary Resend invitation
+// @Description Issue a new token for a pending or expired invitation and email it again (Admin or organization Admin). The previous token stops working.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} utils.Response{data=models.InvitationResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /users/invitations/{id}/resend [post]
+func (ic *InvitationController) ResendInvitation(c *fiber.Ctx) error {
+	inv, err := ic.findInvitation(c)
+	if err != nil || inv == nil {
+		return err
+	}
+
+	token, hash, err := utils.GenerateInviteToken()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างคำเชิญได้", err)
+	}
+	cfg := ic.Config.Get()
+	invitations := ic.Invitations.ForTenant(tenantID(c))
+	renewed, err := invitations.Renew(c.UserContext(), inv.ID, hash, time.Now().Add(cfg.Registration.InviteTTL))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกคำเชิญได้", err)
+	}
+	if !renewed {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "คำเชิญถูกรับหรือเพิกถอนไปแล้ว", nil)
+	}
+	if inv, err = invitations.GetByID(c.UserContext(), inv.ID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลคำเชิญได้", err)
+	}
+
+	if err := mail.New(cfg.Mail).Send(c.UserContext(), ic.invitationMessage(c, cfg, inv, token)); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadGateway, "ส่งอีเมลคำเชิญไม่สำเร็จ", err)
+	}
+	return utils.SuccessResponse(c, "ส่งคำเชิญใหม่สำเร็จ", inv.ConvertToResponse())
+}
+
+// RevokeInvitation ฟังก์ชันสำหรับเพิกถอนคำเชิญที่ยังไม่ถูกรับ (เฉพาะ Admin ของระบบหรือขององค์กร)
+// @Summary Revoke invitation
+// @Description Revoke an invitation that has not been accepted yet (Admin or organization Admin)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/invitations/{id} [delete]
+func (ic *InvitationController) RevokeInvitation(c *fiber.Ctx) error {
+	inv, err := ic.findInvitation(c)
+	if err != nil || inv == nil {
+		return err
+	}
+	revoked, err := ic.Invitations.ForTenant(tenantID(c)).Revoke(c.UserContext(), inv.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเพิกถอนคำเชิญได้", err)
+	}
+	if !revoked {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "คำเชิญถูกรับหรือเพิกถอนไปแล้ว", nil)
+	}
+	return utils.SuccessResponse(c, "เพิกถอนคำเชิญสำเร็จ", nil)
+}
+
+// findInvitation อ่านคำเชิญตาม :id ในเส้นทาง (เฉพาะคำเชิญขององค์กรที่เลือก)
+// คืนค่าคำเชิญเป็น nil เมื่อส่ง response ข้อผิดพลาด (400/404/500) ไปแล้ว
+func (ic *InvitationController) findInvitation(c *fiber.Ctx) (*models.Invitation, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของคำเชิญไม่ถูกต้อง", err)
+	}
+	inv, err := ic.Invitations.ForTenant(tenantID(c)).GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบคำเชิญ", nil)
+	}
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลคำเชิญได้", err)
+	}
+	return inv, nil
+}
+
+// invitationMessage สร้างอีเมลคำเชิญ ลิงก์ชี้ไปที่ INVITE_URL พร้อม token
+// หากไม่ได้กำหนด INVITE_URL จะส่งเฉพาะ token ให้นำไปใช้กับ POST /api/v1/auth/accept-invite
+// คำเชิญขององค์กรระบุชื่อองค์กรในอีเมลด้วย
+func (ic *InvitationController) invitationMessage(c *fiber.Ctx, cfg *config.Config, inv *models.Invitation, token string) mail.Message {
+	name := cfg.App.Name
+	if inv.OrganizationID != nil {
+		if org, err := ic.Organizations.GetByID(c.UserContext(), *inv.OrganizationID); err == nil {
+			name = org.Name + " (" + cfg.App.Name + ")"
+		}
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "คุณได้รับเชิญให้เข้าร่วม %s ในฐานะ %s\n\n", name, inv.Role)
+	if link, err := url.Parse(cfg.Registration.InviteURL); err == nil && cfg.Registration.InviteURL != "" {
+		query := link.Query()
+		query.Set("token", token)
+		link.RawQuery = query.Encode()
+		fmt.Fprintf(&body, "ตั้งชื่อผู้ใช้และรหัสผ่านเพื่อเริ่มใช้งานได้ที่:\n%s\n\n", link.String())
+	} else {
+		fmt.Fprintf(&body, "ใช้ token นี้เพื่อตั้งชื่อผู้ใช้และรหัสผ่าน:\n%s\n\n", token)
+	}
+	fmt.Fprintf(&body, "คำเชิญนี้ใช้ได้ครั้งเดียวและหมดอายุเมื่อ %s\n", inv.ExpiresAt.Format("2006-01-02 15:04 MST"))
+	body.WriteString("หากคุณไม่ได้คาดว่าจะได้รับคำเชิญนี้ สามารถละเว้นอีเมลฉบับนี้ได้\n")
+
+	return mail.Message{
+		To:      inv.Email,
+		Subject: "คำเชิญเข้าร่วม " + name,
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// OAuthClientController โครงสร้างสำหรับลงทะเบียนแอปกับ OAuth2 authorization server (เฉพาะ Admin)
+type OAuthClientController struct {
+	Config    *config.Store               // การตั้งค่าระบบ
+	DB        *sqlx.DB                    // การเชื่อมต่อฐานข้อมูล
+	OAuth     *repository.OAuthRepository // การเข้าถึงตาราง oauth_*
+	Validator *validator.Validate         // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewOAuthClientController ฟังก์ชันสร้าง OAuthClientController ใหม่
+func NewOAuthClientController(cfg *config.Store, db *sqlx.DB) *OAuthClientController {
+	return &OAuthClientController{
+		Config:    cfg,
+		DB:        db,
+		OAuth:     repository.NewOAuthRepository(db),
+		Validator: validator.New(),
+	}
+}
+
+// CreateOAuthClient ฟังก์ชันสำหรับลงทะเบียนแอปใหม่ (เฉพาะ Admin)
+// client secret ของ confidential client จะถูกส่งกลับเพียงครั้งเดียวใน response นี้ ระบบเก็บเฉพาะ hash
+// @Summary Register OAuth client
+// @Description Register an application with the OAuth2 authorization server (Admin only). The client secret is returned only once.
+// @Tags oauth-clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param client body models.OAuthClientCreate true "Client data"
+// @Success 201 {object} utils.Response{data=models.OAuthClientResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /oauth/clients [post]
+func (occ *OAuthClientController) CreateOAuthClient(c *fiber.Ctx) error {
+	var input models.OAuthClientCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := occ.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	client := models.OAuthClient{
+		Name:         input.Name,
+		RedirectURIs: strings.Join(input.RedirectURIs, " "),
+		GrantTypes:   strings.Join(input.GrantTypes, " "),
+		Scopes:       strings.Join(input.Scopes, " "),
+		FirstParty:   input.FirstParty,
+	}
+	if client.AllowsGrant(models.GrantAuthorizationCode) && len(input.RedirectURIs) == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "grant แบบ authorization_code ต้องระบุ redirect_uris อย่างน้อยหนึ่งรายการ", nil)
+	}
+	// public client เก็บ secret ไม่ได้ จึงขอ token ในนามตัวเองไม่ได้
+	if input.Public && client.AllowsGrant(models.GrantClientCredentials) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "public client ใช้ grant แบบ client_credentials ไม่ได้", nil)
+	}
+
+	clientID, err := utils.GenerateOAuthClientID()
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง client_id ได้", err)
+	}
+	client.ClientID = clientID
+
+	secret := ""
+	if !input.Public {
+		secret, client.SecretHash, err = utils.GenerateOAuthToken(utils.OAuthClientSecretPrefix)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง client secret ได้", err)
+		}
+	}
+
+	id, err := occ.OAuth.CreateClient(c.UserContext(), &client)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกแอปได้", err)
+	}
+	created, err := occ.OAuth.GetClientByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลแอปได้", err)
+	}
+
+	response := created.ConvertToResponse()
+	response.ClientSecret = secret // แสดง secret เพียงครั้งเดียว
+	return utils.CreatedResponse(c, "ลงทะเบียนแอปสำเร็จ กรุณาเก็บ client secret ไว้ เนื่องจากจะไม่แสดงอีก", response)
+}
+
+// GetOAuthClients ฟังก์ชันสำหรับดูรายการแอปที่ลงทะเบียนไว้ (เฉพาะ Admin)
+// @Summary List OAuth clients
+// @Description List applications registered with the OAuth2 authorization server (Admin only)
+// @Tags oauth-clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.OAuthClientResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /oauth/clients [get]
+func (occ *OAuthClientController) GetOAuthClients(c *fiber.Ctx) error {
+	clients, err := occ.OAuth.ListClients(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลแอปได้", err)
+	}
+
+	responses := make([]models.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		responses = append(responses, client.ConvertToResponse())
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลแอปสำเร็จ", responses)
+}
+
+// GetOAuthClient ฟังก์ชันสำหรับดูข้อมูลแอปตาม ID (เฉพาะ Admin)
+// @Summary Get OAuth client
+// @Description Get a registered application by ID (Admin only)
+// @Tags oauth-clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Client ID (numeric)"
+// @Success 200 {object} utils.Response{data=models.OAuthClientResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /oauth/clients/{id} [get]
+func (occ *OAuthClientController) GetOAuthClient(c *fiber.Ctx) error {
+	client, err := occ.findClient(c)
+	if err != nil || client == nil {
+		return err
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลแอปสำเร็จ", client.ConvertToResponse())
+}
+
+// DeleteOAuthClient ฟังก์ชันสำหรับลบแอป (เฉพาะ Admin)
+// code, refresh token และความยินยอมของผู้ใช้ที่มีต่อแอปจะถูกลบด้วย
+// @Summary Delete OAuth client
+// @Description Delete a registered application together with its codes, refresh tokens and consents (Admin only)
+// @Tags oauth-clients
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Client ID (numeric)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /oauth/clients/{id} [delete]
+func (occ *OAuthClientController) DeleteOAuthClient(c *fiber.Ctx) error {
+	client, err := occ.findClient(c)
+	if err != nil || client == nil {
+		return err
+	}
+	if err := occ.OAuth.DeleteClient(c.UserContext(), client.ClientID); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถลบแอปได้", err)
+	}
+	return utils.SuccessResponse(c, "ลบแอปสำเร็จ", nil)
+}
+
+// findClient อ่านแอปตาม :id ในเส้นทาง
+// คืนค่า client เป็น nil เมื่อส่ง response ข้อผิดพลาด (400/404/500) ไปแล้ว
+func (occ *OAuthClientController) findClient(c *fiber.Ctx) (*models.OAuthClient, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของแอปไม่ถูกต้อง", err)
+	}
+	client, err := occ.OAuth.GetClientByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบแอป", nil)
+	}
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลแอปได้", err)
+	}
+	return client, nil
+}
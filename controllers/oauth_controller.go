@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/oauth"
+	"github.com/Sing254463/GoTemplate/Backend/password"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// oauthFlowCookie ชื่อ cookie ที่เก็บ state, nonce และ PKCE verifier ระหว่าง /start และ /callback
+const oauthFlowCookie = "oauth_flow"
+
+// oauthCookiePath จำกัดให้ browser ส่ง cookie ของ flow เฉพาะเส้นทาง OAuth
+const oauthCookiePath = "/api/v1/auth/oauth"
+
+// invalidUsernameChars ตัวอักษรที่ไม่ใช้ในชื่อผู้ใช้ที่สร้างจากข้อมูลของ provider
+var invalidUsernameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// OAuthController โครงสร้างสำหรับจัดการการเข้าสู่ระบบผ่าน OIDC/OAuth2 (Google, GitHub)
+type OAuthController struct {
+	Config        *config.Store                      // การตั้งค่าระบบ (OAuth, JWT)
+	DB            *sqlx.DB                           // การเชื่อมต่อฐานข้อมูล
+	Providers     *oauth.Registry                    // provider ที่เปิดใช้งานตามการตั้งค่าปัจจุบัน
+	Identities    *repository.IdentityRepository     // การเข้าถึงตาราง linked_identities
+	Sessions      *repository.SessionRepository      // การเข้าถึงตาราง sessions
+	Organizations *repository.OrganizationRepository // การเลือกองค์กรตอนเข้าสู่ระบบ
+}
+
+// NewOAuthController ฟังก์ชันสร้าง OAuthController ใหม่
+func NewOAuthController(cfg *config.Store, db *sqlx.DB) *OAuthController {
+	return &OAuthController{
+		Config:        cfg,
+		DB:            db,
+		Providers:     oauth.NewRegistry(cfg),
+		Identities:    repository.NewIdentityRepository(db),
+		Sessions:      repository.NewSessionRepository(db),
+		Organizations: repository.NewOrganizationRepository(db),
+	}
+}
+
+// StartOAuth ฟังก์ชันเริ่มการเข้าสู่ระบบผ่าน provider
+// สร้าง state, nonce และ PKCE verifier เก็บใน cookie ที่เซ็นแล้ว แล้ว redirect ไปยังหน้าเข้าสู่ระบบของ provider
+// @Summary Start OAuth login
+// @Description Redirect to the provider login page (Authorization Code flow with PKCE, state and nonce)
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider" Enums(google, github)
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /auth/oauth/{provider}/start [get]
+func (oc *OAuthController) StartOAuth(c *fiber.Ctx) error {
+	name := c.Params("provider")
+	provider, err := oc.Providers.Provider(c.UserContext(), name)
+	if err != nil {
+		return providerError(c, err)
+	}
+
+	flow, err := oauth.NewFlow(name)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเริ่มการเข้าสู่ระบบได้", err)
+	}
+	cfg := oc.Config.Get()
+	sealed, err := flow.Seal(cfg.JWT.Secret, cfg.OAuth.StateTTL)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเริ่มการเข้าสู่ระบบได้", err)
+	}
+
+	// SameSite=Lax เพื่อให้ browser ส่ง cookie กลับมาตอนที่ provider redirect มายัง callback
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthFlowCookie,
+		Value:    sealed,
+		Path:     oauthCookiePath,
+		MaxAge:   int(cfg.OAuth.StateTTL / time.Second),
+		Secure:   strings.HasPrefix(cfg.OAuth.RedirectBaseURL, "https://"),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(provider.AuthCodeURL(flow.State, flow.Nonce, flow.Verifier), fiber.StatusFound)
+}
+
+// OAuthCallback ฟังก์ชันรับผลการเข้าสู่ระบบจาก provider
+// ตรวจ state กับ cookie, แลก code ด้วย PKCE verifier, ตรวจ id_token และ nonce (OIDC)
+// แล้วจับคู่ผู้ใช้ตามบัญชีที่เชื่อมไว้ หรือตามอีเมลที่ provider ยืนยันแล้ว (สร้างผู้ใช้ใหม่หากยังไม่มี)
+// @Summary OAuth login callback
+// @Description Complete the provider login and return our own JWT token
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider" Enums(google, github)
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the start step"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Failure 502 {object} utils.Response
+// @Router /auth/oauth/{provider}/callback [get]
+func (oc *OAuthController) OAuthCallback(c *fiber.Ctx) error {
+	name := c.Params("provider")
+	cfg := oc.Config.Get()
+
+	// cookie ของ flow ใช้ได้ครั้งเดียว ลบทิ้งทันทีไม่ว่าผลจะเป็นอย่างไร
+	sealed := c.Cookies(oauthFlowCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthFlowCookie,
+		Path:     oauthCookiePath,
+		Expires:  time.Unix(0, 0), // fasthttp ไม่เขียน Max-Age ที่ติดลบ จึงลบ cookie ด้วย Expires ในอดีต
+		Secure:   strings.HasPrefix(cfg.OAuth.RedirectBaseURL, "https://"),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	// ผู้ใช้ยกเลิกหรือ provider ปฏิเสธ (เช่น error=access_denied)
+	if providerErr := c.Query("error"); providerErr != "" {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "ผู้ให้บริการปฏิเสธการเข้าสู่ระบบ", errors.New(providerErr+": "+c.Query("error_description")))
+	}
+
+	if sealed == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบสถานะการเข้าสู่ระบบ กรุณาเริ่มใหม่", nil)
+	}
+	flow, err := oauth.OpenFlow(sealed, cfg.JWT.Secret)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "สถานะการเข้าสู่ระบบไม่ถูกต้องหรือหมดอายุ กรุณาเริ่มใหม่", err)
+	}
+	if flow.Provider != name || subtle.ConstantTimeCompare([]byte(flow.State), []byte(c.Query("state"))) != 1 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "state ไม่ตรงกัน", nil)
+	}
+	code := c.Query("code")
+	if code == "" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบ authorization code", nil)
+	}
+
+	provider, err := oc.Providers.Provider(c.UserContext(), name)
+	if err != nil {
+		return providerError(c, err)
+	}
+	identity, err := provider.Identify(c.UserContext(), code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusUnauthorized, "ไม่สามารถยืนยันตัวตนกับผู้ให้บริการได้", err)
+	}
+
+	// 1. บัญชีนี้เคยเชื่อมไว้แล้ว
+	user, err := oc.Identities.FindUser(c.UserContext(), identity.Provider, identity.Subject)
+	created := false
+	if errors.Is(err, repository.ErrNotFound) {
+		// 2. ยังไม่เคยเชื่อม: จับคู่หรือสร้างผู้ใช้จากอีเมล ซึ่งต้องได้รับการยืนยันจาก provider แล้วเท่านั้น
+		//    (มิฉะนั้นใครก็อ้างอีเมลของผู้อื่นเพื่อยึดบัญชีได้)
+		if identity.Email == "" || !identity.EmailVerified {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "บัญชีของผู้ให้บริการยังไม่ได้ยืนยันอีเมล", nil)
+		}
+		//    เมื่อปิดการลงทะเบียนด้วยตนเอง จะเชื่อมได้เฉพาะบัญชีที่มีอยู่แล้วเท่านั้น
+		var newUser *models.User
+		if cfg.Registration.Enabled {
+			if newUser, err = newOAuthUser(identity, password.NewHasher(cfg.Password)); err != nil {
+				return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างผู้ใช้ได้", err)
+			}
+		}
+		user, created, err = oc.Identities.LinkByEmail(c.UserContext(), &models.LinkedIdentity{
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}, newUser)
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "ไม่พบบัญชีที่ใช้อีเมลนี้ และปิดการลงทะเบียนด้วยตนเอง กรุณาติดต่อผู้ดูแลระบบเพื่อขอคำเชิญ", nil)
+		}
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเชื่อมบัญชีได้", err)
+		}
+	} else if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+
+	if status := user.EffectiveStatus(time.Now()); status != models.UserStatusActive {
+		return utils.AccountStatusResponse(c, status, user.SuspendedUntil)
+	}
+
+	// เลือกองค์กรเหมือนการเข้าสู่ระบบด้วยรหัสผ่าน (header หรือ subdomain ของ callback)
+	tenant, err := chooseTenant(c.UserContext(), utils.TenantFromRequest(c, cfg.Tenant), oc.Organizations, user)
+	if err != nil {
+		return tenantError(c, err)
+	}
+
+	data := fiber.Map{
+		"user":     userResponse(cfg, user), // ข้อมูลผู้ใช้ (ไม่รวมรหัสผ่าน)
+		"provider": identity.Provider,       // provider ที่ใช้เข้าสู่ระบบ
+		"created":  created,                 // true = สร้างผู้ใช้ใหม่จากบัญชีนี้
+	}
+	// ออก JWT และ session ของระบบเอง เหมือนการเข้าสู่ระบบด้วยรหัสผ่าน
+	if err := startSession(c, cfg, oc.Sessions, user, tenant, data); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง session ได้", err)
+	}
+	return utils.SuccessResponse(c, "เข้าสู่ระบบสำเร็จ", data)
+}
+
+// providerError แปลงข้อผิดพลาดจาก oauth.Registry เป็น response
+func providerError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, oauth.ErrUnknownProvider) || errors.Is(err, oauth.ErrProviderDisabled) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่รองรับการเข้าสู่ระบบผ่านผู้ให้บริการนี้", err)
+	}
+	return utils.ErrorResponse(c, fiber.StatusBadGateway, "ไม่สามารถติดต่อผู้ให้บริการได้", err)
+}
+
+// newOAuthUser สร้างข้อมูลผู้ใช้ใหม่จากบัญชีของ provider
+// รหัสผ่านเป็นค่าสุ่มที่ไม่มีใครรู้ ผู้ใช้จึงเข้าสู่ระบบได้ผ่าน provider เท่านั้น
+func newOAuthUser(identity *oauth.Identity, hasher password.Hasher) (*models.User, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := hasher.Hash(hex.EncodeToString(random))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		Username: oauthUsername(identity),
+		Email:    identity.Email,
+		Password: hashedPassword,
+		Role:     "user", // ผู้ใช้ที่สร้างจาก provider เป็น user เสมอ
+		Status:   models.UserStatusActive,
+	}, nil
+}
+
+// oauthUsername เลือกชื่อผู้ใช้จากชื่อที่ provider แนะนำ หรือส่วนหน้า @ ของอีเมล
+// ให้อยู่ในรูปแบบเดียวกับการลงทะเบียน (3-20 ตัวอักษร)
+func oauthUsername(identity *oauth.Identity) string {
+	username := identity.Username
+	if username == "" {
+		username, _, _ = strings.Cut(identity.Email, "@")
+	}
+	username = invalidUsernameChars.ReplaceAllString(username, "")
+	if len(username) > 20 {
+		username = username[:20]
+	}
+	if len(username) < 3 {
+		username = "user_" + username
+	}
+	return username
+}
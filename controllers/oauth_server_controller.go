@@ -0,0 +1,709 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// OAuthServerController โครงสร้างสำหรับ OAuth2 authorization server
+// ให้แอปของพาร์ทเนอร์ (และแอปของเราเอง) เข้าสู่ระบบด้วยบัญชีของระบบนี้
+//
+// endpoint ตาม RFC (token, introspect, revoke) ตอบกลับเป็น JSON ตามมาตรฐาน OAuth2
+// ไม่ใช้รูปแบบ utils.Response เพื่อให้ client library ทั่วไปใช้งานได้ทันที
+type OAuthServerController struct {
+	Config *config.Store               // การตั้งค่าระบบ (AuthServer, JWT)
+	DB     *sqlx.DB                    // การเชื่อมต่อฐานข้อมูล
+	OAuth  *repository.OAuthRepository // การเข้าถึงตาราง oauth_*
+}
+
+// NewOAuthServerController ฟังก์ชันสร้าง OAuthServerController ใหม่
+func NewOAuthServerController(cfg *config.Store, db *sqlx.DB) *OAuthServerController {
+	return &OAuthServerController{
+		Config: cfg,
+		DB:     db,
+		OAuth:  repository.NewOAuthRepository(db),
+	}
+}
+
+// Discovery ฟังก์ชันสำหรับเผยแพร่ metadata ของ authorization server (RFC 8414)
+// ให้บริการที่ /.well-known/oauth-authorization-server เท่านั้น (ไม่ใช่ OpenID Provider จึงไม่มี openid-configuration)
+// ระบบไม่ออก id_token (access token เซ็นด้วย HS256) แอปจึงต้องใช้ introspection หรือ /auth/profile เพื่ออ่านข้อมูลผู้ใช้
+func (sc *OAuthServerController) Discovery(c *fiber.Ctx) error {
+	cfg := sc.Config.Get()
+	issuer := strings.TrimRight(cfg.AuthServer.Issuer, "/")
+	authorization := cfg.AuthServer.AuthorizationURL
+	if authorization == "" {
+		authorization = issuer + "/api/v1/oauth/authorize"
+	}
+	clientAuth := []string{"client_secret_basic", "client_secret_post"}
+
+	return c.JSON(models.OAuthDiscovery{
+		Issuer:                        issuer,
+		AuthorizationEndpoint:         authorization,
+		TokenEndpoint:                 issuer + "/api/v1/oauth/token",
+		IntrospectionEndpoint:         issuer + "/api/v1/oauth/introspect",
+		RevocationEndpoint:            issuer + "/api/v1/oauth/revoke",
+		ScopesSupported:               models.OAuthScopes,
+		ResponseTypesSupported:        []string{"code"},
+		GrantTypesSupported:           []string{models.GrantAuthorizationCode, models.GrantClientCredentials, models.GrantRefreshToken},
+		CodeChallengeMethodsSupported: []string{"S256"},
+		// public client ยืนยันตัวตนด้วย client_id + PKCE เท่านั้น
+		TokenEndpointAuthMethodsSupported:         append(clientAuth, "none"),
+		IntrospectionEndpointAuthMethodsSupported: clientAuth,
+		RevocationEndpointAuthMethodsSupported:    append(clientAuth, "none"),
+	})
+}
+
+// Authorize ฟังก์ชันตรวจคำขออนุญาตของแอป (ขั้นแรกของ authorization code flow)
+// หน้า login/consent ของ frontend ส่งพารามิเตอร์ที่ได้จากแอปมาพร้อม token ของผู้ใช้
+// หากแอปเป็น first party หรือผู้ใช้เคยอนุญาต scope เหล่านี้แล้ว จะออก code และคืน redirect_to ทันที
+// มิฉะนั้นคืน consent_required เพื่อให้ frontend แสดงหน้าขออนุญาต แล้วส่งผลมาที่ POST /oauth/authorize
+// @Summary Start OAuth authorization
+// @Description Validate an authorization request for the signed-in user. Returns redirect_to (with code or error) or consent_required.
+// @Tags oauth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param response_type query string true "Must be code" Enums(code)
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string false "Registered redirect URI (optional when only one is registered)"
+// @Param scope query string false "Space-separated scopes"
+// @Param state query string false "Opaque value returned to the client"
+// @Param code_challenge query string true "PKCE code challenge"
+// @Param code_challenge_method query string true "PKCE method" Enums(S256)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /oauth/authorize [get]
+func (sc *OAuthServerController) Authorize(c *fiber.Ctx) error {
+	var req models.OAuthAuthorizeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	client, scopes, err := sc.resolveAuthorize(c, &req)
+	if err != nil || client == nil {
+		return err
+	}
+
+	userID := c.Locals("user_id").(int)
+	if !client.FirstParty {
+		granted, err := sc.OAuth.GetConsent(c.UserContext(), userID, client.ClientID)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+		}
+		if !scopesGranted(granted, scopes) {
+			return utils.SuccessResponse(c, "แอปต้องการความยินยอมจากผู้ใช้", fiber.Map{
+				"consent_required": true,
+				"client":           fiber.Map{"client_id": client.ClientID, "name": client.Name},
+				"scopes":           scopes,
+			})
+		}
+	}
+	return sc.issueCode(c, client, userID, &req, scopes)
+}
+
+// ApproveAuthorize ฟังก์ชันรับผลการตัดสินใจของผู้ใช้จากหน้าขออนุญาต
+// approve=true บันทึกความยินยอมและออก code ส่วน approve=false ส่ง error=access_denied กลับไปยังแอป
+// @Summary Submit OAuth consent
+// @Description Approve or deny an authorization request. Returns redirect_to with a code or error=access_denied.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body models.OAuthAuthorizeRequest true "Authorization request and decision"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /oauth/authorize [post]
+func (sc *OAuthServerController) ApproveAuthorize(c *fiber.Ctx) error {
+	var req models.OAuthAuthorizeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	// ตรวจคำขอซ้ำทั้งหมด เพราะ frontend ส่งพารามิเตอร์มาใหม่
+	client, scopes, err := sc.resolveAuthorize(c, &req)
+	if err != nil || client == nil {
+		return err
+	}
+	if !req.Approve {
+		return authorizeRedirect(c, req.RedirectURI, url.Values{"error": {"access_denied"}, "error_description": {"ผู้ใช้ไม่อนุญาต"}}, req.State)
+	}
+
+	// รวมกับ scope ที่เคยอนุญาตไว้ เพื่อไม่ให้การขอ scope ย่อยลงทำให้ความยินยอมเดิมหายไป
+	userID := c.Locals("user_id").(int)
+	granted, err := sc.OAuth.GetConsent(c.UserContext(), userID, client.ClientID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	merged := strings.Fields(granted)
+	for _, scope := range scopes {
+		if !scopesGranted(granted, []string{scope}) {
+			merged = append(merged, scope)
+		}
+	}
+	if err := sc.OAuth.SaveConsent(c.UserContext(), userID, client.ClientID, strings.Join(merged, " ")); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกความยินยอมได้", err)
+	}
+	return sc.issueCode(c, client, userID, &req, scopes)
+}
+
+// Token ฟังก์ชัน token endpoint (RFC 6749 ข้อ 3.2)
+// รองรับ grant_type: authorization_code (ต้องมี code_verifier), client_credentials และ refresh_token
+// confidential client ยืนยันตัวตนด้วย HTTP Basic หรือ client_id/client_secret ใน body ส่วน public client ส่งเฉพาะ client_id
+// refresh token ถูกหมุนเวียนทุกครั้งที่ใช้ หากพบการใช้ token เดิมซ้ำ token ทั้งหมดของผู้ใช้กับแอปนั้นจะถูกเพิกถอน
+// @Summary OAuth token endpoint
+// @Description Exchange an authorization code, client credentials or a refresh token for an access token
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Grant type" Enums(authorization_code, client_credentials, refresh_token)
+// @Param code formData string false "Authorization code (authorization_code)"
+// @Param redirect_uri formData string false "Redirect URI used in the authorization request (authorization_code)"
+// @Param code_verifier formData string false "PKCE code verifier (authorization_code)"
+// @Param refresh_token formData string false "Refresh token (refresh_token)"
+// @Param scope formData string false "Space-separated scopes (client_credentials, refresh_token)"
+// @Param client_id formData string false "Client ID (when not using HTTP Basic)"
+// @Param client_secret formData string false "Client secret (when not using HTTP Basic)"
+// @Success 200 {object} models.OAuthTokenResponse
+// @Failure 400 {object} models.OAuthErrorResponse
+// @Failure 401 {object} models.OAuthErrorResponse
+// @Failure 500 {object} models.OAuthErrorResponse
+// @Router /oauth/token [post]
+func (sc *OAuthServerController) Token(c *fiber.Ctx) error {
+	client, err := sc.authenticateClient(c, false)
+	if err != nil || client == nil {
+		return err
+	}
+
+	grant := c.FormValue("grant_type")
+	switch grant {
+	case models.GrantAuthorizationCode, models.GrantClientCredentials, models.GrantRefreshToken:
+	case "":
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "ไม่พบ grant_type")
+	default:
+		return oauthError(c, fiber.StatusBadRequest, "unsupported_grant_type", "ไม่รองรับ grant_type "+grant)
+	}
+	if !client.AllowsGrant(grant) {
+		return oauthError(c, fiber.StatusBadRequest, "unauthorized_client", "แอปไม่ได้รับอนุญาตให้ใช้ grant_type "+grant)
+	}
+
+	switch grant {
+	case models.GrantAuthorizationCode:
+		return sc.exchangeCode(c, client)
+	case models.GrantClientCredentials:
+		// แอปขอ token ในนามตัวเอง ต้องพิสูจน์ตัวตนด้วย secret ได้
+		if !client.Confidential() {
+			return oauthError(c, fiber.StatusBadRequest, "unauthorized_client", "public client ใช้ client_credentials ไม่ได้")
+		}
+		scopes := requestedScopes(c.FormValue("scope"), client.Scopes)
+		if len(scopes) == 0 || !client.AllowsScopes(scopes) {
+			return oauthError(c, fiber.StatusBadRequest, "invalid_scope", "scope ไม่ถูกต้องหรือเกินกว่าที่แอปลงทะเบียนไว้")
+		}
+		return sc.issueTokens(c, client, nil, scopes)
+	default:
+		return sc.refresh(c, client)
+	}
+}
+
+// Introspect ฟังก์ชัน introspection endpoint (RFC 7662)
+// ให้ resource server (confidential client) ตรวจว่า access token หรือ refresh token ยังใช้งานได้หรือไม่
+// @Summary OAuth token introspection
+// @Description Check whether an access token or refresh token is active (confidential clients only)
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to inspect"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Param client_id formData string false "Client ID (when not using HTTP Basic)"
+// @Param client_secret formData string false "Client secret (when not using HTTP Basic)"
+// @Success 200 {object} models.OAuthIntrospection
+// @Failure 400 {object} models.OAuthErrorResponse
+// @Failure 401 {object} models.OAuthErrorResponse
+// @Failure 500 {object} models.OAuthErrorResponse
+// @Router /oauth/introspect [post]
+func (sc *OAuthServerController) Introspect(c *fiber.Ctx) error {
+	client, err := sc.authenticateClient(c, true)
+	if err != nil || client == nil {
+		return err
+	}
+	token := c.FormValue("token")
+	if token == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "ไม่พบ token")
+	}
+
+	cfg := sc.Config.Get()
+	issuer := strings.TrimRight(cfg.AuthServer.Issuer, "/")
+	inactive := models.OAuthIntrospection{Active: false}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// refresh token มี prefix ของตัวเอง ส่วนค่าอื่นถือเป็น access token (JWT)
+	if strings.HasPrefix(token, utils.OAuthRefreshTokenPrefix) {
+		refresh, err := sc.OAuth.GetRefreshToken(c.UserContext(), utils.HashOAuthToken(token))
+		if errors.Is(err, repository.ErrNotFound) {
+			return c.JSON(inactive)
+		}
+		if err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+		}
+		if !refresh.Active(time.Now()) {
+			return c.JSON(inactive)
+		}
+		user, err := sc.findUser(c, refresh.UserID)
+		if err != nil || user == nil {
+			return c.JSON(inactive)
+		}
+		return c.JSON(models.OAuthIntrospection{
+			Active:    true,
+			Scope:     refresh.Scopes,
+			ClientID:  refresh.ClientID,
+			Username:  user.Username,
+			TokenType: "refresh_token",
+			Exp:       refresh.ExpiresAt.Unix(),
+			Iat:       refresh.CreatedAt.Unix(),
+			Sub:       strconv.Itoa(user.ID),
+			Iss:       issuer,
+		})
+	}
+
+	// รายงานเฉพาะ access token ที่ออกโดย authorization server (มี client_id) ไม่รวม JWT จากการเข้าสู่ระบบปกติ
+	claims, err := utils.ParseJWT(token, cfg.JWT.Secret)
+	if err != nil || claims.ClientID == "" || claims.ExpiresAt == nil {
+		return c.JSON(inactive)
+	}
+	revoked, err := sc.OAuth.IsAccessTokenRevoked(c.UserContext(), claims.ID)
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+	if revoked {
+		return c.JSON(inactive)
+	}
+	// token ของแอปที่ถูกลบไปแล้วถือว่าไม่ active
+	if _, err := sc.OAuth.GetClient(c.UserContext(), claims.ClientID); errors.Is(err, repository.ErrNotFound) {
+		return c.JSON(inactive)
+	} else if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+
+	result := models.OAuthIntrospection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  claims.Username,
+		TokenType: "Bearer",
+		Exp:       claims.ExpiresAt.Unix(),
+		Sub:       claims.Subject,
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		result.Iat = claims.IssuedAt.Unix()
+	}
+	return c.JSON(result)
+}
+
+// Revoke ฟังก์ชัน revocation endpoint (RFC 7009)
+// แอปเพิกถอนได้เฉพาะ token ของตัวเอง และตอบ 200 เสมอแม้ไม่พบ token (ไม่เปิดเผยว่า token มีอยู่หรือไม่)
+// @Summary OAuth token revocation
+// @Description Revoke an access token or refresh token issued to the calling client
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Param client_id formData string false "Client ID (when not using HTTP Basic)"
+// @Param client_secret formData string false "Client secret (when not using HTTP Basic)"
+// @Success 200 "Token revoked or unknown"
+// @Failure 400 {object} models.OAuthErrorResponse
+// @Failure 401 {object} models.OAuthErrorResponse
+// @Failure 500 {object} models.OAuthErrorResponse
+// @Router /oauth/revoke [post]
+func (sc *OAuthServerController) Revoke(c *fiber.Ctx) error {
+	client, err := sc.authenticateClient(c, false)
+	if err != nil || client == nil {
+		return err
+	}
+	token := c.FormValue("token")
+	if token == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "ไม่พบ token")
+	}
+
+	if strings.HasPrefix(token, utils.OAuthRefreshTokenPrefix) {
+		refresh, err := sc.OAuth.GetRefreshToken(c.UserContext(), utils.HashOAuthToken(token))
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+		}
+		if err == nil && refresh.ClientID == client.ClientID {
+			if _, err := sc.OAuth.RevokeRefreshToken(c.UserContext(), refresh.ID); err != nil {
+				return oauthError(c, fiber.StatusInternalServerError, "server_error", "ไม่สามารถเพิกถอน token ได้")
+			}
+		}
+	} else if claims, err := utils.ParseJWT(token, sc.Config.Get().JWT.Secret); err == nil && claims.ClientID == client.ClientID && claims.ExpiresAt != nil {
+		// access token ที่หมดอายุแล้ว (ParseJWT ไม่ผ่าน) ไม่ต้องบันทึก
+		if err := sc.OAuth.RevokeAccessToken(c.UserContext(), claims.ID, claims.ExpiresAt.Time); err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "ไม่สามารถเพิกถอน token ได้")
+		}
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// resolveAuthorize ตรวจคำขออนุญาตและคืนค่าแอปกับ scope ที่ขอ
+// client_id หรือ redirect_uri ที่ไม่ถูกต้องตอบ 400 โดยไม่ redirect (ป้องกัน open redirect)
+// ข้อผิดพลาดอื่นส่ง redirect_to ที่มี error กลับไปยังแอปตาม RFC 6749 ข้อ 4.1.2.1
+// คืนค่า client เป็น nil เมื่อส่ง response ไปแล้ว
+func (sc *OAuthServerController) resolveAuthorize(c *fiber.Ctx, req *models.OAuthAuthorizeRequest) (*models.OAuthClient, []string, error) {
+	// token ของแอป (OAuth access token) และ API key มี scopes ใน Locals
+	// การอนุญาตแอปต้องทำโดยผู้ใช้ที่เข้าสู่ระบบเองเท่านั้น
+	if _, delegated := c.Locals("scopes").([]string); delegated {
+		return nil, nil, utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเข้าสู่ระบบด้วยบัญชีผู้ใช้โดยตรงเพื่ออนุญาตแอป", nil)
+	}
+
+	client, err := sc.OAuth.GetClient(c.UserContext(), req.ClientID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่พบแอป (client_id ไม่ถูกต้อง)", nil)
+	}
+	if err != nil {
+		return nil, nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+
+	// ไม่ระบุ redirect_uri ได้เมื่อแอปลงทะเบียนไว้เพียงรายการเดียว
+	if registered := strings.Fields(client.RedirectURIs); req.RedirectURI == "" && len(registered) == 1 {
+		req.RedirectURI = registered[0]
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "redirect_uri ไม่ตรงกับที่ลงทะเบียนไว้", nil)
+	}
+
+	fail := func(code, description string) (*models.OAuthClient, []string, error) {
+		return nil, nil, authorizeRedirect(c, req.RedirectURI, url.Values{"error": {code}, "error_description": {description}}, req.State)
+	}
+	if req.ResponseType != "code" {
+		return fail("unsupported_response_type", "รองรับเฉพาะ response_type=code")
+	}
+	if !client.AllowsGrant(models.GrantAuthorizationCode) {
+		return fail("unauthorized_client", "แอปไม่ได้รับอนุญาตให้ใช้ authorization_code")
+	}
+	// PKCE บังคับสำหรับทุกแอป (รวม confidential client)
+	if !validCodeChallenge(req.CodeChallengeMethod, req.CodeChallenge) {
+		return fail("invalid_request", "ต้องส่ง code_challenge แบบ S256 (PKCE)")
+	}
+	scopes := requestedScopes(req.Scope, client.Scopes)
+	if len(scopes) == 0 || !client.AllowsScopes(scopes) {
+		return fail("invalid_scope", "scope ไม่ถูกต้องหรือเกินกว่าที่แอปลงทะเบียนไว้")
+	}
+	return client, scopes, nil
+}
+
+// issueCode ออก authorization code และคืน redirect_to ที่มี code และ state กลับไปยังแอป
+func (sc *OAuthServerController) issueCode(c *fiber.Ctx, client *models.OAuthClient, userID int, req *models.OAuthAuthorizeRequest, scopes []string) error {
+	raw, hash, err := utils.GenerateOAuthToken(utils.OAuthCodePrefix)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง authorization code ได้", err)
+	}
+	code := models.OAuthCode{
+		CodeHash:      hash,
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        strings.Join(scopes, " "),
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(sc.Config.Get().AuthServer.CodeTTL),
+	}
+	if err := sc.OAuth.CreateCode(c.UserContext(), &code); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึก authorization code ได้", err)
+	}
+	return authorizeRedirect(c, req.RedirectURI, url.Values{"code": {raw}}, req.State)
+}
+
+// exchangeCode แลก authorization code เป็น token (grant_type=authorization_code)
+func (sc *OAuthServerController) exchangeCode(c *fiber.Ctx, client *models.OAuthClient) error {
+	raw := c.FormValue("code")
+	if raw == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "ไม่พบ code")
+	}
+	code, err := sc.OAuth.ConsumeCode(c.UserContext(), utils.HashOAuthToken(raw))
+	if errors.Is(err, repository.ErrNotFound) {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "code ไม่ถูกต้องหรือถูกใช้ไปแล้ว")
+	}
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+
+	// code ถูกลบไปแล้วในทุกกรณี การตรวจไม่ผ่านจึงไม่เปิดโอกาสให้เดาซ้ำ
+	switch {
+	case code.ClientID != client.ClientID:
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "code ไม่ได้ออกให้แอปนี้")
+	case !time.Now().Before(code.ExpiresAt):
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "code หมดอายุแล้ว")
+	case c.FormValue("redirect_uri", code.RedirectURI) != code.RedirectURI:
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "redirect_uri ไม่ตรงกับคำขออนุญาต")
+	case !utils.VerifyPKCE(c.FormValue("code_verifier"), code.CodeChallenge):
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "code_verifier ไม่ถูกต้อง")
+	}
+
+	user, err := sc.findUser(c, code.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+	return sc.issueTokens(c, client, user, strings.Fields(code.Scopes))
+}
+
+// refresh แลก refresh token เป็น token ชุดใหม่ (grant_type=refresh_token)
+// token เดิมถูกเพิกถอนทันที การนำ token ที่เพิกถอนแล้วมาใช้อีกแสดงว่า token รั่วไหล
+// จึงเพิกถอน refresh token ทั้งหมดของผู้ใช้กับแอปนี้
+func (sc *OAuthServerController) refresh(c *fiber.Ctx, client *models.OAuthClient) error {
+	raw := c.FormValue("refresh_token")
+	if raw == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "ไม่พบ refresh_token")
+	}
+	token, err := sc.OAuth.GetRefreshToken(c.UserContext(), utils.HashOAuthToken(raw))
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && token.ClientID != client.ClientID) {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "refresh_token ไม่ถูกต้อง")
+	}
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+	if !time.Now().Before(token.ExpiresAt) {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "refresh_token หมดอายุแล้ว")
+	}
+
+	// ขอ scope ให้น้อยลงได้ แต่ขอเพิ่มจากที่ผู้ใช้อนุญาตไว้ไม่ได้
+	// ตรวจก่อนเพิกถอน token เดิม request ที่ถูกปฏิเสธจึงไม่ทำให้ refresh token ที่ยังใช้ได้หายไป
+	// (token ที่ถูกเพิกถอนแล้วไม่ถูกตรวจ scope เพื่อให้ไปถึงการตรวจการนำมาใช้ซ้ำด้านล่างเสมอ)
+	scopes, ok := refreshScopes(c.FormValue("scope"), token.Scopes)
+	if token.RevokedAt == nil && !ok {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_scope", "scope เกินกว่าที่ผู้ใช้อนุญาตไว้")
+	}
+
+	// RevokeRefreshToken คืนค่า false เมื่อ token ถูกเพิกถอนไปก่อนแล้ว (รวมถึงถูก request อื่นใช้ไปพร้อมกัน)
+	rotated := false
+	if token.RevokedAt == nil {
+		if rotated, err = sc.OAuth.RevokeRefreshToken(c.UserContext(), token.ID); err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+		}
+	}
+	if !rotated {
+		if err := sc.OAuth.RevokeRefreshTokens(c.UserContext(), client.ClientID, token.UserID); err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+		}
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "refresh_token ถูกใช้ไปแล้ว token ทั้งหมดของแอปนี้ถูกเพิกถอน")
+	}
+
+	user, err := sc.findUser(c, token.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+	return sc.issueTokens(c, client, user, scopes)
+}
+
+// issueTokens ออก access token (และ refresh token เมื่อมีผู้ใช้และแอปใช้ grant refresh_token ได้)
+// user เป็น nil สำหรับ client_credentials: token แทนตัวแอปเอง (sub = client_id, user_id = 0)
+func (sc *OAuthServerController) issueTokens(c *fiber.Ctx, client *models.OAuthClient, user *models.User, scopes []string) error {
+	cfg := sc.Config.Get()
+	scope := strings.Join(scopes, " ")
+
+	claims := &utils.JWTClaims{ClientID: client.ClientID, Scope: scope}
+	claims.Issuer = strings.TrimRight(cfg.AuthServer.Issuer, "/")
+	claims.Subject = client.ClientID
+	if user != nil {
+		claims.UserID = user.ID
+		claims.Username = user.Username
+		claims.Role = user.Role // role ปัจจุบันของผู้ใช้ จำกัดเพิ่มเติมด้วย scope (RequireScope)
+		claims.Subject = strconv.Itoa(user.ID)
+	}
+	accessToken, err := utils.GenerateAccessToken(claims, cfg.JWT.Secret, cfg.AuthServer.AccessTokenTTL)
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "ไม่สามารถสร้าง token ได้")
+	}
+
+	response := models.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(cfg.AuthServer.AccessTokenTTL / time.Second),
+		Scope:       scope,
+	}
+	if user != nil && client.AllowsGrant(models.GrantRefreshToken) {
+		raw, hash, err := utils.GenerateOAuthToken(utils.OAuthRefreshTokenPrefix)
+		if err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "ไม่สามารถสร้าง refresh token ได้")
+		}
+		err = sc.OAuth.CreateRefreshToken(c.UserContext(), &models.OAuthRefreshToken{
+			TokenHash: hash,
+			ClientID:  client.ClientID,
+			UserID:    user.ID,
+			Scopes:    scope,
+			ExpiresAt: time.Now().Add(cfg.AuthServer.RefreshTokenTTL),
+		})
+		if err != nil {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "ไม่สามารถบันทึก refresh token ได้")
+		}
+		response.RefreshToken = raw
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(response)
+}
+
+// authenticateClient ยืนยันตัวตนของแอปจาก HTTP Basic หรือ client_id/client_secret ใน body
+// confidentialOnly = true ไม่รับ public client (เช่น introspection)
+// คืนค่า client เป็น nil เมื่อส่ง response ข้อผิดพลาดไปแล้ว
+func (sc *OAuthServerController) authenticateClient(c *fiber.Ctx, confidentialOnly bool) (*models.OAuthClient, error) {
+	clientID, secret := c.FormValue("client_id"), c.FormValue("client_secret")
+	basic := false
+	if scheme, value, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); found && strings.EqualFold(scheme, "Basic") {
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, invalidClient(c, true)
+		}
+		// RFC 6749 ข้อ 2.3.1: client_id และ secret ถูก URL-encode ก่อนนำมาต่อกัน
+		id, pass, _ := strings.Cut(string(decoded), ":")
+		if clientID, err = url.QueryUnescape(id); err != nil {
+			return nil, invalidClient(c, true)
+		}
+		if secret, err = url.QueryUnescape(pass); err != nil {
+			return nil, invalidClient(c, true)
+		}
+		basic = true
+	}
+	if clientID == "" {
+		return nil, invalidClient(c, basic)
+	}
+
+	client, err := sc.OAuth.GetClient(c.UserContext(), clientID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, invalidClient(c, basic)
+	}
+	if err != nil {
+		return nil, oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+
+	if client.Confidential() {
+		if subtle.ConstantTimeCompare([]byte(utils.HashOAuthToken(secret)), []byte(client.SecretHash)) != 1 {
+			return nil, invalidClient(c, basic)
+		}
+	} else if confidentialOnly || secret != "" {
+		return nil, invalidClient(c, basic)
+	}
+	return client, nil
+}
+
+// findUser อ่านผู้ใช้ที่อนุญาตแอป (ข้อมูลปัจจุบัน ไม่ใช่ตอนที่อนุญาต)
+// คืนค่า user เป็น nil เมื่อส่ง response ข้อผิดพลาดไปแล้ว
+func (sc *OAuthServerController) findUser(c *fiber.Ctx, userID int) (*models.User, error) {
+	var user models.User
+	query := "SELECT id, username, role, status, suspended_until FROM users WHERE id = ?"
+	err := sc.DB.GetContext(c.UserContext(), &user, sc.DB.Rebind(query), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, oauthError(c, fiber.StatusBadRequest, "invalid_grant", "ไม่พบผู้ใช้ที่อนุญาตแอป")
+	}
+	if err == nil && user.EffectiveStatus(time.Now()) != models.UserStatusActive {
+		return nil, oauthError(c, fiber.StatusBadRequest, "invalid_grant", "บัญชีผู้ใช้ที่อนุญาตแอปถูกระงับหรือยังไม่เปิดใช้งาน")
+	}
+	if err != nil {
+		return nil, oauthError(c, fiber.StatusInternalServerError, "server_error", "เกิดข้อผิดพลาดในฐานข้อมูล")
+	}
+	return &user, nil
+}
+
+// authorizeRedirect ส่ง URL ที่ frontend ต้อง redirect ผู้ใช้กลับไปยังแอป พร้อม code หรือ error และ state
+// ผลที่เป็น error ตอบด้วยสถานะ 400 แต่ยังมี redirect_to เพื่อให้แอปรับรู้ข้อผิดพลาด
+func authorizeRedirect(c *fiber.Ctx, redirectURI string, params url.Values, state string) error {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "redirect_uri ไม่ถูกต้อง", err)
+	}
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	target.RawQuery = query.Encode()
+
+	if params.Has("error") {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.Response{
+			Status:  false,
+			Message: "คำขออนุญาตไม่สำเร็จ: " + params.Get("error_description"),
+			Data:    fiber.Map{"redirect_to": target.String()},
+			Error:   params.Get("error"),
+		})
+	}
+	return utils.SuccessResponse(c, "อนุญาตแอปสำเร็จ", fiber.Map{"redirect_to": target.String()})
+}
+
+// oauthError ส่งข้อผิดพลาดในรูปแบบของ OAuth2 (RFC 6749 ข้อ 5.2)
+func oauthError(c *fiber.Ctx, status int, code, description string) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(status).JSON(models.OAuthErrorResponse{Error: code, ErrorDescription: description})
+}
+
+// invalidClient ตอบ 401 invalid_client และขอ HTTP Basic เมื่อแอปส่งข้อมูลมาทาง Authorization header
+func invalidClient(c *fiber.Ctx, basic bool) error {
+	if basic {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+	}
+	return oauthError(c, fiber.StatusUnauthorized, "invalid_client", "ยืนยันตัวตนของแอปไม่สำเร็จ")
+}
+
+// validCodeChallenge ตรวจ code_challenge ของ PKCE รองรับเฉพาะ S256 (ไม่รับ plain ที่ส่ง verifier ผ่าน browser ตรงๆ)
+// code_challenge แบบ S256 คือ base64url ของ SHA-256 ซึ่งยาว 43 ตัวอักษรเสมอ
+func validCodeChallenge(method, challenge string) bool {
+	return method == "S256" && len(challenge) == 43
+}
+
+// refreshScopes คืนค่า scope ของ token ใหม่จากการ refresh (ไม่ระบุ = เท่าเดิม)
+// ok เป็น false เมื่อขอ scope ที่ไม่อยู่ใน granted ซึ่งผู้ใช้อนุญาตไว้ตอนแรก (ขอน้อยลงได้ แต่ขอเพิ่มไม่ได้)
+func refreshScopes(scope, granted string) (scopes []string, ok bool) {
+	scopes = requestedScopes(scope, granted)
+	return scopes, len(scopes) > 0 && scopesGranted(granted, scopes)
+}
+
+// requestedScopes แยก scope ที่ขอ (คั่นด้วยช่องว่าง) ตัดค่าซ้ำ ไม่ระบุ = ใช้ค่า fallback ทั้งหมด
+func requestedScopes(scope, fallback string) []string {
+	if strings.TrimSpace(scope) == "" {
+		scope = fallback
+	}
+	scopes := []string{}
+	for _, s := range strings.Fields(scope) {
+		if !scopesGranted(strings.Join(scopes, " "), []string{s}) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// scopesGranted ตรวจว่า scope ทั้งหมดอยู่ใน granted (list ที่คั่นด้วยช่องว่าง)
+func scopesGranted(granted string, scopes []string) bool {
+	fields := strings.Fields(granted)
+	for _, scope := range scopes {
+		found := false
+		for _, field := range fields {
+			if field == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestValidCodeChallenge ตรวจว่ารับเฉพาะ code_challenge แบบ S256 และปฏิเสธ plain
+func TestValidCodeChallenge(t *testing.T) {
+	// ค่าจาก RFC 7636 Appendix B
+	const challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	tests := []struct {
+		name      string
+		method    string
+		challenge string
+		want      bool
+	}{
+		{"S256", "S256", challenge, true},
+		{"plain", "plain", "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk", false},
+		{"plain with S256 length", "plain", challenge, false},
+		{"missing method", "", challenge, false},
+		{"lowercase method", "s256", challenge, false},
+		{"short challenge", "S256", challenge[:42], false},
+		{"missing challenge", "S256", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validCodeChallenge(tt.method, tt.challenge); got != tt.want {
+				t.Errorf("validCodeChallenge(%q, %q) = %v, ต้องการ %v", tt.method, tt.challenge, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRequestedScopes ตรวจการแยก scope: คั่นด้วยช่องว่าง ตัดค่าซ้ำ และใช้ fallback เมื่อไม่ระบุ
+func TestRequestedScopes(t *testing.T) {
+	tests := []struct {
+		scope    string
+		fallback string
+		want     []string
+	}{
+		{"profile:read users:read", "profile:read", []string{"profile:read", "users:read"}},
+		{"  profile:read   profile:read users:read ", "", []string{"profile:read", "users:read"}},
+		{"", "profile:read users:read", []string{"profile:read", "users:read"}},
+		{"   ", "profile:read", []string{"profile:read"}},
+		{"", "", []string{}},
+	}
+	for _, tt := range tests {
+		if got := requestedScopes(tt.scope, tt.fallback); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("requestedScopes(%q, %q) = %q, ต้องการ %q", tt.scope, tt.fallback, got, tt.want)
+		}
+	}
+}
+
+// TestScopesGranted ตรวจว่าทุก scope ต้องอยู่ใน granted แบบตรงทั้งคำ
+func TestScopesGranted(t *testing.T) {
+	tests := []struct {
+		granted string
+		scopes  []string
+		want    bool
+	}{
+		{"profile:read users:read", []string{"profile:read"}, true},
+		{"profile:read users:read", []string{"users:read", "profile:read"}, true},
+		{"profile:read users:read", nil, true},
+		{"profile:read", []string{"profile:read", "users:write"}, false},
+		{"profile:read", []string{"profile"}, false},
+		{"profile:read", []string{"profile:read:all"}, false},
+		{"", []string{"profile:read"}, false},
+	}
+	for _, tt := range tests {
+		if got := scopesGranted(tt.granted, tt.scopes); got != tt.want {
+			t.Errorf("scopesGranted(%q, %q) = %v, ต้องการ %v", tt.granted, tt.scopes, got, tt.want)
+		}
+	}
+}
+
+// TestRefreshScopes ตรวจว่าการ refresh ขอ scope น้อยลงได้ แต่ขอเกินกว่าที่ผู้ใช้อนุญาตไว้ตอนแรกไม่ได้
+func TestRefreshScopes(t *testing.T) {
+	const granted = "profile:read users:read"
+
+	tests := []struct {
+		name  string
+		scope string
+		want  []string
+		ok    bool
+	}{
+		{"same as granted", "", []string{"profile:read", "users:read"}, true},
+		{"narrower", "users:read", []string{"users:read"}, true},
+		{"reordered", "users:read profile:read", []string{"users:read", "profile:read"}, true},
+		{"wider", "profile:read users:read users:write", []string{"profile:read", "users:read", "users:write"}, false},
+		{"different", "profile:write", []string{"profile:write"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := refreshScopes(tt.scope, granted)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("refreshScopes(%q) = %q, %v ต้องการ %q, %v", tt.scope, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	if _, ok := refreshScopes("", ""); ok {
+		t.Error("refresh token ที่ไม่มี scope ต้องถูกปฏิเสธ")
+	}
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// errNotMember คืนค่าเมื่อผู้ใช้เข้าสู่ระบบกับองค์กรที่ตนไม่ได้เป็นสมาชิก
+var errNotMember = errors.New("คุณไม่ได้เป็นสมาชิกขององค์กรนี้")
+
+// OrganizationController โครงสร้างสำหรับจัดการองค์กร (tenant) และสมาชิก
+// การสร้างองค์กรและจัดการสมาชิกทำได้เฉพาะ Admin ของระบบ ส่วน Admin ขององค์กรจัดการผู้ใช้ผ่าน /users
+type OrganizationController struct {
+	Config        *config.Store                      // การตั้งค่าระบบ
+	DB            *sqlx.DB                           // การเชื่อมต่อฐานข้อมูล
+	Organizations *repository.OrganizationRepository // การเข้าถึงตาราง organizations และ memberships
+	Users         *repository.UserRepository         // การเข้าถึงตาราง users
+	Validator     *validator.Validate                // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewOrganizationController ฟังก์ชันสร้าง OrganizationController ใหม่
+func NewOrganizationController(cfg *config.Store, db *sqlx.DB) *OrganizationController {
+	return &OrganizationController{
+		Config:        cfg,
+		DB:            db,
+		Organizations: repository.NewOrganizationRepository(db),
+		Users:         repository.NewUserRepository(db),
+		Validator:     validator.New(),
+	}
+}
+
+// CreateOrganization ฟังก์ชันสำหรับสร้างองค์กรใหม่ (เฉพาะ Admin ของระบบ)
+// @Summary Create organization
+// @Description Create a tenant organization (platform Admin only). The slug is used in the X-Tenant header and as a subdomain.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param organization body models.OrganizationCreate true "Organization data"
+// @Success 201 {object} utils.Response{data=models.Organization}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /organizations [post]
+func (oc *OrganizationController) CreateOrganization(c *fiber.Ctx) error {
+	var input models.OrganizationCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	input.Slug = strings.ToLower(strings.TrimSpace(input.Slug))
+	input.Name = strings.TrimSpace(input.Name)
+	if err := oc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	id, err := oc.Organizations.Create(c.UserContext(), &models.Organization{Slug: input.Slug, Name: input.Name})
+	if errors.Is(err, repository.ErrOrganizationExists) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, err.Error(), nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้างองค์กรได้", err)
+	}
+
+	org, err := oc.Organizations.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลองค์กรได้", err)
+	}
+	return utils.CreatedResponse(c, "สร้างองค์กรสำเร็จ", org)
+}
+
+// GetOrganizations ฟังก์ชันสำหรับดูรายการองค์กรทั้งหมด (เฉพาะ Admin ของระบบ)
+// @Summary List organizations
+// @Description List all tenant organizations (platform Admin only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.Organization}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /organizations [get]
+func (oc *OrganizationController) GetOrganizations(c *fiber.Ctx) error {
+	orgs, err := oc.Organizations.List(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลองค์กรได้", err)
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลองค์กรสำเร็จ", orgs)
+}
+
+// SetMember ฟังก์ชันสำหรับเพิ่มผู้ใช้เข้าองค์กรหรือเปลี่ยน role ในองค์กร (เฉพาะ Admin ของระบบ)
+// role admin ทำให้ผู้ใช้จัดการผู้ใช้และคำเชิญขององค์กรนี้ได้ แต่ไม่เห็นองค์กรอื่น
+// @Summary Add or update organization member
+// @Description Add a user to an organization or change their role in it (platform Admin only)
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Param membership body models.MembershipUpdate true "Role in the organization"
+// @Success 200 {object} utils.Response "Role updated"
+// @Success 201 {object} utils.Response "Member added"
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /organizations/{id}/members/{userId} [put]
+func (oc *OrganizationController) SetMember(c *fiber.Ctx) error {
+	org, userID, err := oc.findMember(c)
+	if err != nil || org == nil {
+		return err
+	}
+
+	var input models.MembershipUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := oc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if input.Role == "" {
+		input.Role = "user"
+	}
+
+	if _, err := oc.Users.GetByID(c.UserContext(), userID); errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	} else if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+
+	added, err := oc.Organizations.SetMember(c.UserContext(), org.ID, userID, input.Role)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกสมาชิกได้", err)
+	}
+	data := fiber.Map{"organization_id": org.ID, "user_id": userID, "role": input.Role}
+	if added {
+		return utils.CreatedResponse(c, "เพิ่มสมาชิกสำเร็จ", data)
+	}
+	return utils.SuccessResponse(c, "เปลี่ยน role ในองค์กรสำเร็จ", data)
+}
+
+// RemoveMember ฟังก์ชันสำหรับนำผู้ใช้ออกจากองค์กร (เฉพาะ Admin ของระบบ)
+// บัญชีของผู้ใช้ยังอยู่ และ token ที่เลือกองค์กรนี้ไว้จะไม่เห็นข้อมูลขององค์กรทันที
+// @Summary Remove organization member
+// @Description Remove a user from an organization. The account itself is kept (platform Admin only).
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Organization ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /organizations/{id}/members/{userId} [delete]
+func (oc *OrganizationController) RemoveMember(c *fiber.Ctx) error {
+	org, userID, err := oc.findMember(c)
+	if err != nil || org == nil {
+		return err
+	}
+	removed, err := oc.Organizations.RemoveMember(c.UserContext(), org.ID, userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถนำสมาชิกออกได้", err)
+	}
+	if !removed {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ผู้ใช้ไม่ได้เป็นสมาชิกขององค์กรนี้", nil)
+	}
+	return utils.SuccessResponse(c, "นำสมาชิกออกจากองค์กรสำเร็จ", nil)
+}
+
+// GetMyOrganizations ฟังก์ชันสำหรับดูองค์กรที่ผู้ใช้เป็นสมาชิก พร้อม role ในแต่ละองค์กร
+// ใช้เลือกองค์กรสำหรับการเข้าสู่ระบบครั้งถัดไป (header X-Tenant หรือ subdomain)
+// @Summary List my organizations
+// @Description List organizations the current user belongs to, with their role in each
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.OrganizationMembership}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/organizations [get]
+func (oc *OrganizationController) GetMyOrganizations(c *fiber.Ctx) error {
+	orgs, err := oc.Organizations.ForUser(c.UserContext(), c.Locals("user_id").(int))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลองค์กรได้", err)
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูลองค์กรสำเร็จ", orgs)
+}
+
+// findMember อ่านองค์กรตาม :id และ ID ผู้ใช้ตาม :userId ของเส้นทาง
+// คืนค่าองค์กรเป็น nil เมื่อพารามิเตอร์ไม่ถูกต้องหรือไม่พบองค์กร (ส่ง response ข้อผิดพลาดไปแล้ว)
+func (oc *OrganizationController) findMember(c *fiber.Ctx) (*models.Organization, int, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, 0, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID องค์กรไม่ถูกต้อง", err)
+	}
+	userID, err := strconv.Atoi(c.Params("userId"))
+	if err != nil {
+		return nil, 0, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	org, err := oc.Organizations.GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, 0, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบองค์กร", nil)
+	}
+	if err != nil {
+		return nil, 0, utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	return org, userID, nil
+}
+
+// tenantID คืนค่าองค์กรของ request ที่ TenantMiddleware เลือกไว้ (0 = ไม่มี หรือทุกองค์กรสำหรับ Admin ของระบบ)
+func tenantID(c *fiber.Ctx) int {
+	id, _ := c.Locals("tenant_id").(int)
+	return id
+}
+
+// chooseTenant เลือกองค์กรของ token ที่จะออกให้ผู้ใช้ตอนเข้าสู่ระบบ
+// องค์กรที่ request ระบุ (header หรือ subdomain) ต้องมีอยู่และผู้ใช้ต้องเป็นสมาชิก (ยกเว้น Admin ของระบบ)
+// เมื่อไม่ได้ระบุ ผู้ใช้ทั่วไปได้องค์กรแรกที่เป็นสมาชิก ส่วน Admin ของระบบไม่เลือกองค์กร (เห็นทุกองค์กร)
+// คืนค่า nil เมื่อไม่มีองค์กร, repository.ErrNotFound เมื่อไม่พบองค์กรที่ระบุ และ errNotMember เมื่อไม่ได้เป็นสมาชิก
+func chooseTenant(ctx context.Context, slug string, orgs *repository.OrganizationRepository, user *models.User) (*models.OrganizationMembership, error) {
+	if slug == "" {
+		if user.Role == "admin" {
+			return nil, nil
+		}
+		memberships, err := orgs.ForUser(ctx, user.ID)
+		if err != nil || len(memberships) == 0 {
+			return nil, err
+		}
+		return &memberships[0], nil
+	}
+
+	org, err := orgs.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	role, err := orgs.MemberRole(ctx, org.ID, user.ID)
+	switch {
+	case err == nil:
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	case user.Role == "admin":
+		role = "admin"
+	default:
+		return nil, errNotMember
+	}
+	return &models.OrganizationMembership{Organization: *org, Role: role}, nil
+}
+
+// tenantError แปลงข้อผิดพลาดจาก chooseTenant เป็น response
+func tenantError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบองค์กร", nil)
+	case errors.Is(err, errNotMember):
+		return utils.ErrorResponse(c, fiber.StatusForbidden, err.Error(), nil)
+	default:
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบองค์กรได้", err)
+	}
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // ลงทะเบียนตัวถอดรหัส GIF ให้ image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/storage"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxAvatarPixels จำนวน pixel สูงสุดของรูปที่ยอมถอดรหัส (4096 x 4096)
+// ป้องกันไฟล์ขนาดเล็กที่ประกาศขนาดรูปใหญ่มากจนใช้หน่วยความจำหมด
+const maxAvatarPixels = 4096 * 4096
+
+// avatarJPEGQuality คุณภาพของ thumbnail ที่บันทึกเป็น JPEG
+const avatarJPEGQuality = 85
+
+// ProfileController โครงสร้างสำหรับจัดการโปรไฟล์ของผู้ใช้ที่เข้าสู่ระบบ (ข้อมูลส่วนตัวและรูปโปรไฟล์)
+type ProfileController struct {
+	Config    *config.Store              // การตั้งค่าระบบ (STORAGE_*, AVATAR_*)
+	DB        *sqlx.DB                   // การเชื่อมต่อฐานข้อมูล
+	Users     *repository.UserRepository // การเข้าถึงตาราง users
+	Validator *validator.Validate        // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewProfileController ฟังก์ชันสร้าง ProfileController ใหม่
+func NewProfileController(cfg *config.Store, db *sqlx.DB) *ProfileController {
+	return &ProfileController{
+		Config:    cfg,
+		DB:        db,
+		Users:     repository.NewUserRepository(db),
+		Validator: validator.New(),
+	}
+}
+
+// UpdateProfile ฟังก์ชันสำหรับแก้ไขข้อมูลโปรไฟล์ของผู้ใช้ที่เข้าสู่ระบบ
+// ส่งเฉพาะ field ที่ต้องการเปลี่ยน ค่าว่างลบค่าเดิม และ metadata: null ลบ metadata
+// @Summary Update user profile
+// @Description Update the current user's display name, bio, locale (BCP 47), timezone (IANA), phone (E.164) or metadata (JSON object up to 4 KB). Omitted fields are kept.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param profile body models.ProfileUpdate true "Fields to change"
+// @Success 200 {object} utils.Response{data=models.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/profile [patch]
+func (pc *ProfileController) UpdateProfile(c *fiber.Ctx) error {
+	var input models.ProfileUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := pc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	user, err := pc.Users.GetByID(c.UserContext(), c.Locals("user_id").(int))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลโปรไฟล์ได้", err)
+	}
+	for field, value := range map[*string]*string{
+		&user.DisplayName: input.DisplayName,
+		&user.Bio:         input.Bio,
+		&user.Locale:      input.Locale,
+		&user.Timezone:    input.Timezone,
+		&user.Phone:       input.Phone,
+	} {
+		if value != nil {
+			*field = strings.TrimSpace(*value)
+		}
+	}
+	if input.Metadata != nil {
+		metadata, err := profileMetadata(input.Metadata)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+		}
+		user.Metadata = metadata
+	}
+
+	if err := pc.Users.UpdateProfile(c.UserContext(), user); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถแก้ไขโปรไฟล์ได้", err)
+	}
+	return utils.SuccessResponse(c, "แก้ไขโปรไฟล์สำเร็จ", userResponse(pc.Config.Get(), user))
+}
+
+// UploadAvatar ฟังก์ชันสำหรับอัปโหลดรูปโปรไฟล์ (multipart/form-data field "avatar")
+// ชนิดไฟล์ตรวจจากเนื้อหา (ไม่เชื่อ Content-Type ของ client) รูปถูกตัดเป็นสี่เหลี่ยมจัตุรัสและย่อเป็น thumbnail ตาม AVATAR_SIZES
+// ไฟล์ต้นฉบับไม่ถูกเก็บ (จึงไม่มีข้อมูล EXIF เช่น ตำแหน่ง GPS หลุดออกไป) และรูปเดิมถูกลบหลังบันทึกรูปใหม่สำเร็จ
+// @Summary Upload avatar
+// @Description Upload a profile picture (JPEG, PNG or GIF by default). It is cropped to a square and resized to every size in AVATAR_SIZES; the first size is the avatar_url.
+// @Tags auth
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param avatar formData file true "Image file"
+// @Success 200 {object} utils.Response{data=models.AvatarResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/profile/avatar [post]
+func (pc *ProfileController) UploadAvatar(c *fiber.Ctx) error {
+	cfg := pc.Config.Get()
+	userID := c.Locals("user_id").(int)
+
+	img, format, err := readAvatar(c, cfg.Avatar)
+	if err != nil || img == nil {
+		return err
+	}
+
+	// ไฟล์ของรูปใหม่อยู่ในโฟลเดอร์สุ่มของตัวเอง จึงลบรูปเดิมได้โดยไม่กระทบรูปใหม่
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกรูปโปรไฟล์ได้", err)
+	}
+	dir := avatarPrefix(userID) + hex.EncodeToString(suffix) + "/"
+	store := storage.New(cfg.Storage)
+
+	response := models.AvatarResponse{Thumbnails: map[string]string{}}
+	var mainKey string
+	for i, size := range cfg.Avatar.ThumbnailSizes() {
+		var buf bytes.Buffer
+		contentType, ext := "image/png", ".png"
+		thumb := utils.Thumbnail(img, size)
+		if format == "jpeg" {
+			contentType, ext = "image/jpeg", ".jpg"
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: avatarJPEGQuality})
+		} else {
+			err = png.Encode(&buf, thumb)
+		}
+		key := dir + strconv.Itoa(size) + ext
+		if err == nil {
+			err = store.Put(c.UserContext(), key, &buf, contentType)
+		}
+		if err != nil {
+			if cleanupErr := store.DeletePrefix(c.UserContext(), dir); cleanupErr != nil {
+				log.Printf("⚠️  ไม่สามารถลบรูปโปรไฟล์ที่บันทึกไม่ครบ %s: %v", dir, cleanupErr)
+			}
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกรูปโปรไฟล์ได้", err)
+		}
+		if i == 0 {
+			mainKey = key
+		}
+		response.Thumbnails[strconv.Itoa(size)] = store.URL(key)
+	}
+
+	old, err := pc.Users.SetAvatar(c.UserContext(), userID, &mainKey)
+	if err != nil {
+		if cleanupErr := store.DeletePrefix(c.UserContext(), dir); cleanupErr != nil {
+			log.Printf("⚠️  ไม่สามารถลบรูปโปรไฟล์ที่ไม่ได้ใช้ %s: %v", dir, cleanupErr)
+		}
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึกรูปโปรไฟล์ได้", err)
+	}
+	if old != nil {
+		removeAvatarFiles(c, store, *old)
+	}
+	response.AvatarURL = store.URL(mainKey)
+	return utils.SuccessResponse(c, "อัปโหลดรูปโปรไฟล์สำเร็จ", response)
+}
+
+// DeleteAvatar ฟังก์ชันสำหรับลบรูปโปรไฟล์ของผู้ใช้ที่เข้าสู่ระบบ
+// @Summary Delete avatar
+// @Description Remove the current user's profile picture and all of its thumbnails
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/profile/avatar [delete]
+func (pc *ProfileController) DeleteAvatar(c *fiber.Ctx) error {
+	old, err := pc.Users.SetAvatar(c.UserContext(), c.Locals("user_id").(int), nil)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถลบรูปโปรไฟล์ได้", err)
+	}
+	if old == nil {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ยังไม่มีรูปโปรไฟล์", nil)
+	}
+	removeAvatarFiles(c, storage.New(pc.Config.Get().Storage), *old)
+	return utils.SuccessResponse(c, "ลบรูปโปรไฟล์สำเร็จ", nil)
+}
+
+// readAvatar อ่านไฟล์ "avatar" จาก multipart form ตรวจขนาด ชนิดไฟล์ และขนาดรูป แล้วถอดรหัสเป็นรูป
+// คืนค่ารูปเป็น nil เมื่อไฟล์ไม่ผ่านการตรวจ (ส่ง response ข้อผิดพลาดไปแล้ว)
+func readAvatar(c *fiber.Ctx, cfg *config.AvatarConfig) (image.Image, string, error) {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ต้องส่งไฟล์รูปใน field avatar (multipart/form-data)", err)
+	}
+	tooLarge := fmt.Sprintf("ไฟล์รูปต้องมีขนาดไม่เกิน %d bytes", cfg.MaxSize)
+	if file.Size > int64(cfg.MaxSize) {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, tooLarge, nil)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถอ่านไฟล์รูปได้", err)
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, int64(cfg.MaxSize)+1))
+	if err != nil {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถอ่านไฟล์รูปได้", err)
+	}
+	if len(data) > cfg.MaxSize {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, tooLarge, nil)
+	}
+
+	detected := mimetype.Detect(data)
+	allowed := false
+	for _, contentType := range cfg.AllowedTypes {
+		allowed = allowed || detected.Is(contentType)
+	}
+	if !allowed {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType,
+			"ไม่รองรับไฟล์ชนิด "+detected.String()+" (รองรับ "+strings.Join(cfg.AllowedTypes, ", ")+")", nil)
+	}
+
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ไฟล์รูปเสียหรืออ่านไม่ได้", err)
+	}
+	if imgConfig.Width*imgConfig.Height > maxAvatarPixels {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "รูปต้องมีขนาดไม่เกิน 4096 x 4096 pixel", nil)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ไฟล์รูปเสียหรืออ่านไม่ได้", err)
+	}
+	return img, format, nil
+}
+
+// profileMetadata ตรวจว่า metadata เป็น JSON object และคืนค่าแบบย่อสำหรับเก็บในฐานข้อมูล (null = ลบ)
+func profileMetadata(raw json.RawMessage) (*string, error) {
+	if string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, errors.New("metadata ต้องเป็น JSON object")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, err
+	}
+	metadata := buf.String()
+	return &metadata, nil
+}
+
+// avatarPrefix คืนค่า prefix ของไฟล์รูปโปรไฟล์ทั้งหมดของผู้ใช้ใน blob storage
+func avatarPrefix(userID int) string {
+	return fmt.Sprintf("avatars/%d/", userID)
+}
+
+// removeAvatarFiles ลบรูปโปรไฟล์เดิมทุกขนาด (ทุกไฟล์ในโฟลเดอร์เดียวกับ key ของรูปหลัก)
+// ความผิดพลาดถูกบันทึก log เท่านั้น เพราะข้อมูลในฐานข้อมูลถูกเปลี่ยนไปแล้ว
+func removeAvatarFiles(c *fiber.Ctx, store storage.Storage, key string) {
+	if err := store.DeletePrefix(c.UserContext(), path.Dir(key)+"/"); err != nil {
+		log.Printf("⚠️  ไม่สามารถลบรูปโปรไฟล์เดิม %s: %v", key, err)
+	}
+}
+
+// userResponse แปลงผู้ใช้เป็น models.UserResponse พร้อมลิงก์รูปโปรไฟล์จาก blob storage ที่ตั้งค่าไว้
+func userResponse(cfg *config.Config, user *models.User) models.UserResponse {
+	response := user.ConvertToResponse()
+	if user.AvatarKey != nil {
+		response.AvatarURL = storage.New(cfg.Storage).URL(*user.AvatarKey)
+	}
+	return response
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// SessionController โครงสร้างสำหรับจัดการ session การเข้าสู่ระบบ (อุปกรณ์ที่เข้าสู่ระบบอยู่)
+type SessionController struct {
+	Config   *config.Store                 // การตั้งค่าระบบ
+	DB       *sqlx.DB                      // การเชื่อมต่อฐานข้อมูล
+	Sessions *repository.SessionRepository // การเข้าถึงตาราง sessions
+	Users    *repository.UserRepository    // ตรวจว่าผู้ใช้อยู่ในองค์กรที่ Admin เลือก
+}
+
+// NewSessionController ฟังก์ชันสร้าง SessionController ใหม่
+func NewSessionController(cfg *config.Store, db *sqlx.DB) *SessionController {
+	return &SessionController{
+		Config:   cfg,
+		DB:       db,
+		Sessions: repository.NewSessionRepository(db),
+		Users:    repository.NewUserRepository(db),
+	}
+}
+
+// GetMySessions ฟังก์ชันสำหรับดูอุปกรณ์ที่ผู้ใช้เข้าสู่ระบบอยู่
+// ต้องเข้าสู่ระบบด้วยบัญชีผู้ใช้โดยตรง (ไม่รับ API key หรือ token ของแอป) และ session ของ request นี้จะมี current=true
+// @Summary List my sessions
+// @Description List active login sessions (device, user agent, IP, last seen) of the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response{data=[]models.SessionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/sessions [get]
+func (sc *SessionController) GetMySessions(c *fiber.Ctx) error {
+	current, ok := c.Locals("session_id").(int)
+	if !ok {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเข้าสู่ระบบด้วยบัญชีผู้ใช้โดยตรงเพื่อจัดการ session", nil)
+	}
+	return sc.listSessions(c, c.Locals("user_id").(int), current)
+}
+
+// RevokeMySession ฟังก์ชันสำหรับออกจากระบบบนอุปกรณ์ที่กำหนด
+// token ของ session ที่ถูกเพิกถอนจะใช้งานไม่ได้ทันที (เพิกถอน session ปัจจุบันได้เช่นเดียวกับ logout)
+// @Summary Revoke my session
+// @Description Revoke one of the current user's sessions. Its token is rejected immediately.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Session ID"
+// @Param X-CSRF-Token header string false "CSRF token (required when authenticated by cookie)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/sessions/{id} [delete]
+func (sc *SessionController) RevokeMySession(c *fiber.Ctx) error {
+	if _, ok := c.Locals("session_id").(int); !ok {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเข้าสู่ระบบด้วยบัญชีผู้ใช้โดยตรงเพื่อจัดการ session", nil)
+	}
+	return sc.revokeSession(c, c.Locals("user_id").(int), "id")
+}
+
+// GetUserSessions ฟังก์ชันสำหรับดูอุปกรณ์ที่ผู้ใช้ตาม ID เข้าสู่ระบบอยู่ (เฉพาะ Admin ของระบบหรือขององค์กร)
+// @Summary List user sessions
+// @Description List active login sessions of a user (Admin or organization Admin)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=[]models.SessionResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/{id}/sessions [get]
+func (sc *SessionController) GetUserSessions(c *fiber.Ctx) error {
+	userID, err := sc.findUser(c)
+	if err != nil || userID == 0 {
+		return err
+	}
+	current, _ := c.Locals("session_id").(int)
+	return sc.listSessions(c, userID, current)
+}
+
+// RevokeUserSession ฟังก์ชันสำหรับเพิกถอน session ของผู้ใช้ตาม ID (เฉพาะ Admin ของระบบหรือขององค์กร)
+// @Summary Revoke user session
+// @Description Revoke a login session of a user. Its token is rejected immediately (Admin or organization Admin).
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "User ID"
+// @Param sid path int true "Session ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/{id}/sessions/{sid} [delete]
+func (sc *SessionController) RevokeUserSession(c *fiber.Ctx) error {
+	userID, err := sc.findUser(c)
+	if err != nil || userID == 0 {
+		return err
+	}
+	return sc.revokeSession(c, userID, "sid")
+}
+
+// findUser อ่าน ID ผู้ใช้ตาม :id ในเส้นทาง ผู้ใช้ต้องอยู่ในองค์กรที่เลือก
+// คืนค่า 0 เมื่อส่ง response ข้อผิดพลาด (400/404/500) ไปแล้ว
+func (sc *SessionController) findUser(c *fiber.Ctx) (int, error) {
+	userID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return 0, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	if _, err := sc.Users.ForTenant(tenantID(c)).GetByID(c.UserContext(), userID); errors.Is(err, repository.ErrNotFound) {
+		return 0, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	} else if err != nil {
+		return 0, utils.ErrorResponse(c, fiber.StatusInternalServerError, "เกิดข้อผิดพลาดในฐานข้อมูล", err)
+	}
+	return userID, nil
+}
+
+// listSessions ส่งรายการ session ที่ยังใช้งานได้ของผู้ใช้ โดยทำเครื่องหมาย session ที่มี ID เท่ากับ current
+func (sc *SessionController) listSessions(c *fiber.Ctx, userID, current int) error {
+	sessions, err := sc.Sessions.ListActive(c.UserContext(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล session ได้", err)
+	}
+
+	responses := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, models.SessionResponse{Session: session, Current: session.ID == current})
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูล session สำเร็จ", responses)
+}
+
+// revokeSession เพิกถอน session ตาม ID ในพารามิเตอร์ param ของเส้นทาง (เฉพาะ session ของ userID)
+func (sc *SessionController) revokeSession(c *fiber.Ctx, userID int, param string) error {
+	id, err := strconv.Atoi(c.Params(param))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของ session ไม่ถูกต้อง", err)
+	}
+	revoked, err := sc.Sessions.Revoke(c.UserContext(), userID, id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเพิกถอน session ได้", err)
+	}
+	if !revoked {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบ session", nil)
+	}
+	return utils.SuccessResponse(c, "เพิกถอน session สำเร็จ", nil)
+}
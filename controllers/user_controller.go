@@ -1,70 +1,88 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"strconv"
+	"time"
 
 	"github.com/Sing254463/GoTemplate/Backend/config"
 	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/password"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/storage"
 	"github.com/Sing254463/GoTemplate/Backend/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
 )
 
 // UserController โครงสร้างสำหรับจัดการข้อมูลผู้ใช้
 // ใช้สำหรับผู้ดูแลระบบ (Admin) ในการจัดการผู้ใช้ต่างๆ
+// Admin ขององค์กรเห็นและจัดการได้เฉพาะผู้ใช้ในองค์กรที่เลือก (tenant_id จาก TenantMiddleware)
 type UserController struct {
-	Config    *config.Config      // การตั้งค่าระบบ
-	Validator *validator.Validate // ตัวตรวจสอบความถูกต้องของข้อมูล
+	Config    *config.Store                 // การตั้งค่าระบบ
+	DB        *sqlx.DB                      // การเชื่อมต่อฐานข้อมูล
+	Users     *repository.UserRepository    // การเข้าถึงตาราง users (จำกัดองค์กรด้วย ForTenant)
+	Validator *validator.Validate           // ตัวตรวจสอบความถูกต้องของข้อมูล
+	Policy    *password.Checker             // นโยบายรหัสผ่านของผู้ใช้ที่นำเข้า (PASSWORD_*)
+	Sessions  *repository.SessionRepository // session ของ token ที่ออกเมื่อเข้าสู่ระบบแทนผู้ใช้
+	Audit     *repository.AuditRepository   // บันทึกการเข้าสู่ระบบแทนผู้ใช้
 }
 
 // NewUserController ฟังก์ชันสร้าง UserController ใหม่
-func NewUserController(cfg *config.Config) *UserController {
+func NewUserController(cfg *config.Store, db *sqlx.DB) *UserController {
 	return &UserController{
 		Config:    cfg,
+		DB:        db,
+		Users:     repository.NewUserRepository(db),
 		Validator: validator.New(),
+		Policy:    password.NewChecker(cfg),
+		Sessions:  repository.NewSessionRepository(db),
+		Audit:     repository.NewAuditRepository(db),
 	}
 }
 
-// GetAllUsers ฟังก์ชันสำหรับดูรายชื่อผู้ใช้ทั้งหมด (เฉพาะ Admin)
+// GetAllUsers ฟังก์ชันสำหรับดูรายชื่อผู้ใช้ทั้งหมด (เฉพาะ Admin ของระบบหรือขององค์กร)
 // @Summary Get all users
-// @Description Get all users (Admin only)
+// @Description Get all users of the selected organization, or every user for a platform Admin without an organization (Admin or organization Admin)
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 403 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /users [get]
 func (uc *UserController) GetAllUsers(c *fiber.Ctx) error {
-	// สร้างตัวแปรสำหรับเก็บรายชื่อผู้ใช้ทั้งหมด
-	var users []models.User
-
-	// Query ดึงข้อมูลผู้ใช้ทั้งหมดจากฐานข้อมูล (ไม่รวมรหัสผ่าน)
-	query := "SELECT id, username, email, role, created_at, updated_at FROM users"
-	err := uc.Config.Database.DB.Select(&users, query)
+	// ดึงข้อมูลผู้ใช้ทั้งหมดในองค์กรที่เลือกจากฐานข้อมูล (ไม่รวมรหัสผ่าน)
+	users, err := uc.Users.ForTenant(tenantID(c)).List(c.UserContext())
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
 	}
 
 	// แปลงข้อมูลผู้ใช้เป็นรูปแบบที่จะส่งกลับ (ซ่อนข้อมูลที่ไม่จำเป็น)
-	var userResponses []models.UserResponse
-	for _, user := range users {
-		userResponses = append(userResponses, user.ConvertToResponse())
+	cfg := uc.Config.Get()
+	userResponses := make([]models.UserResponse, 0, len(users))
+	for i := range users {
+		userResponses = append(userResponses, userResponse(cfg, &users[i]))
 	}
 
 	// ส่งรายชื่อผู้ใช้ทั้งหมดกลับไป
 	return utils.SuccessResponse(c, "ดึงข้อมูลผู้ใช้ทั้งหมดสำเร็จ", userResponses)
 }
 
-// GetUserByID ฟังก์ชันสำหรับดูข้อมูลผู้ใช้ตาม ID (เฉพาะ Admin)
+// GetUserByID ฟังก์ชันสำหรับดูข้อมูลผู้ใช้ตาม ID (เฉพาะ Admin ของระบบหรือขององค์กร)
 // @Summary Get user by ID
-// @Description Get user by ID (Admin only)
+// @Description Get user by ID. Users outside the selected organization are not found (Admin or organization Admin)
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param id path int true "User ID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
@@ -80,25 +98,28 @@ func (uc *UserController) GetUserByID(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
 	}
 
-	// ค้นหาผู้ใช้ในฐานข้อมูลด้วย ID
-	var user models.User
-	query := "SELECT id, username, email, role, created_at, updated_at FROM users WHERE id = ?"
-	err = uc.Config.Database.DB.Get(&user, query, id)
+	// ค้นหาผู้ใช้ในองค์กรที่เลือกด้วย ID (ผู้ใช้ขององค์กรอื่นถือว่าไม่พบ)
+	user, err := uc.Users.ForTenant(tenantID(c)).GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	}
 	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", err)
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
 	}
 
 	// ส่งข้อมูลผู้ใช้ที่พบกลับไป
-	return utils.SuccessResponse(c, "ดึงข้อมูลผู้ใช้สำเร็จ", user.ConvertToResponse())
+	return utils.SuccessResponse(c, "ดึงข้อมูลผู้ใช้สำเร็จ", userResponse(uc.Config.Get(), user))
 }
 
-// DeleteUser ฟังก์ชันสำหรับลบผู้ใช้ตาม ID (เฉพาะ Admin)
+// DeleteUser ฟังก์ชันสำหรับลบผู้ใช้ตาม ID (เฉพาะ Admin ของระบบหรือขององค์กร)
+// Admin ขององค์กรนำผู้ใช้ออกจากองค์กร บัญชีถูกลบเมื่อไม่ได้เป็นสมาชิกขององค์กรอื่นแล้วเท่านั้น
 // @Summary Delete user
-// @Description Delete user by ID (Admin only)
+// @Description Delete user by ID. Within an organization the user is removed from it and the account is deleted only when it belongs to no other organization (Admin or organization Admin)
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Param id path int true "User ID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
@@ -114,21 +135,184 @@ func (uc *UserController) DeleteUser(c *fiber.Ctx) error {
 		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
 	}
 
-	// ตรวจสอบว่ามีผู้ใช้ที่มี ID นี้อยู่หรือไม่
-	var user models.User
-	query := "SELECT id FROM users WHERE id = ?"
-	err = uc.Config.Database.DB.Get(&user, query, id)
-	if err != nil {
-		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", err)
-	}
-
-	// ลบผู้ใช้จากฐานข้อมูล
-	deleteQuery := "DELETE FROM users WHERE id = ?"
-	_, err = uc.Config.Database.DB.Exec(deleteQuery, id)
+	// ลบผู้ใช้ในองค์กรที่เลือก (ผู้ใช้ขององค์กรอื่นถือว่าไม่พบ)
+	deleted, err := uc.Users.ForTenant(tenantID(c)).Delete(c.UserContext(), id)
 	if err != nil {
 		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถลบผู้ใช้ได้", err)
 	}
+	if !deleted {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	}
+
+	// ลบรูปโปรไฟล์เมื่อบัญชีถูกลบจริง (การนำออกจากองค์กรเดียวอาจยังเหลือบัญชีไว้)
+	uc.removeAvatarIfDeleted(c, id)
 
 	// ส่งผลลัพธ์การลบสำเร็จกลับไป
 	return utils.SuccessResponse(c, "ลบผู้ใช้สำเร็จ", nil)
 }
+
+// BatchUsers ฟังก์ชันสำหรับจัดการผู้ใช้หลายรายพร้อมกัน (เฉพาะ Admin ของระบบหรือขององค์กร)
+// ทำงานใน transaction เดียวและบันทึก audit log หนึ่งรายการ ผู้ใช้ที่ติดข้อห้ามถูกข้ามและแจ้งในผลรายผู้ใช้
+// Admin ขององค์กรสั่งได้เฉพาะ delete (นำออกจากองค์กร) เช่นเดียวกับ DeleteUser
+// @Summary Batch user operation
+// @Description Delete, change the role of, disable or enable many users at once, selected by IDs or by a filter (up to 1000 users). Runs in one transaction and is recorded as one audit log entry. Users that would lock administrators out are skipped: the caller's own account (except enable) and the last active Admin of the platform or of the organization. Within an organization, delete removes users from it and set_role changes their organization role. Organization Admins may only delete.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param batch body models.UserBatchRequest true "Action and users"
+// @Success 200 {object} utils.Response{data=models.UserBatchResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/batch [post]
+func (uc *UserController) BatchUsers(c *fiber.Ctx) error {
+	var req models.UserBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := uc.Validator.Struct(&req); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if req.Filter != nil && req.Filter.IsEmpty() {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "filter ต้องมีอย่างน้อยหนึ่งเงื่อนไข", nil)
+	}
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "until ต้องเป็นเวลาในอนาคต", nil)
+	}
+	if req.Action != models.UserBatchDelete && c.Locals("role") != "admin" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "Admin ขององค์กรสั่งได้เฉพาะ delete", nil)
+	}
+
+	actorID := c.Locals("user_id").(int)
+	audit := &models.AuditLog{ActorID: &actorID, Action: "users.batch." + req.Action, IPAddress: c.IP()}
+	if tenant := tenantID(c); tenant != 0 {
+		audit.OrganizationID = &tenant
+	}
+	response, err := uc.Users.ForTenant(tenantID(c)).Batch(c.UserContext(), &req, audit)
+	if errors.Is(err, repository.ErrBatchTooLarge) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, err.Error(), nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถจัดการผู้ใช้ได้", err)
+	}
+
+	if req.Action == models.UserBatchDelete {
+		for _, result := range response.Results {
+			if result.Status == models.UserBatchOK {
+				uc.removeAvatarIfDeleted(c, result.ID)
+			}
+		}
+	}
+	return utils.SuccessResponse(c, fmt.Sprintf("ดำเนินการกับผู้ใช้ %d จาก %d ราย", response.Succeeded, len(response.Results)), response)
+}
+
+// SuspendUser ฟังก์ชันสำหรับระงับบัญชีผู้ใช้ (เฉพาะ Admin ของระบบ)
+// เพิกถอน session ทั้งหมดของผู้ใช้ และ token/API key ของผู้ใช้ถูกปฏิเสธด้วยรหัส account_suspended
+// ระงับซ้ำได้เพื่อเปลี่ยนเหตุผลหรือเวลาสิ้นสุด
+// @Summary Suspend user
+// @Description Suspend a user account with an optional reason and expiry. All sessions are revoked, login is refused with code account_suspended and existing tokens and API keys of the user stop working. Suspending again updates the reason and expiry. The caller's own account and the last active Admin cannot be suspended (platform Admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "User ID"
+// @Param suspension body models.UserSuspend false "Reason and expiry"
+// @Success 200 {object} utils.Response{data=models.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Last active Admin"
+// @Failure 500 {object} utils.Response
+// @Router /users/{id}/suspend [post]
+func (uc *UserController) SuspendUser(c *fiber.Ctx) error {
+	var input models.UserSuspend
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+		}
+	}
+	if err := uc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if input.Until != nil && !input.Until.After(time.Now()) {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "until ต้องเป็นเวลาในอนาคต", nil)
+	}
+	req := &models.UserBatchRequest{Action: models.UserBatchDisable, Reason: input.Reason, Until: input.Until}
+	return uc.setUserStatus(c, req, "users.suspend", "ระงับบัญชีผู้ใช้สำเร็จ")
+}
+
+// ReinstateUser ฟังก์ชันสำหรับยกเลิกการระงับ หรือเปิดใช้บัญชีที่รอเปิดใช้งาน (เฉพาะ Admin ของระบบ)
+// @Summary Reinstate user
+// @Description Lift a suspension or activate a pending account. The user must log in again because suspension revoked their sessions (platform Admin only)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response{data=models.UserResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/{id}/reinstate [post]
+func (uc *UserController) ReinstateUser(c *fiber.Ctx) error {
+	req := &models.UserBatchRequest{Action: models.UserBatchEnable}
+	return uc.setUserStatus(c, req, "users.reinstate", "ยกเลิกการระงับบัญชีผู้ใช้สำเร็จ")
+}
+
+// setUserStatus เปลี่ยนสถานะบัญชีของผู้ใช้ตาม :id ด้วยคำสั่ง batch ผู้ใช้รายเดียว
+// (ใช้ข้อห้ามและการบันทึก audit log เดียวกับ POST /users/batch) แล้วส่งข้อมูลผู้ใช้ล่าสุดกลับ
+func (uc *UserController) setUserStatus(c *fiber.Ctx, req *models.UserBatchRequest, action, message string) error {
+	if c.Locals("role") != "admin" {
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเป็น Admin ของระบบเท่านั้น", nil)
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ผู้ใช้ไม่ถูกต้อง", err)
+	}
+	req.IDs = []int{id}
+
+	actorID := c.Locals("user_id").(int)
+	audit := &models.AuditLog{ActorID: &actorID, Action: action, IPAddress: c.IP()}
+	if tenant := tenantID(c); tenant != 0 {
+		audit.OrganizationID = &tenant
+	}
+	users := uc.Users.ForTenant(tenantID(c))
+	response, err := users.Batch(c.UserContext(), req, audit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเปลี่ยนสถานะบัญชีได้", err)
+	}
+
+	result := response.Results[0]
+	switch {
+	case result.Status == models.UserBatchNotFound:
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบผู้ใช้", nil)
+	case result.Reason == "self":
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถระงับบัญชีของตัวเองได้", nil)
+	case result.Reason == "last_admin":
+		return utils.ErrorResponse(c, fiber.StatusConflict, "ไม่สามารถระงับ Admin ที่ใช้งานได้คนสุดท้ายได้", nil)
+	}
+
+	user, err := users.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
+	}
+	return utils.SuccessResponse(c, message, userResponse(uc.Config.Get(), user))
+}
+
+// removeAvatarIfDeleted ลบรูปโปรไฟล์ของผู้ใช้เมื่อบัญชีไม่อยู่แล้ว (ความผิดพลาดบันทึก log เท่านั้น)
+func (uc *UserController) removeAvatarIfDeleted(c *fiber.Ctx, id int) {
+	if _, err := uc.Users.GetByID(c.UserContext(), id); !errors.Is(err, repository.ErrNotFound) {
+		return
+	}
+	if err := storage.New(uc.Config.Get().Storage).DeletePrefix(c.UserContext(), avatarPrefix(id)); err != nil {
+		log.Printf("⚠️  ไม่สามารถลบรูปโปรไฟล์ของผู้ใช้ %d: %v", id, err)
+	}
+}
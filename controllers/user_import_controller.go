@@ -0,0 +1,392 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/password"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxImportRows จำนวนแถวสูงสุดต่อการนำเข้าหนึ่งครั้ง (ไฟล์ใหญ่กว่านี้ให้แบ่งส่ง)
+const maxImportRows = 1000
+
+// exportFlushRows จำนวนแถวที่เขียนก่อนส่งข้อมูลออกไปยัง client ระหว่าง export
+const exportFlushRows = 500
+
+// importRow แถวหนึ่งของไฟล์นำเข้าผู้ใช้
+type importRow struct {
+	Line  int                 // บรรทัดในไฟล์
+	Input models.UserRegister // ข้อมูลของแถว (ตรวจด้วยกฎเดียวกับการลงทะเบียน)
+	Error *models.UserImportError
+}
+
+// ImportUsers ฟังก์ชันสำหรับนำเข้าผู้ใช้จำนวนมากจากไฟล์ CSV หรือ NDJSON (เฉพาะ Admin ของระบบหรือขององค์กร)
+// ทุกแถวถูกตรวจด้วยกฎเดียวกับ /auth/register (รวมนโยบายรหัสผ่าน) และบันทึกใน transaction เดียว
+// แถวใดไม่ผ่านจะไม่มีผู้ใช้ถูกสร้างเลย ส่วน dry_run=true ตรวจสอบและส่งรายงานโดยไม่บันทึก
+// @Summary Import users
+// @Description Create users in bulk from CSV (header: username,email,password) or NDJSON (one models.UserRegister per line), sent as the request body or as the multipart field "file". Every row is validated like /auth/register and all rows are created in one transaction, so any invalid row creates nothing. Users imported within an organization become its members. Up to 1000 rows per request.
+// @Tags users
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param format query string false "File format when it cannot be inferred from Content-Type or the file name" Enums(csv, ndjson)
+// @Param dry_run query bool false "Validate only and return the report without creating users"
+// @Param file formData file false "CSV or NDJSON file (instead of the raw request body)"
+// @Success 200 {object} utils.Response{data=models.UserImportResult} "Dry run report"
+// @Success 201 {object} utils.Response{data=models.UserImportResult}
+// @Failure 400 {object} utils.Response{data=models.UserImportResult} "Malformed file, or rows failed validation (nothing was created)"
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Failure 415 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/import [post]
+func (uc *UserController) ImportUsers(c *fiber.Ctx) error {
+	src, format, err := importSource(c)
+	if err != nil || src == nil {
+		return err
+	}
+	defer src.Close()
+
+	var rows []*importRow
+	if format == "csv" {
+		rows, err = parseImportCSV(src)
+	} else {
+		rows, err = parseImportNDJSON(src)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "อ่านไฟล์นำเข้าไม่ได้", err)
+	}
+	if len(rows) == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ไฟล์นำเข้าไม่มีข้อมูลผู้ใช้", nil)
+	}
+	if len(rows) > maxImportRows {
+		return utils.ErrorResponse(c, fiber.StatusRequestEntityTooLarge, fmt.Sprintf("นำเข้าได้ไม่เกิน %d แถวต่อครั้ง", maxImportRows), nil)
+	}
+
+	result := models.UserImportResult{DryRun: c.QueryBool("dry_run"), Total: len(rows), Errors: []models.UserImportError{}}
+	if err := uc.validateImport(c, rows, &result); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบข้อมูลนำเข้าได้", err)
+	}
+	if result.DryRun {
+		return utils.SuccessResponse(c, "ตรวจสอบไฟล์นำเข้าแล้ว (ยังไม่ได้สร้างผู้ใช้)", result)
+	}
+	if len(result.Errors) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.Response{
+			Status:  false,
+			Message: "ข้อมูลบางแถวไม่ผ่านการตรวจสอบ จึงไม่ได้สร้างผู้ใช้",
+			Code:    utils.CodeValidationFailed,
+			Data:    result,
+		})
+	}
+
+	cfg := uc.Config.Get()
+	hasher := password.NewHasher(cfg.Password)
+	users := make([]*models.User, 0, len(rows))
+	for _, row := range rows {
+		hash, err := hasher.Hash(row.Input.Password)
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถเข้ารหัสรหัสผ่านได้", err)
+		}
+		users = append(users, &models.User{Username: row.Input.Username, Email: row.Input.Email, Password: hash, Role: "user", Status: models.UserStatusActive})
+	}
+	err = uc.Users.ForTenant(tenantID(c)).Import(c.UserContext(), users, cfg.Password.History)
+	if errors.Is(err, repository.ErrUserExists) {
+		return utils.ErrorResponse(c, fiber.StatusConflict, "มีผู้ใช้บางรายถูกสร้างระหว่างการนำเข้า กรุณาตรวจสอบไฟล์อีกครั้ง", nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถนำเข้าผู้ใช้ได้", err)
+	}
+	result.Created = len(users)
+	return utils.CreatedResponse(c, "นำเข้าผู้ใช้สำเร็จ", result)
+}
+
+// ExportUsers ฟังก์ชันสำหรับส่งออกผู้ใช้ทั้งหมดเป็นไฟล์ CSV หรือ NDJSON (เฉพาะ Admin ของระบบหรือขององค์กร)
+// อ่านจากฐานข้อมูลและเขียนออกทีละแถว (ไม่โหลดผู้ใช้ทั้งหมดไว้ในหน่วยความจำเหมือน GetAllUsers)
+// @Summary Export users
+// @Description Stream every user of the selected organization (or every user for a platform Admin without an organization) as CSV or NDJSON. Passwords and metadata are not exported.
+// @Tags users
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param format query string false "Output format" Enums(csv, ndjson) default(csv)
+// @Success 200 {array} models.UserExport
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/export [get]
+func (uc *UserController) ExportUsers(c *fiber.Ctx) error {
+	format := strings.ToLower(c.Query("format", "csv"))
+	if format != "csv" && format != "ndjson" {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "format ต้องเป็น csv หรือ ndjson", nil)
+	}
+
+	rows, err := uc.Users.ForTenant(tenantID(c)).Rows(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูลผู้ใช้ได้", err)
+	}
+
+	if format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	}
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="users.`+format+`"`)
+
+	// body ถูกเขียนหลัง handler คืนค่า ความผิดพลาดระหว่างนี้เปลี่ยน status ไม่ได้แล้ว จึงบันทึก log เท่านั้น
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer rows.Close()
+
+		csvWriter := csv.NewWriter(w)
+		encoder := json.NewEncoder(w)
+		if format == "csv" {
+			csvWriter.Write(models.UserExportColumns)
+		}
+		for count := 1; rows.Next(); count++ {
+			var user models.User
+			if err := rows.StructScan(&user); err != nil {
+				log.Printf("⚠️  export ผู้ใช้ไม่สำเร็จ: %v", err)
+				return
+			}
+			row := user.ConvertToExport()
+			if format == "csv" {
+				record := row.Record()
+				for i := range record {
+					record[i] = csvCell(record[i])
+				}
+				csvWriter.Write(record)
+			} else if err := encoder.Encode(row); err != nil {
+				return
+			}
+			if count%exportFlushRows == 0 {
+				csvWriter.Flush()
+				if err := w.Flush(); err != nil {
+					return // client ปิดการเชื่อมต่อแล้ว
+				}
+			}
+		}
+		csvWriter.Flush()
+		if err := rows.Err(); err != nil {
+			log.Printf("⚠️  export ผู้ใช้ไม่สำเร็จ: %v", err)
+		}
+	})
+	return nil
+}
+
+// validateImport ตรวจทุกแถวด้วยกฎของ models.UserRegister, นโยบายรหัสผ่าน, ข้อมูลซ้ำในไฟล์ และบัญชีที่มีอยู่แล้ว
+// แล้วเติมจำนวนแถวที่ผ่านและข้อผิดพลาดรายแถวลงใน result
+func (uc *UserController) validateImport(c *fiber.Ctx, rows []*importRow, result *models.UserImportResult) error {
+	var emails, usernames []string
+	for _, row := range rows {
+		if row.Error == nil {
+			emails = append(emails, strings.ToLower(row.Input.Email))
+			usernames = append(usernames, row.Input.Username)
+		}
+	}
+	takenEmails, takenUsernames, err := uc.Users.Taken(c.UserContext(), emails, usernames)
+	if err != nil {
+		return err
+	}
+
+	seenEmails, seenUsernames := map[string]int{}, map[string]int{}
+	for _, row := range rows {
+		if row.Error != nil {
+			result.Errors = append(result.Errors, *row.Error)
+			continue
+		}
+		errs := importFieldErrors(uc.Validator.Struct(&row.Input))
+
+		email := strings.ToLower(row.Input.Email)
+		if line, ok := seenEmails[email]; ok && email != "" {
+			errs = append(errs, utils.FieldError{Field: "email", Code: "duplicate", Message: fmt.Sprintf("อีเมลซ้ำกับบรรทัดที่ %d", line)})
+		} else if takenEmails[email] {
+			errs = append(errs, utils.FieldError{Field: "email", Code: "exists", Message: "มีผู้ใช้ที่ใช้อีเมลนี้อยู่แล้ว"})
+		} else {
+			seenEmails[email] = row.Line
+		}
+		if line, ok := seenUsernames[row.Input.Username]; ok && row.Input.Username != "" {
+			errs = append(errs, utils.FieldError{Field: "username", Code: "duplicate", Message: fmt.Sprintf("ชื่อผู้ใช้ซ้ำกับบรรทัดที่ %d", line)})
+		} else if takenUsernames[row.Input.Username] {
+			errs = append(errs, utils.FieldError{Field: "username", Code: "exists", Message: "มีผู้ใช้ที่ใช้ชื่อนี้อยู่แล้ว"})
+		} else {
+			seenUsernames[row.Input.Username] = row.Line
+		}
+
+		if row.Input.Password != "" {
+			violations, err := uc.Policy.Check(row.Input.Password, password.UserInfo{Username: row.Input.Username, Email: row.Input.Email})
+			if err != nil {
+				return err
+			}
+			errs = append(errs, passwordErrors("password", violations)...)
+		}
+
+		if len(errs) == 0 {
+			result.Valid++
+		}
+		for _, e := range errs {
+			result.Errors = append(result.Errors, models.UserImportError{Line: row.Line, Field: e.Field, Code: e.Code, Message: e.Message})
+		}
+	}
+	return nil
+}
+
+// importSource คืนค่าข้อมูลของไฟล์นำเข้า (multipart field "file" หรือ request body) และรูปแบบไฟล์ (csv/ndjson)
+// รูปแบบอ่านจาก query format, นามสกุลไฟล์ หรือ Content-Type ตามลำดับ
+// คืนค่า reader เป็น nil เมื่อส่ง response ข้อผิดพลาดไปแล้ว
+func importSource(c *fiber.Ctx) (io.ReadCloser, string, error) {
+	format := strings.ToLower(c.Query("format"))
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+
+	var src io.ReadCloser = io.NopCloser(bytes.NewReader(c.Body()))
+	if contentType == fiber.MIMEMultipartForm {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ต้องส่งไฟล์ใน field file (multipart/form-data)", err)
+		}
+		if src, err = file.Open(); err != nil {
+			return nil, "", utils.ErrorResponse(c, fiber.StatusBadRequest, "ไม่สามารถอ่านไฟล์นำเข้าได้", err)
+		}
+		contentType = strings.ToLower(file.Header.Get(fiber.HeaderContentType))
+		switch strings.ToLower(path.Ext(file.Filename)) {
+		case ".csv":
+			contentType = "text/csv"
+		case ".ndjson", ".jsonl":
+			contentType = "application/x-ndjson"
+		}
+	}
+
+	if format == "" {
+		switch contentType {
+		case "text/csv", "application/csv":
+			format = "csv"
+		case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+			format = "ndjson"
+		}
+	}
+	if format != "csv" && format != "ndjson" {
+		src.Close()
+		return nil, "", utils.ErrorResponse(c, fiber.StatusUnsupportedMediaType,
+			"รองรับไฟล์ CSV (text/csv) หรือ NDJSON (application/x-ndjson) เท่านั้น หรือระบุ format=csv|ndjson", nil)
+	}
+	return src, format, nil
+}
+
+// parseImportCSV อ่านไฟล์ CSV ที่มีหัวตาราง username, email, password (ลำดับใดก็ได้ คอลัมน์อื่นถูกข้าม)
+func parseImportCSV(src io.Reader) ([]*importRow, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // BOM ของไฟล์ที่บันทึกจาก Excel
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"username", "email", "password"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("หัวตารางต้องมีคอลัมน์ %s", name)
+		}
+	}
+
+	var rows []*importRow
+	for len(rows) <= maxImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, &importRow{Line: line, Input: models.UserRegister{
+			Username: cell("username"),
+			Email:    cell("email"),
+			Password: cell("password"),
+		}})
+	}
+	return rows, nil
+}
+
+// parseImportNDJSON อ่านไฟล์ NDJSON (JSON object ของ models.UserRegister หนึ่งรายการต่อบรรทัด บรรทัดว่างถูกข้าม)
+// บรรทัดที่ไม่ใช่ JSON ที่ถูกต้องถูกรายงานเป็นข้อผิดพลาดของแถวนั้น
+func parseImportNDJSON(src io.Reader) ([]*importRow, error) {
+	scanner := bufio.NewScanner(src)
+	var rows []*importRow
+	for line := 1; scanner.Scan() && len(rows) <= maxImportRows; line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		row := &importRow{Line: line}
+		if err := json.Unmarshal(text, &row.Input); err != nil {
+			row.Error = &models.UserImportError{Line: line, Code: "invalid_json", Message: "บรรทัดนี้ไม่ใช่ JSON object ที่ถูกต้อง: " + err.Error()}
+		}
+		row.Input.Username = strings.TrimSpace(row.Input.Username)
+		row.Input.Email = strings.TrimSpace(row.Input.Email)
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// importFieldErrors แปลงข้อผิดพลาดของ validator เป็นข้อผิดพลาดราย field (ชื่อ field ตาม JSON ของ models.UserRegister)
+func importFieldErrors(err error) []utils.FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+	errs := make([]utils.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		message := "ไม่ผ่านกฎ " + fe.Tag()
+		if fe.Param() != "" {
+			message += "=" + fe.Param()
+		}
+		errs = append(errs, utils.FieldError{Field: strings.ToLower(fe.Field()), Code: fe.Tag(), Message: message})
+	}
+	return errs
+}
+
+// csvCell ป้องกัน formula injection เมื่อเปิดไฟล์ CSV ด้วยโปรแกรม spreadsheet
+// ค่าที่ขึ้นต้นด้วย = + - @ ถูกนำหน้าด้วย ' (ยกเว้นตัวเลขเช่นเบอร์โทรศัพท์ +66...)
+func csvCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if strings.Trim(value[1:], "0123456789") == "" && len(value) > 1 && (value[0] == '+' || value[0] == '-') {
+		return value
+	}
+	return "'" + value
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/search"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// searchPageSize จำนวนผลลัพธ์ต่อหน้าเริ่มต้นและสูงสุดของ GET /users/search
+const (
+	searchPageSize    = 20
+	searchMaxPageSize = 100
+)
+
+// maxSearchQueryLength ความยาวสูงสุดของคำค้น (ตัวอักษร)
+const maxSearchQueryLength = 100
+
+// น้ำหนักของแต่ละ field ในการจัดอันดับ (อีเมลต่ำกว่าเล็กน้อย เพราะโดเมนเดียวกันตรงกับผู้ใช้จำนวนมาก)
+const (
+	searchWeightName  = 1.0
+	searchWeightEmail = 0.9
+)
+
+// SearchUsers ฟังก์ชันสำหรับค้นหาผู้ใช้ด้วยบางส่วนของชื่อผู้ใช้ อีเมล หรือชื่อที่แสดง (เฉพาะ Admin ของระบบหรือขององค์กร)
+// ทนต่อการพิมพ์ผิดเล็กน้อย ผลลัพธ์เรียงตามคะแนนความตรงพร้อมไฮไลต์ส่วนที่ตรง และแบ่งหน้าด้วย page/per_page
+// @Summary Search users
+// @Description Ranked, typo-tolerant search over username, email and display name. Matches are highlighted with <mark> in HTML-escaped text. Organization Admins search only their organization's members. Candidates are pre-ranked in the database (exact > prefix > contains, then typo n-gram overlap) and capped at 500 per stage; when the cap is hit, weaker matches are dropped and the envelope sets approximate=true because total counts only the ranked candidates.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param q query string true "Search text (2-100 characters)"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Results per page (max 100)" default(20)
+// @Success 200 {object} utils.Response{data=utils.Page{items=[]models.UserSearchResult}}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/search [get]
+func (uc *UserController) SearchUsers(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+	terms := search.Terms(query)
+	if length := utf8.RuneCountInString(query); length < search.MinQueryLength || length > maxSearchQueryLength || len(terms) == 0 {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "คำค้นต้องมีความยาว 2-100 ตัวอักษร", nil)
+	}
+	page := c.QueryInt("page", 1)
+	perPage := c.QueryInt("per_page", searchPageSize)
+	if page < 1 || perPage < 1 || perPage > searchMaxPageSize {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "page หรือ per_page ไม่ถูกต้อง", errors.New("page >= 1 และ per_page ระหว่าง 1-100"))
+	}
+
+	// หาผู้สมัครในองค์กรที่เลือกจากฐานข้อมูล แล้วจัดอันดับใหม่ด้วยการเทียบแบบ fuzzy
+	users, truncated, err := uc.Users.ForTenant(tenantID(c)).Search(c.UserContext(), terms)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถค้นหาผู้ใช้ได้", err)
+	}
+	cfg := uc.Config.Get()
+	results := []models.UserSearchResult{}
+	for i := range users {
+		match := search.Score(terms, []search.Field{
+			{Name: "username", Text: users[i].Username, Weight: searchWeightName},
+			{Name: "email", Text: users[i].Email, Weight: searchWeightEmail},
+			{Name: "display_name", Text: users[i].DisplayName, Weight: searchWeightName},
+		})
+		if match.Score < search.MinScore {
+			continue
+		}
+		results = append(results, models.UserSearchResult{
+			UserResponse: userResponse(cfg, &users[i]),
+			Score:        math.Round(match.Score*1000) / 1000,
+			Highlights:   match.Highlights,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	// เมื่อผู้สมัครถูกตัด จำนวนทั้งหมดนับเฉพาะผู้สมัครที่ตรงที่สุด จึงเป็นค่าประมาณ
+	pageData, start, end := utils.NewPage(page, perPage, len(results))
+	pageData.Approximate = truncated
+	pageData.Items = results[start:end]
+	return utils.SuccessResponse(c, "ค้นหาผู้ใช้สำเร็จ", pageData)
+}
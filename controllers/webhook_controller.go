@@ -0,0 +1,384 @@
+package controllers
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// deliveryPageSize จำนวนบันทึกการส่งต่อหน้าเริ่มต้นและสูงสุดของ GET /webhooks/:id/deliveries
+const (
+	deliveryPageSize    = 50
+	deliveryMaxPageSize = 200
+)
+
+// WebhookController โครงสร้างสำหรับจัดการ webhook ที่ส่ง event ของผู้ใช้ไปยังระบบภายนอก (เฉพาะ Admin)
+type WebhookController struct {
+	Config    *config.Store                 // การตั้งค่าระบบ
+	DB        *sqlx.DB                      // การเชื่อมต่อฐานข้อมูล
+	Webhooks  *repository.WebhookRepository // การเข้าถึงตาราง webhooks และ webhook_deliveries
+	Validator *validator.Validate           // ตัวตรวจสอบความถูกต้องของข้อมูล
+}
+
+// NewWebhookController ฟังก์ชันสร้าง WebhookController ใหม่
+func NewWebhookController(cfg *config.Store, db *sqlx.DB) *WebhookController {
+	return &WebhookController{
+		Config:    cfg,
+		DB:        db,
+		Webhooks:  repository.NewWebhookRepository(db),
+		Validator: validator.New(),
+	}
+}
+
+// CreateWebhook ฟังก์ชันสำหรับลงทะเบียน webhook ใหม่ (เฉพาะ Admin)
+// secret สำหรับตรวจลายเซ็นจะถูกส่งกลับเพียงครั้งเดียวใน response นี้ (ไม่ระบุ = ระบบสร้างให้)
+// @Summary Create webhook
+// @Description Register a URL that receives signed POST requests for the selected user events (Admin only). The signing secret is returned only once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param webhook body models.WebhookCreate true "Webhook data"
+// @Success 201 {object} utils.Response{data=models.WebhookResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks [post]
+func (wc *WebhookController) CreateWebhook(c *fiber.Ctx) error {
+	var input models.WebhookCreate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := wc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+	if err := wc.checkURL(input.URL); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "URL ของ webhook ไม่ถูกต้อง", err)
+	}
+
+	webhook := models.Webhook{
+		URL:         input.URL,
+		Description: input.Description,
+		Events:      joinEvents(input.Events),
+		Secret:      input.Secret,
+		Active:      input.Active == nil || *input.Active,
+	}
+	if webhook.Secret == "" {
+		secret, err := utils.GenerateWebhookSecret()
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง secret ได้", err)
+		}
+		webhook.Secret = secret
+	}
+
+	id, err := wc.Webhooks.Create(c.UserContext(), &webhook)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถบันทึก webhook ได้", err)
+	}
+	created, err := wc.Webhooks.GetByID(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล webhook ได้", err)
+	}
+
+	response := created.ConvertToResponse()
+	response.Secret = created.Secret // แสดง secret เพียงครั้งเดียว
+	return utils.CreatedResponse(c, "ลงทะเบียน webhook สำเร็จ กรุณาเก็บ secret ไว้ เนื่องจากจะไม่แสดงอีก", response)
+}
+
+// GetWebhooks ฟังก์ชันสำหรับดูรายการ webhook (เฉพาะ Admin)
+// @Summary List webhooks
+// @Description List registered webhooks (Admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} utils.Response{data=[]models.WebhookResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks [get]
+func (wc *WebhookController) GetWebhooks(c *fiber.Ctx) error {
+	webhooks, err := wc.Webhooks.List(c.UserContext())
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล webhook ได้", err)
+	}
+
+	responses := make([]models.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		responses = append(responses, webhooks[i].ConvertToResponse())
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูล webhook สำเร็จ", responses)
+}
+
+// GetWebhook ฟังก์ชันสำหรับดูข้อมูล webhook ตาม ID (เฉพาะ Admin)
+// @Summary Get webhook
+// @Description Get a webhook by ID (Admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} utils.Response{data=models.WebhookResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/{id} [get]
+func (wc *WebhookController) GetWebhook(c *fiber.Ctx) error {
+	webhook, err := wc.findWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+	return utils.SuccessResponse(c, "ดึงข้อมูล webhook สำเร็จ", webhook.ConvertToResponse())
+}
+
+// UpdateWebhook ฟังก์ชันสำหรับแก้ไข URL, คำอธิบาย, event, สถานะการเปิดใช้ หรือสร้าง secret ใหม่ (เฉพาะ Admin)
+// เมื่อ rotate_secret เป็น true secret ใหม่จะถูกส่งกลับเพียงครั้งเดียว และการส่งที่ค้างอยู่จะถูกเซ็นด้วย secret ใหม่
+// @Summary Update webhook
+// @Description Update a webhook's URL, description, events or active flag, or rotate its secret (Admin only). A rotated secret is returned only once and also signs pending deliveries.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Webhook ID"
+// @Param webhook body models.WebhookUpdate true "Fields to change"
+// @Success 200 {object} utils.Response{data=models.WebhookResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks/{id} [patch]
+func (wc *WebhookController) UpdateWebhook(c *fiber.Ctx) error {
+	webhook, err := wc.findWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+	var input models.WebhookUpdate
+	if err := c.BodyParser(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลที่ส่งมาไม่ถูกต้อง", err)
+	}
+	if err := wc.Validator.Struct(&input); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ข้อมูลไม่ผ่านการตรวจสอบ", err)
+	}
+
+	if input.URL != nil {
+		if err := wc.checkURL(*input.URL); err != nil {
+			return utils.ErrorResponse(c, fiber.StatusBadRequest, "URL ของ webhook ไม่ถูกต้อง", err)
+		}
+		webhook.URL = *input.URL
+	}
+	if input.Description != nil {
+		webhook.Description = *input.Description
+	}
+	if len(input.Events) > 0 {
+		webhook.Events = joinEvents(input.Events)
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	if input.RotateSecret {
+		secret, err := utils.GenerateWebhookSecret()
+		if err != nil {
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถสร้าง secret ได้", err)
+		}
+		webhook.Secret = secret
+	}
+
+	if err := wc.Webhooks.Update(c.UserContext(), webhook); err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถแก้ไข webhook ได้", err)
+	}
+	updated, err := wc.Webhooks.GetByID(c.UserContext(), webhook.ID)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล webhook ได้", err)
+	}
+
+	response := updated.ConvertToResponse()
+	if input.RotateSecret {
+		response.Secret = updated.Secret
+	}
+	return utils.SuccessResponse(c, "แก้ไข webhook สำเร็จ", response)
+}
+
+// DeleteWebhook ฟังก์ชันสำหรับลบ webhook พร้อมบันทึกการส่งและการส่งที่ค้างอยู่ทั้งหมด (เฉพาะ Admin)
+// @Summary Delete webhook
+// @Description Delete a webhook together with its delivery log and pending deliveries (Admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks/{id} [delete]
+func (wc *WebhookController) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของ webhook ไม่ถูกต้อง", err)
+	}
+	deleted, err := wc.Webhooks.Delete(c.UserContext(), id)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถลบ webhook ได้", err)
+	}
+	if !deleted {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบ webhook", nil)
+	}
+	return utils.SuccessResponse(c, "ลบ webhook สำเร็จ", nil)
+}
+
+// GetWebhookDeliveries ฟังก์ชันสำหรับดูบันทึกการส่งของ webhook จากใหม่ไปเก่า (เฉพาะ Admin)
+// แบ่งหน้าด้วย before = ID ของบันทึกสุดท้ายในหน้าก่อนหน้า
+// @Summary List webhook deliveries
+// @Description List a webhook's deliveries with status, attempts, last response and payload, newest first (Admin only). Page with before = the last ID of the previous page.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Webhook ID"
+// @Param limit query int false "Page size (max 200)" default(50)
+// @Param before query int false "Return deliveries older than this ID"
+// @Success 200 {object} utils.Response{data=[]models.WebhookDeliveryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks/{id}/deliveries [get]
+func (wc *WebhookController) GetWebhookDeliveries(c *fiber.Ctx) error {
+	webhook, err := wc.findWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+	limit := c.QueryInt("limit", deliveryPageSize)
+	if limit <= 0 || limit > deliveryMaxPageSize {
+		limit = deliveryMaxPageSize
+	}
+	deliveries, err := wc.Webhooks.Deliveries(c.UserContext(), webhook.ID, c.QueryInt("before"), limit)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงบันทึกการส่งได้", err)
+	}
+
+	responses := make([]models.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		responses = append(responses, deliveries[i].ConvertToResponse())
+	}
+	return utils.SuccessResponse(c, "ดึงบันทึกการส่งสำเร็จ", responses)
+}
+
+// RedeliverWebhook ฟังก์ชันสำหรับส่ง event ของบันทึกการส่งที่ระบุซ้ำ (เฉพาะ Admin)
+// สร้างการส่งใหม่ด้วย event_id และ payload เดิม ซึ่ง worker จะส่งในรอบถัดไป (แม้การส่งเดิมจะสำเร็จแล้ว)
+// @Summary Redeliver webhook event
+// @Description Queue a new delivery of the same event (same event ID and payload) for the next dispatch round (Admin only)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 201 {object} utils.Response{data=models.WebhookDeliveryResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (wc *WebhookController) RedeliverWebhook(c *fiber.Ctx) error {
+	webhook, err := wc.findWebhook(c)
+	if err != nil || webhook == nil {
+		return err
+	}
+	deliveryID, err := strconv.Atoi(c.Params("deliveryId"))
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของการส่งไม่ถูกต้อง", err)
+	}
+	delivery, err := wc.Webhooks.GetDelivery(c.UserContext(), webhook.ID, deliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบบันทึกการส่ง", nil)
+	}
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงบันทึกการส่งได้", err)
+	}
+
+	queued, err := wc.Webhooks.Redeliver(c.UserContext(), delivery)
+	if err != nil {
+		return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถส่งซ้ำได้", err)
+	}
+	message := "เพิ่มการส่งซ้ำสำเร็จ"
+	if !webhook.Active {
+		message += " (webhook ถูกปิดอยู่ จะส่งเมื่อเปิดใช้อีกครั้ง)"
+	}
+	return utils.CreatedResponse(c, message, queued.ConvertToResponse())
+}
+
+// findWebhook อ่าน webhook ตาม :id ในเส้นทาง
+// คืนค่า webhook เป็น nil เมื่อส่ง response ข้อผิดพลาด (400/404/500) ไปแล้ว
+func (wc *WebhookController) findWebhook(c *fiber.Ctx) (*models.Webhook, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusBadRequest, "ID ของ webhook ไม่ถูกต้อง", err)
+	}
+	webhook, err := wc.Webhooks.GetByID(c.UserContext(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบ webhook", nil)
+	}
+	if err != nil {
+		return nil, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถดึงข้อมูล webhook ได้", err)
+	}
+	return webhook, nil
+}
+
+// checkURL ตรวจว่า URL ของ webhook เป็น https (หรือ http ในโหมด development หรือเมื่อ WEBHOOK_ALLOW_HTTP=true)
+func (wc *WebhookController) checkURL(raw string) error {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if target.Host == "" || target.User != nil {
+		return errors.New("URL ต้องมี host และห้ามมีข้อมูลผู้ใช้")
+	}
+	cfg := wc.Config.Get()
+	switch strings.ToLower(target.Scheme) {
+	case "https":
+		return nil
+	case "http":
+		if cfg.Server.Environment == "development" || cfg.Webhook.AllowHTTP {
+			return nil
+		}
+		return errors.New("ต้องใช้ https (เปิด WEBHOOK_ALLOW_HTTP เพื่ออนุญาต http)")
+	default:
+		return errors.New("รองรับเฉพาะ http และ https")
+	}
+}
+
+// joinEvents รวมชนิด event เป็นสตริงคั่นด้วยช่องว่างสำหรับเก็บในฐานข้อมูล (ตัดรายการซ้ำ)
+func joinEvents(events []string) string {
+	var unique []string
+	for _, event := range events {
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return strings.Join(unique, " ")
+}
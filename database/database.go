@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/XSAM/otelsql"
+	"github.com/jmoiron/sqlx"
+)
+
+// Open ฟังก์ชันสำหรับเปิดการเชื่อมต่อฐานข้อมูลหนึ่งครั้งตาม DB_DRIVER (mysql, postgres, sqlite)
+// ตั้งค่า Connection Pool ตามการตั้งค่า และตรวจสอบด้วย PingContext
+// คืนค่า error แทนการหยุดโปรแกรม เพื่อให้ผู้เรียกตัดสินใจเองว่าจะลองใหม่หรือไม่
+func Open(ctx context.Context, cfg *config.DatabaseConfig) (*sqlx.DB, error) {
+	dialect, err := dialectFor(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	dsn, err := dialect.buildDSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// เปิดการเชื่อมต่อผ่าน otelsql เพื่อให้ทุก query ที่ส่ง context มาด้วย
+	// ถูกบันทึกเป็น child span ของ request ที่กำลังทำงานอยู่
+	sqlDB, err := otelsql.Open(dialect.driverName, dsn,
+		otelsql.WithAttributes(dialect.system),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถเปิดการเชื่อมต่อฐานข้อมูลได้: %w", err)
+	}
+	// ใช้ชื่อ driver เดิมกับ sqlx เพื่อให้ Rebind เลือก placeholder ถูกต้อง (? หรือ $1)
+	db := sqlx.NewDb(sqlDB, dialect.driverName)
+
+	// ตั้งค่า Connection Pool เพื่อจัดการการเชื่อมต่อแบบมีประสิทธิภาพ
+	db.SetMaxOpenConns(cfg.MaxOpenConns)       // จำนวนการเชื่อมต่อสูงสุดที่เปิดพร้อมกัน
+	db.SetMaxIdleConns(cfg.MaxIdleConns)       // จำนวนการเชื่อมต่อที่เก็บไว้ในสถานะรอ
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime) // ระยะเวลาที่การเชื่อมต่อหนึ่งจะมีชีวิตอยู่สูงสุด
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime) // ระยะเวลาที่การเชื่อมต่อว่างได้ก่อนถูกปิด
+	if cfg.Driver == DriverSQLite {
+		// SQLite เขียนได้ทีละ connection เดียว จำกัดไว้เพื่อหลีกเลี่ยง "database is locked"
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ไม่สามารถเชื่อมต่อกับฐานข้อมูลได้: %w", err)
+	}
+	return db, nil
+}
+
+// Connect ฟังก์ชันสำหรับเชื่อมต่อฐานข้อมูลพร้อมลองใหม่แบบ exponential backoff
+// ใช้ตอนเริ่มระบบเพื่อไม่ให้ฐานข้อมูลที่ยังไม่พร้อมชั่วคราวทำให้ process หยุดทำงาน
+// จะลองทั้งหมด 1 + cfg.ConnectRetries ครั้ง โดยเวลารอเพิ่มเป็นสองเท่าจนถึง cfg.ConnectMaxBackoff
+func Connect(ctx context.Context, cfg *config.DatabaseConfig) (*sqlx.DB, error) {
+	backoff := cfg.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		db, err := Open(ctx, cfg)
+		if err == nil {
+			log.Println("Database connected successfully")
+			log.Println("ฐานข้อมูลเชื่อมต่อสำเร็จแล้ว")
+			return db, nil
+		}
+		if attempt >= cfg.ConnectRetries {
+			return nil, fmt.Errorf("เชื่อมต่อฐานข้อมูลไม่สำเร็จหลังจากลอง %d ครั้ง: %w", attempt+1, err)
+		}
+
+		log.Printf("⏳ เชื่อมต่อฐานข้อมูลไม่สำเร็จ (ครั้งที่ %d/%d): %v - ลองใหม่ใน %s",
+			attempt+1, cfg.ConnectRetries+1, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > cfg.ConnectMaxBackoff {
+			backoff = cfg.ConnectMaxBackoff
+		}
+	}
+}
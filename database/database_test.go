@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// TestOpenTracesQueries ตรวจว่า query ที่ส่ง context ของ span มาด้วยถูกบันทึกเป็น child span ที่มี db.system
+func TestOpenTracesQueries(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider) // otelsql อ่าน provider ตอน Open
+
+	db, err := Open(context.Background(), &config.DatabaseConfig{Driver: DriverSQLite, DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	exporter.Reset() // ไม่สนใจ span ของ ping ตอนเปิดการเชื่อมต่อ
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	var one int
+	if err := db.GetContext(ctx, &one, "SELECT 1"); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	var query *tracetest.SpanStub
+	for i := range spans {
+		if spans[i].Name == "sql.conn.query" {
+			query = &spans[i]
+		}
+	}
+	if query == nil {
+		t.Fatalf("ไม่พบ span ของ query ใน %d span", len(spans))
+	}
+	if got, want := query.Parent.SpanID(), parent.SpanContext().SpanID(); got != want {
+		t.Errorf("parent ของ span query = %s, ต้องการ %s", got, want)
+	}
+	if got := query.SpanContext.TraceID(); got != parent.SpanContext().TraceID() {
+		t.Errorf("span query อยู่คนละ trace กับ parent (%s)", got)
+	}
+	if !hasAttribute(query, semconv.DBSystemSqlite) {
+		t.Errorf("span query ไม่มี attribute %s: %v", semconv.DBSystemKey, query.Attributes)
+	}
+}
+
+// hasAttribute ตรวจว่า span มี attribute ที่มีค่าตามที่กำหนด
+func hasAttribute(span *tracetest.SpanStub, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes {
+		if attr.Key == want.Key && attr.Value == want.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migrationFiles ไฟล์ migration ของแต่ละ dialect ถูกฝังไว้ในไบนารี
+// โครงสร้าง: migrations/<driver>/<version>_<name>.sql เช่น migrations/sqlite/0001_create_users.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// Migrate รัน migration ที่ยังไม่เคยรันของ driver ปัจจุบันตามลำดับชื่อไฟล์
+// ประวัติการรันถูกเก็บในตาราง schema_migrations เพื่อไม่ให้รันซ้ำ
+// แต่ละไฟล์รันภายใน transaction เดียว (ยกเว้น MySQL ที่ DDL จะ commit เองโดยอัตโนมัติ)
+func Migrate(ctx context.Context, db *sqlx.DB) error {
+	driver := db.DriverName()
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return fmt.Errorf("ไม่พบ migration ของ driver %s: %w", driver, err)
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("ไม่สามารถสร้างตาราง schema_migrations ได้: %w", err)
+	}
+
+	var applied []string
+	if err := db.SelectContext(ctx, &applied, "SELECT version FROM schema_migrations"); err != nil {
+		return fmt.Errorf("ไม่สามารถอ่านประวัติ migration ได้: %w", err)
+	}
+	done := make(map[string]bool, len(applied))
+	for _, version := range applied {
+		done[version] = true
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if done[version] {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, db, version, string(content)); err != nil {
+			return fmt.Errorf("migration %s ล้มเหลว: %w", version, err)
+		}
+		log.Printf("📦 รัน migration %s สำเร็จ", version)
+	}
+	return nil
+}
+
+// applyMigration รันคำสั่ง SQL ทีละคำสั่งในไฟล์ แล้วบันทึก version ลง schema_migrations
+func applyMigration(ctx context.Context, db *sqlx.DB, version, content string) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, statement := range splitStatements(content) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("%w\n%s", err, statement)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("INSERT INTO schema_migrations (version) VALUES (?)"), version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// splitStatements แยกไฟล์ SQL เป็นคำสั่งย่อยด้วย ; ที่ท้ายบรรทัด และตัดบรรทัด comment (--) ออก
+// เพียงพอสำหรับ DDL/DML ทั่วไปใน migration (ไม่รองรับ stored procedure ที่มี ; อยู่ภายใน)
+func splitStatements(content string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
@@ -0,0 +1,15 @@
+-- ตารางบัญชีภายนอก (OIDC/OAuth2) ที่เชื่อมกับผู้ใช้ในระบบ (MySQL)
+-- หนึ่งผู้ใช้เชื่อมได้หลาย provider แต่ subject ของแต่ละ provider เชื่อมได้กับผู้ใช้เดียว
+CREATE TABLE IF NOT EXISTS linked_identities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT uq_linked_identities_provider_subject UNIQUE (provider, subject),
+    CONSTRAINT fk_linked_identities_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_linked_identities_user_id ON linked_identities (user_id);
@@ -0,0 +1,66 @@
+-- ตารางของ OAuth2 authorization server (MySQL)
+-- secret, authorization code และ refresh token เก็บเฉพาะ SHA-256 ไม่เก็บค่าจริง
+
+-- แอปที่ลงทะเบียนไว้ (secret_hash ว่าง = public client เช่น SPA/mobile ที่ต้องใช้ PKCE)
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    client_id VARCHAR(64) UNIQUE NOT NULL,
+    secret_hash VARCHAR(64) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    redirect_uris TEXT NOT NULL,
+    grant_types VARCHAR(255) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    first_party BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- authorization code ที่ยังไม่ถูกแลก (ลบทิ้งทันทีเมื่อถูกใช้)
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code_hash CHAR(64) UNIQUE NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    redirect_uri TEXT NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_oauth_codes_client FOREIGN KEY (client_id) REFERENCES oauth_clients (client_id) ON DELETE CASCADE,
+    CONSTRAINT fk_oauth_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- refresh token (ถูกเพิกถอนเมื่อใช้แลก token ใหม่)
+CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_oauth_refresh_client FOREIGN KEY (client_id) REFERENCES oauth_clients (client_id) ON DELETE CASCADE,
+    CONSTRAINT fk_oauth_refresh_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oauth_refresh_tokens_client_user ON oauth_refresh_tokens (client_id, user_id);
+
+-- scope ที่ผู้ใช้อนุญาตให้แต่ละแอปแล้ว (ไม่ต้องถามซ้ำหากขอ scope เดิมหรือน้อยกว่า)
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT uq_oauth_consents_user_client UNIQUE (user_id, client_id),
+    CONSTRAINT fk_oauth_consents_client FOREIGN KEY (client_id) REFERENCES oauth_clients (client_id) ON DELETE CASCADE,
+    CONSTRAINT fk_oauth_consents_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- JTI ของ access token ที่ถูกเพิกถอนก่อนหมดอายุ (ลบได้เมื่อเลย expires_at)
+CREATE TABLE IF NOT EXISTS oauth_revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
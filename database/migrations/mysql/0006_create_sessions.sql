@@ -0,0 +1,17 @@
+-- ตาราง session ของการเข้าสู่ระบบ (MySQL)
+-- หนึ่งแถวต่อหนึ่ง JWT ที่ออกให้ผู้ใช้ (ผูกด้วย jti) ใช้แสดงรายการอุปกรณ์และเพิกถอน token ก่อนหมดอายุ
+CREATE TABLE IF NOT EXISTS sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    jti VARCHAR(64) UNIQUE NOT NULL,
+    device VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
@@ -0,0 +1,11 @@
+-- ตารางประวัติรหัสผ่าน (MySQL)
+-- เก็บ hash ของรหัสผ่านล่าสุด PASSWORD_HISTORY รายการต่อผู้ใช้ เพื่อห้ามใช้รหัสผ่านเดิมซ้ำ
+CREATE TABLE IF NOT EXISTS password_history (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_password_history_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_history_user_id ON password_history (user_id);
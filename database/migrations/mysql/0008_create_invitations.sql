@@ -0,0 +1,21 @@
+-- ตารางคำเชิญผู้ใช้ (MySQL)
+-- Admin เชิญอีเมลพร้อมกำหนด role ไว้ล่วงหน้า ผู้รับเชิญตั้งชื่อผู้ใช้และรหัสผ่านเองตอนรับคำเชิญ
+-- เก็บเฉพาะ SHA-256 ของ token ซึ่งใช้ได้ครั้งเดียวและหมดอายุตาม INVITE_TTL
+CREATE TABLE IF NOT EXISTS invitations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    role ENUM('user', 'admin') NOT NULL DEFAULT 'user',
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by INT NULL,
+    user_id INT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP NULL DEFAULT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_invitations_invited_by FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE SET NULL,
+    CONSTRAINT fk_invitations_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_invitations_email ON invitations (email);
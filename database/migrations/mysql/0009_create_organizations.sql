@@ -0,0 +1,31 @@
+-- ตารางองค์กร (tenant) และสมาชิก (MySQL)
+-- ผู้ใช้เห็นและจัดการได้เฉพาะผู้ใช้ในองค์กรเดียวกัน role ใน memberships กำหนด Admin ขององค์กร
+CREATE TABLE IF NOT EXISTS organizations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    organization_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role ENUM('user', 'admin') NOT NULL DEFAULT 'user',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_memberships_organization_user (organization_id, user_id),
+    CONSTRAINT fk_memberships_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE,
+    CONSTRAINT fk_memberships_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+-- คำเชิญขององค์กร (NULL = คำเชิญระดับระบบจาก Admin ของระบบ)
+ALTER TABLE invitations ADD COLUMN organization_id INT NULL,
+    ADD CONSTRAINT fk_invitations_organization FOREIGN KEY (organization_id) REFERENCES organizations (id) ON DELETE CASCADE;
+
+-- ผู้ใช้ที่มีอยู่ก่อนรองรับหลายองค์กรเป็นสมาชิกขององค์กร default ด้วย role เดิม
+INSERT INTO organizations (slug, name) VALUES ('default', 'Default');
+INSERT INTO memberships (organization_id, user_id, role)
+SELECT o.id, u.id, u.role FROM organizations o, users u WHERE o.slug = 'default';
@@ -0,0 +1,10 @@
+-- ข้อมูลโปรไฟล์ของผู้ใช้และรูปโปรไฟล์ (MySQL)
+-- metadata เก็บ JSON object ที่ frontend กำหนดเอง, avatar_key คือ key ของรูปหลักใน blob storage
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN metadata TEXT NULL,
+    ADD COLUMN avatar_key VARCHAR(255) NULL;
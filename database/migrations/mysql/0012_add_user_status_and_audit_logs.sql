@@ -0,0 +1,17 @@
+-- สถานะบัญชีผู้ใช้และบันทึกการกระทำของผู้ดูแล (MySQL)
+-- status: active = ใช้งานได้, disabled = ถูกระงับโดย Admin (เข้าสู่ระบบไม่ได้)
+ALTER TABLE users ADD COLUMN status ENUM('active', 'disabled') NOT NULL DEFAULT 'active';
+
+-- audit_logs ไม่มี foreign key เพื่อให้บันทึกยังอยู่หลังลบผู้ใช้หรือองค์กรที่เกี่ยวข้อง
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    organization_id INT NULL,
+    actor_id INT NULL,
+    action VARCHAR(64) NOT NULL,
+    details TEXT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_organization_id ON audit_logs (organization_id, id);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
@@ -0,0 +1,10 @@
+-- การระงับบัญชีพร้อมเหตุผลและเวลาสิ้นสุด (MySQL)
+-- status: active = ใช้งานได้, suspended = ถูกระงับโดย Admin, pending = ยังไม่เปิดใช้งาน
+-- เปลี่ยน disabled เดิมเป็น suspended (ขยาย ENUM ก่อนเพื่อให้ UPDATE ได้ แล้วจึงตัดค่าเดิมออก)
+ALTER TABLE users MODIFY COLUMN status ENUM('active', 'disabled', 'suspended', 'pending') NOT NULL DEFAULT 'active';
+UPDATE users SET status = 'suspended' WHERE status = 'disabled';
+ALTER TABLE users MODIFY COLUMN status ENUM('active', 'suspended', 'pending') NOT NULL DEFAULT 'active';
+
+-- suspended_until: NULL = ระงับจนกว่า Admin จะยกเลิก, มีค่า = ใช้งานได้อีกครั้งเมื่อถึงเวลานี้
+ALTER TABLE users ADD COLUMN suspension_reason VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN suspended_until TIMESTAMP NULL;
@@ -0,0 +1,4 @@
+-- ผู้ดูแลที่เข้าสู่ระบบแทนผู้ใช้ (impersonation) ของ session (MySQL)
+-- NULL = ผู้ใช้เข้าสู่ระบบเอง, มีค่า = Admin ที่ออก token แทน (session ถูกลบเมื่อลบบัญชี Admin นั้น)
+ALTER TABLE sessions ADD COLUMN impersonator_id INT NULL;
+ALTER TABLE sessions ADD CONSTRAINT fk_sessions_impersonator FOREIGN KEY (impersonator_id) REFERENCES users (id) ON DELETE CASCADE;
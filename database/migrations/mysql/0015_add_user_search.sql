@@ -0,0 +1,3 @@
+-- ดัชนี FULLTEXT สำหรับค้นหาผู้ใช้ (MySQL)
+-- ใช้หาผู้สมัครด้วย MATCH ... AGAINST ก่อนจัดอันดับแบบ fuzzy ในแอปพลิเคชัน
+ALTER TABLE users ADD FULLTEXT INDEX ft_users_search (username, email, display_name);
@@ -0,0 +1,34 @@
+-- webhook ที่ Admin ลงทะเบียนและ outbox/บันทึกการส่งของแต่ละ event (MySQL)
+-- events เก็บชนิด event คั่นด้วยช่องว่าง, secret ใช้เซ็น payload (HMAC-SHA256) จึงต้องเก็บค่าจริง
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    events VARCHAR(255) NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- แถวถูกเพิ่มใน transaction เดียวกับการเปลี่ยนแปลงของผู้ใช้ (status = pending) แล้ว worker ส่งและบันทึกผล
+-- event_id เหมือนกันทุกแถวของ event เดียวกัน (รวมการส่งซ้ำ) ให้ปลายทางใช้ตัดรายการซ้ำ
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    webhook_id INT NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status ENUM('pending', 'delivered', 'failed') NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NULL,
+    last_attempt_at TIMESTAMP NULL,
+    response_status INT NULL,
+    last_error VARCHAR(1000) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP NULL,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
@@ -0,0 +1,4 @@
+-- สร้าง admin user เริ่มต้น (password: admin123) - ควรเปลี่ยนรหัสผ่านทันทีหลังติดตั้ง
+INSERT INTO users (username, email, password, role) VALUES
+('admin', 'admin@example.com', '$2a$10$EPDc9uf5wyld3TzdUzmPkeAwwx3.ajT1vLne07aB81ZdPdU8gw.ty', 'admin')
+ON CONFLICT DO NOTHING;
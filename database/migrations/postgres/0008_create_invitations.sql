@@ -0,0 +1,19 @@
+-- ตารางคำเชิญผู้ใช้ (PostgreSQL)
+-- Admin เชิญอีเมลพร้อมกำหนด role ไว้ล่วงหน้า ผู้รับเชิญตั้งชื่อผู้ใช้และรหัสผ่านเองตอนรับคำเชิญ
+-- เก็บเฉพาะ SHA-256 ของ token ซึ่งใช้ได้ครั้งเดียวและหมดอายุตาม INVITE_TTL
+CREATE TABLE IF NOT EXISTS invitations (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    invited_by INTEGER NULL REFERENCES users (id) ON DELETE SET NULL,
+    user_id INTEGER NULL REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invitations_email ON invitations (email);
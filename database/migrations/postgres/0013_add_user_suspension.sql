@@ -0,0 +1,10 @@
+-- การระงับบัญชีพร้อมเหตุผลและเวลาสิ้นสุด (PostgreSQL)
+-- status: active = ใช้งานได้, suspended = ถูกระงับโดย Admin, pending = ยังไม่เปิดใช้งาน
+-- เปลี่ยน disabled เดิมเป็น suspended (users_status_check คือชื่อที่ PostgreSQL ตั้งให้ CHECK ของ 0012)
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+UPDATE users SET status = 'suspended' WHERE status = 'disabled';
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'suspended', 'pending'));
+
+-- suspended_until: NULL = ระงับจนกว่า Admin จะยกเลิก, มีค่า = ใช้งานได้อีกครั้งเมื่อถึงเวลานี้
+ALTER TABLE users ADD COLUMN suspension_reason VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN suspended_until TIMESTAMP NULL;
@@ -0,0 +1,3 @@
+-- ผู้ดูแลที่เข้าสู่ระบบแทนผู้ใช้ (impersonation) ของ session (PostgreSQL)
+-- NULL = ผู้ใช้เข้าสู่ระบบเอง, มีค่า = Admin ที่ออก token แทน (session ถูกลบเมื่อลบบัญชี Admin นั้น)
+ALTER TABLE sessions ADD COLUMN impersonator_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- การค้นหาผู้ใช้ (PostgreSQL)
+-- ไม่สร้างดัชนี: pg_trgm ต้องใช้สิทธิ์ติดตั้ง extension ที่ฐานข้อมูลส่วนใหญ่ไม่มี
+-- แอปพลิเคชันหาผู้สมัครด้วย LIKE ของ bigram/trigram ของคำค้นแทน
@@ -0,0 +1,25 @@
+-- outbox ของ domain event (PostgreSQL)
+-- แถวถูกเพิ่มใน transaction เดียวกับการเปลี่ยนแปลง แล้ว worker ของ event bus ส่งให้ subscriber ในโปรเซสและบันทึกผล
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id SERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processed', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NULL,
+    last_error VARCHAR(1000) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_due ON event_outbox (status, next_attempt_at);
+
+-- idempotency key: subscriber ที่ประมวลผล event สำเร็จแล้ว (บันทึกใน transaction เดียวกับงานของ subscriber)
+-- event ที่ถูกส่งซ้ำจะข้าม subscriber ที่มีแถวอยู่แล้ว
+CREATE TABLE IF NOT EXISTS event_consumptions (
+    subscriber VARCHAR(64) NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (subscriber, event_id)
+);
@@ -0,0 +1,14 @@
+-- ตารางบัญชีภายนอก (OIDC/OAuth2) ที่เชื่อมกับผู้ใช้ในระบบ (SQLite)
+-- หนึ่งผู้ใช้เชื่อมได้หลาย provider แต่ subject ของแต่ละ provider เชื่อมได้กับผู้ใช้เดียว
+CREATE TABLE IF NOT EXISTS linked_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_linked_identities_user_id ON linked_identities (user_id);
@@ -0,0 +1,16 @@
+-- ตาราง session ของการเข้าสู่ระบบ (SQLite)
+-- หนึ่งแถวต่อหนึ่ง JWT ที่ออกให้ผู้ใช้ (ผูกด้วย jti) ใช้แสดงรายการอุปกรณ์และเพิกถอน token ก่อนหมดอายุ
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    jti VARCHAR(64) UNIQUE NOT NULL,
+    device VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
@@ -0,0 +1,10 @@
+-- ตารางประวัติรหัสผ่าน (SQLite)
+-- เก็บ hash ของรหัสผ่านล่าสุด PASSWORD_HISTORY รายการต่อผู้ใช้ เพื่อห้ามใช้รหัสผ่านเดิมซ้ำ
+CREATE TABLE IF NOT EXISTS password_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id ON password_history (user_id);
@@ -0,0 +1,28 @@
+-- ตารางองค์กร (tenant) และสมาชิก (SQLite)
+-- ผู้ใช้เห็นและจัดการได้เฉพาะผู้ใช้ในองค์กรเดียวกัน role ใน memberships กำหนด Admin ขององค์กร
+CREATE TABLE IF NOT EXISTS organizations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS memberships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_memberships_user_id ON memberships (user_id);
+
+-- คำเชิญขององค์กร (NULL = คำเชิญระดับระบบจาก Admin ของระบบ)
+ALTER TABLE invitations ADD COLUMN organization_id INTEGER NULL REFERENCES organizations (id) ON DELETE CASCADE;
+
+-- ผู้ใช้ที่มีอยู่ก่อนรองรับหลายองค์กรเป็นสมาชิกขององค์กร default ด้วย role เดิม
+INSERT INTO organizations (slug, name) VALUES ('default', 'Default');
+INSERT INTO memberships (organization_id, user_id, role)
+SELECT o.id, u.id, u.role FROM organizations o, users u WHERE o.slug = 'default';
@@ -0,0 +1,9 @@
+-- ข้อมูลโปรไฟล์ของผู้ใช้และรูปโปรไฟล์ (SQLite)
+-- metadata เก็บ JSON object ที่ frontend กำหนดเอง, avatar_key คือ key ของรูปหลักใน blob storage
+ALTER TABLE users ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN metadata TEXT NULL;
+ALTER TABLE users ADD COLUMN avatar_key VARCHAR(255) NULL;
@@ -0,0 +1,17 @@
+-- สถานะบัญชีผู้ใช้และบันทึกการกระทำของผู้ดูแล (SQLite)
+-- status: active = ใช้งานได้, disabled = ถูกระงับโดย Admin (เข้าสู่ระบบไม่ได้)
+ALTER TABLE users ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disabled'));
+
+-- audit_logs ไม่มี foreign key เพื่อให้บันทึกยังอยู่หลังลบผู้ใช้หรือองค์กรที่เกี่ยวข้อง
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    organization_id INTEGER NULL,
+    actor_id INTEGER NULL,
+    action VARCHAR(64) NOT NULL,
+    details TEXT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_organization_id ON audit_logs (organization_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
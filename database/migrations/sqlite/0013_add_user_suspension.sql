@@ -0,0 +1,11 @@
+-- การระงับบัญชีพร้อมเหตุผลและเวลาสิ้นสุด (SQLite)
+-- status: active = ใช้งานได้, suspended = ถูกระงับโดย Admin, pending = ยังไม่เปิดใช้งาน
+-- SQLite แก้ CHECK ของคอลัมน์ไม่ได้ จึงสร้างคอลัมน์ใหม่ คัดลอกค่า (disabled เดิมเป็น suspended) แล้วแทนที่คอลัมน์เดิม
+ALTER TABLE users ADD COLUMN status_new VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status_new IN ('active', 'suspended', 'pending'));
+UPDATE users SET status_new = CASE status WHEN 'disabled' THEN 'suspended' ELSE status END;
+ALTER TABLE users DROP COLUMN status;
+ALTER TABLE users RENAME COLUMN status_new TO status;
+
+-- suspended_until: NULL = ระงับจนกว่า Admin จะยกเลิก, มีค่า = ใช้งานได้อีกครั้งเมื่อถึงเวลานี้
+ALTER TABLE users ADD COLUMN suspension_reason VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN suspended_until DATETIME NULL;
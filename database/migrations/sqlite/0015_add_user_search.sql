@@ -0,0 +1,3 @@
+-- การค้นหาผู้ใช้ (SQLite)
+-- ไม่สร้างดัชนี: LIKE '%...%' ใช้ดัชนีแบบ B-tree ไม่ได้
+-- แอปพลิเคชันหาผู้สมัครด้วย LIKE ของ bigram/trigram ของคำค้นแทน
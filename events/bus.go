@@ -0,0 +1,167 @@
+// Package events เป็น event bus ภายในโปรเซสที่ส่ง domain event (models.DomainEvent) จาก outbox ให้ subscriber
+// event ถูกบันทึกลงตาราง event_outbox ใน transaction เดียวกับการเปลี่ยนแปลง (repository.EventRepository.Publish)
+// แล้ว Bus ส่งเบื้องหลังให้ทุก subscriber ที่สมัครรับแบบ at-least-once
+// subscriber แต่ละตัวได้รับ event หนึ่งครั้งต่อการประมวลผลสำเร็จ โดยใช้ event_id เป็น idempotency key (ตาราง event_consumptions)
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// claimDuration เวลาที่จอง event ระหว่างส่งให้ subscriber (worker ที่หยุดกลางคันจะถูกส่งซ้ำหลังเวลานี้)
+const claimDuration = 5 * time.Minute
+
+// Envelope event หนึ่งรายการที่ส่งให้ subscriber
+type Envelope struct {
+	models.OutboxEvent                    // แถวใน outbox (EventID คือ idempotency key)
+	Event              models.DomainEvent // event ที่อ่านกลับเป็นชนิดเดิม เช่น *models.UserRegistered
+}
+
+// Subscriber ผู้รับ event จาก Bus
+// Handle ได้รับ transaction ที่บันทึก idempotency key ของ subscriber ด้วย งานที่เขียนลงฐานข้อมูลผ่าน tx จึงเกิดครั้งเดียวต่อ event
+// งานภายนอก (เช่น ส่งอีเมล) อาจเกิดซ้ำเมื่อ commit ไม่สำเร็จ จึงควรส่ง EventID ต่อให้ปลายทางตัดรายการซ้ำ
+type Subscriber struct {
+	Name   string                                                        // ชื่อที่ไม่ซ้ำและห้ามเปลี่ยน (ส่วนหนึ่งของ idempotency key)
+	Events []string                                                      // ชื่อ event ที่สมัครรับ (ว่าง = ทุก event)
+	Handle func(ctx context.Context, tx *sqlx.Tx, event *Envelope) error // ประมวลผล event (คืนค่า error = ส่งซ้ำภายหลัง)
+}
+
+// handles ตรวจว่า subscriber สมัครรับ event ชื่อนี้หรือไม่
+func (s *Subscriber) handles(name string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, name)
+}
+
+// Bus ส่ง event ที่ถึงเวลาจาก outbox ให้ subscriber ที่ลงทะเบียนไว้ และกำหนดเวลาส่งซ้ำแบบ exponential backoff
+// อ่านการตั้งค่า EVENTS_* ล่าสุดทุกรอบ จึงเปลี่ยนค่าได้โดยไม่ต้อง restart
+type Bus struct {
+	Config *config.Store               // การตั้งค่าระบบ
+	DB     *sqlx.DB                    // การเชื่อมต่อฐานข้อมูล (เปิด transaction ให้ subscriber)
+	Events *repository.EventRepository // การเข้าถึงตาราง event_outbox และ event_consumptions
+
+	subscribers []Subscriber
+}
+
+// NewBus สร้าง Bus ใหม่ที่ยังไม่มี subscriber
+func NewBus(store *config.Store, db *sqlx.DB) *Bus {
+	return &Bus{
+		Config: store,
+		DB:     db,
+		Events: repository.NewEventRepository(db),
+	}
+}
+
+// Subscribe ลงทะเบียน subscriber (เรียกก่อน Run)
+func (b *Bus) Subscribe(subscriber Subscriber) {
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// Run ส่ง event ทุก EVENTS_POLL_INTERVAL จนกว่า ctx จะถูกยกเลิก (เรียกใน goroutine แยก)
+func (b *Bus) Run(ctx context.Context) {
+	for {
+		if err := b.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("⚠️  ไม่สามารถส่ง event ให้ subscriber ได้: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.Config.Get().Events.PollInterval):
+		}
+	}
+}
+
+// Dispatch ส่ง event ที่ถึงเวลาหนึ่งรอบ (ไม่เกิน EVENTS_BATCH_SIZE รายการ) ตามลำดับที่เกิด
+func (b *Bus) Dispatch(ctx context.Context) error {
+	cfg := b.Config.Get().Events
+	now := time.Now().UTC()
+	pending, err := b.Events.Due(ctx, now, cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range pending {
+		// จองก่อนส่ง instance อื่นที่อ่านรายการเดียวกันจะจองไม่ได้และข้ามไป
+		claimed, err := b.Events.Claim(ctx, pending[i].ID, now, now.Add(claimDuration))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		processErr := b.process(ctx, &pending[i])
+		done := time.Now().UTC()
+		var next *time.Time
+		if attempt := pending[i].Attempts + 1; processErr != nil && attempt < cfg.MaxAttempts {
+			at := done.Add(utils.Backoff(cfg.Backoff, cfg.BackoffMax, attempt))
+			next = &at
+		}
+		if processErr != nil {
+			log.Printf("⚠️  event %s (%s) ล้มเหลว: %v", pending[i].EventID, pending[i].Name, processErr)
+		}
+		if err := b.Events.RecordAttempt(ctx, pending[i].ID, done, processErr, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// process ส่ง event ให้ทุก subscriber ที่สมัครรับ และคืนค่าข้อผิดพลาดของ subscriber ที่ล้มเหลว
+// subscriber ที่สำเร็จแล้วในรอบก่อนจะถูกข้าม (ตาม idempotency key)
+func (b *Bus) process(ctx context.Context, outbox *models.OutboxEvent) error {
+	event, err := models.DecodeEvent(outbox.Name, []byte(outbox.Payload))
+	if err != nil {
+		return err
+	}
+	envelope := &Envelope{OutboxEvent: *outbox, Event: event}
+
+	var errs []error
+	for i := range b.subscribers {
+		subscriber := &b.subscribers[i]
+		if !subscriber.handles(outbox.Name) {
+			continue
+		}
+		if err := b.deliver(ctx, subscriber, envelope); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscriber.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver ส่ง event ให้ subscriber หนึ่งตัวใน transaction ที่บันทึก idempotency key ด้วย
+func (b *Bus) deliver(ctx context.Context, subscriber *Subscriber, envelope *Envelope) (err error) {
+	tx, err := b.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	consumed, err := b.Events.Consumed(ctx, tx, subscriber.Name, envelope.EventID)
+	if err != nil || consumed {
+		return err
+	}
+
+	// subscriber ที่ panic ไม่ควรหยุด worker ทั้งตัว ถือเป็นการประมวลผลไม่สำเร็จและส่งซ้ำภายหลัง
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if err := subscriber.Handle(ctx, tx, envelope); err != nil {
+		return err
+	}
+	if err := b.Events.MarkConsumed(ctx, tx, subscriber.Name, envelope.EventID, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
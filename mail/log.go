@@ -0,0 +1,21 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer เขียนอีเมลลง log ของเซิร์ฟเวอร์แทนการส่งจริง (MAIL_DRIVER=log)
+// ใช้ระหว่างพัฒนาเท่านั้น เพราะเนื้อหาอีเมล (เช่น ลิงก์คำเชิญ) จะอยู่ใน log
+type LogMailer struct {
+	From string // ผู้ส่ง (แสดงใน log เท่านั้น)
+}
+
+// Send เขียนอีเมลลง log
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	log.Printf("📧 [MAIL_DRIVER=log] from=%s to=%s subject=%q\n%s", m.From, msg.To, msg.Subject, msg.Body)
+	return nil
+}
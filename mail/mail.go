@@ -0,0 +1,48 @@
+// Package mail ส่งอีเมลของระบบ (เช่น คำเชิญผู้ใช้) ผ่าน backend ที่เลือกด้วย MAIL_DRIVER
+// ผู้เรียกสร้าง Mailer ใหม่จากการตั้งค่าล่าสุดทุกครั้ง จึงเปลี่ยน backend ได้โดยไม่ต้อง restart
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// Message อีเมลหนึ่งฉบับแบบข้อความธรรมดา (text/plain, UTF-8)
+type Message struct {
+	To      string // ที่อยู่ผู้รับหนึ่งคน
+	Subject string // หัวเรื่อง
+	Body    string // เนื้อหา
+}
+
+// Mailer ส่งอีเมลหนึ่งฉบับ
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New สร้าง Mailer ตาม MAIL_DRIVER
+func New(cfg *config.MailConfig) Mailer {
+	if cfg.Driver == "smtp" {
+		return &SMTPMailer{
+			From:     cfg.From,
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		}
+	}
+	return &LogMailer{From: cfg.From}
+}
+
+// validate ปฏิเสธข้อความที่มีการขึ้นบรรทัดใหม่ใน header (ป้องกันการแทรก header เช่น Bcc)
+func (m Message) validate() error {
+	if m.To == "" {
+		return errors.New("ไม่ได้ระบุผู้รับ")
+	}
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return errors.New("ผู้รับหรือหัวเรื่องมีการขึ้นบรรทัดใหม่")
+	}
+	return nil
+}
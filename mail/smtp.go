@@ -0,0 +1,125 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// smtpTimeout เวลาสูงสุดของการส่งอีเมลหนึ่งฉบับ เมื่อ context ไม่ได้กำหนด deadline ไว้
+const smtpTimeout = 30 * time.Second
+
+// SMTPMailer ส่งอีเมลผ่าน SMTP server (MAIL_DRIVER=smtp)
+// พอร์ต 465 ใช้ TLS ตั้งแต่เริ่มเชื่อมต่อ พอร์ตอื่นใช้ STARTTLS เมื่อ server รองรับ
+type SMTPMailer struct {
+	From     string // ผู้ส่ง เช่น "GoTemplate <no-reply@example.com>"
+	Host     string // ที่อยู่ของ SMTP server
+	Port     string // พอร์ตของ SMTP server
+	Username string // ชื่อผู้ใช้สำหรับ SMTP AUTH (ว่าง = ไม่ยืนยันตัวตน)
+	Password string // รหัสผ่านสำหรับ SMTP AUTH
+}
+
+// Send ส่งอีเมลหนึ่งฉบับ
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("MAIL_FROM ไม่ถูกต้อง: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("ที่อยู่ผู้รับไม่ถูกต้อง: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, smtpTimeout)
+		defer cancel()
+	}
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.Username != "" {
+		// PlainAuth ปฏิเสธการส่งรหัสผ่านผ่านการเชื่อมต่อที่ไม่เข้ารหัส (ยกเว้น localhost)
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(m.compose(from, to, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial เชื่อมต่อ SMTP server และเปิด TLS ตามพอร์ต
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.Host, m.Port)
+	tlsConfig := &tls.Config{ServerName: m.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if m.Port == "465" {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && m.Port != "465" {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// compose สร้างข้อความอีเมลพร้อม header (หัวเรื่องภาษาไทยเข้ารหัสแบบ RFC 2047)
+func (m *SMTPMailer) compose(from, to *mail.Address, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
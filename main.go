@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/database"
 	_ "github.com/Sing254463/GoTemplate/Backend/docs"
+	"github.com/Sing254463/GoTemplate/Backend/events"
 	"github.com/Sing254463/GoTemplate/Backend/middleware"
+	"github.com/Sing254463/GoTemplate/Backend/password"
 	"github.com/Sing254463/GoTemplate/Backend/routes"
+	"github.com/Sing254463/GoTemplate/Backend/server"
+	"github.com/Sing254463/GoTemplate/Backend/telemetry"
+	"github.com/Sing254463/GoTemplate/Backend/webhook"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
@@ -51,25 +59,112 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
-// @description Enter: Bearer {token}
-// การตั้งค่าความปลอดภัยสำหรับ JWT Token
+// @description Enter: Bearer {token} หรือ ApiKey {key}
+// การตั้งค่าความปลอดภัยสำหรับ JWT Token (และ API key แบบ Authorization header)
+
+// @securityDefinitions.apikey APIKeyHeader
+// @in header
+// @name X-API-Key
+// @description Enter: {key} (gtk_...)
+// การตั้งค่าความปลอดภัยสำหรับ API key ของ client แบบ service-to-service
 
 // ============================================
 // ฟังก์ชันหลักของแอปพลิเคชัน
 // ============================================
 func main() {
 	// ============================================
-	// 1. โหลดการตั้งค่าจากไฟล์ .env
+	// 0. คำสั่งเสริม (Subcommands)
+	// ============================================
+	// `config print` แสดงการตั้งค่าที่มีผลจริงหลังรวมทุกแหล่ง (ซ่อนค่าความลับ)
+	// ตัวอย่าง: go run . config print --config config.yaml --port=9090
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		cfg, err := config.Load(os.Args[3:])
+		if err != nil {
+			log.Fatal("การตั้งค่าไม่ถูกต้อง/Invalid configuration: ", err)
+		}
+		if err := cfg.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// `password benchmark` วัดเวลาการ hash รหัสผ่านด้วยการตั้งค่าปัจจุบันและพารามิเตอร์ที่แนะนำ
+	// ตัวอย่าง: go run . password benchmark --password-algorithm=argon2id --password-argon2-memory=47104
+	if len(os.Args) > 2 && os.Args[1] == "password" && os.Args[2] == "benchmark" {
+		cfg, err := config.Load(os.Args[3:])
+		if err != nil {
+			log.Fatal("การตั้งค่าไม่ถูกต้อง/Invalid configuration: ", err)
+		}
+		if err := password.Benchmark(os.Stdout, cfg.Password, 5); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// `migrate` รัน migration ของ DB_DRIVER ปัจจุบันแล้วจบการทำงาน
+	// ตัวอย่าง: go run . migrate --db-driver=sqlite --db-name=dev.db
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		cfg, err := config.Load(os.Args[2:])
+		if err != nil {
+			log.Fatal("การตั้งค่าไม่ถูกต้อง/Invalid configuration: ", err)
+		}
+		db, err := database.Connect(context.Background(), cfg.Database)
+		if err != nil {
+			log.Fatal("ไม่สามารถเชื่อมต่อกับฐานข้อมูล/Failed to connect to database: ", err)
+		}
+		defer db.Close()
+		if err := database.Migrate(context.Background(), db); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("✅ รัน migration สำเร็จ")
+		return
+	}
+
+	// ============================================
+	// 1. โหลดการตั้งค่า
 	// ============================================
 	// cfg := config.LoadConfig() ทำหน้าที่:
-	// - อ่านค่าตัวแปรจากไฟล์ .env (DB_HOST, DB_PORT, JWT_SECRET ฯลฯ)
-	// - สร้างการเชื่อมต่อกับฐานข้อมูล MySQL
-	// - ตั้งค่า JWT และ Server configuration
-	// - ตรวจสอบการเชื่อมต่อฐานข้อมูลและแสดงผลลัพธ์
+	// - อ่านค่าตามลำดับ: ค่า default → ไฟล์ YAML/TOML → .env → environment → CLI flags
+	// - ตรวจสอบความถูกต้อง (production จะไม่ยอมรับ JWT_SECRET ค่าเริ่มต้นหรือสั้นเกินไป)
+	// การเชื่อมต่อฐานข้อมูลแยกออกไปอยู่ในขั้นตอนที่ 1.2
 	fmt.Println("🔧 กำลังโหลดการตั้งค่าระบบ...")
 	cfg := config.LoadConfig()
+	// store เก็บการตั้งค่าที่ reload ได้ขณะรัน (SIGHUP หรือเมื่อไฟล์เปลี่ยน)
+	// ส่วนที่ต้องอ่านค่าล่าสุดเสมอ (controllers, JWT middleware) ใช้ store.Get()
+	store := config.NewStore(cfg, os.Args[1:])
 	fmt.Println("✅ โหลดการตั้งค่าสำเร็จ")
 
+	// ============================================
+	// 1.1 เริ่มต้น OpenTelemetry tracing
+	// ============================================
+	// สร้าง TracerProvider ตาม TRACING_EXPORTER (otlp, stdout, none)
+	// และลงทะเบียน W3C traceparent propagator เพื่อต่อ trace ข้ามบริการ
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.Tracing, cfg.App)
+	if err != nil {
+		log.Fatal("ไม่สามารถเริ่มต้น tracing ได้/Failed to set up tracing:", err)
+	}
+	defer shutdownTracing(context.Background())
+	fmt.Printf("✅ เริ่มต้น Tracing สำเร็จ (exporter: %s)\n", cfg.Tracing.Exporter)
+
+	// ============================================
+	// 1.2 เชื่อมต่อฐานข้อมูล
+	// ============================================
+	// database.Connect จะลองเชื่อมต่อใหม่แบบ exponential backoff
+	// (DB_CONNECT_RETRIES, DB_CONNECT_BACKOFF) เพื่อรอฐานข้อมูลที่ยังไม่พร้อมชั่วคราว
+	fmt.Println("💾 กำลังเชื่อมต่อฐานข้อมูล...")
+	db, err := database.Connect(context.Background(), cfg.Database)
+	if err != nil {
+		log.Fatal("ไม่สามารถเชื่อมต่อกับฐานข้อมูล/Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	// รัน migration ที่ยังไม่เคยรัน (ปิดได้ด้วย DB_AUTO_MIGRATE=false)
+	if cfg.Database.AutoMigrate {
+		if err := database.Migrate(context.Background(), db); err != nil {
+			log.Fatal("ไม่สามารถรัน migration ได้/Failed to run migrations: ", err)
+		}
+	}
+
 	// ============================================
 	// 2. สร้างแอปพลิเคชัน Fiber
 	// ============================================
@@ -114,41 +209,44 @@ func main() {
 	app.Use(recover.New())
 	fmt.Println("   ✅ ติดตั้ง Recover Middleware (กู้คืนจาก panic)")
 
-	// 3.2 Logger Middleware - บันทึกข้อมูลการร้องขอ
+	// 3.2 Tracing Middleware - สร้าง span ให้กับทุก request
+	// ต่อ trace จาก header traceparent และส่ง context ให้ query ฐานข้อมูลเป็น child span
+	app.Use(middleware.Tracing())
+	fmt.Println("   ✅ ติดตั้ง Tracing Middleware (OpenTelemetry)")
+
+	// 3.3 Logger Middleware - บันทึกข้อมูลการร้องขอ
 	// จะบันทึกข้อมูลการร้องขอทุกครั้ง เช่น:
 	// - เวลาที่ใช้ในการประมวลผล
 	// - รหัสสถานะของการตอบสนอง (200, 404, 500 ฯลฯ)
 	// - URL ที่ร้องขอ
 	// - HTTP method (GET, POST, PUT, DELETE)
+	// ระดับ log (LOG_LEVEL) เปลี่ยนได้ขณะรันผ่านการ reload การตั้งค่า
+	middleware.SetLogLevel(cfg.Server.LogLevel)
+	store.OnChange(func(old, new *config.Config) {
+		if old.Server.LogLevel != new.Server.LogLevel {
+			middleware.SetLogLevel(new.Server.LogLevel)
+			log.Printf("📝 เปลี่ยนระดับ log เป็น %s", new.Server.LogLevel)
+		}
+	})
 	app.Use(middleware.Logger())
 	fmt.Println("   ✅ ติดตั้ง Logger Middleware (บันทึกข้อมูลการร้องขอ)")
 
-	// 3.3 CORS Middleware - จัดการ Cross-Origin Resource Sharing
+	// 3.4 CORS Middleware - จัดการ Cross-Origin Resource Sharing
 	// อนุญาตให้เว็บไซต์จากโดเมนอื่นสามารถเรียกใช้ API ได้
 	// เช่น หากมี Frontend ที่รันบนพอร์ต 3000 ต้องการเรียก API บนพอร์ต 8080
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*", // อนุญาตให้ทุกโดเมนเข้าถึง (ใช้สำหรับการพัฒนา)
-		// ในการใช้งานจริงควรระบุโดเมนที่แน่นอน เช่น "https://mywebsite.com"
-
-		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		// HTTP methods ที่อนุญาตให้ใช้:
-		// - GET: ดึงข้อมูล
-		// - POST: สร้างข้อมูลใหม่
-		// - PUT: อัปเดตข้อมูล
-		// - DELETE: ลบข้อมูล
-		// - PATCH: อัปเดตข้อมูลบางส่วน
-		// - OPTIONS: ตรวจสอบสิทธิ์ CORS
-		// - HEAD: ดึงข้อมูล header เท่านั้น
-
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		// Headers ที่อนุญาตให้ส่งมาด้วย:
-		// - Origin: ที่มาของการร้องขอ
-		// - Content-Type: ประเภทข้อมูลที่ส่ง (เช่น application/json)
-		// - Accept: ประเภทข้อมูลที่ต้องการรับ
-		// - Authorization: JWT Token สำหรับการยืนยันตัวตน
-	}))
+	// นโยบายแยกกันระหว่าง /api/v1 (CORS_*) และ /swagger (CORS_SWAGGER_ALLOW_ORIGINS)
+	// และเปลี่ยนได้ขณะรันผ่านการ reload การตั้งค่า
+	// ในการใช้งานจริงต้องระบุโดเมนที่แน่นอน เช่น CORS_ALLOW_ORIGINS=https://mywebsite.com,https://*.mywebsite.com
+	app.Use("/api/v1", middleware.CORS(store, middleware.APICORSPolicy))
+	app.Use("/swagger", middleware.CORS(store, middleware.SwaggerCORSPolicy))
 	fmt.Println("   ✅ ติดตั้ง CORS Middleware (จัดการ Cross-Origin)")
 
+	// 3.5 Security Headers Middleware - ส่ง header ป้องกันการโจมตีฝั่ง browser
+	// HSTS (เฉพาะ HTTPS), X-Content-Type-Options, X-Frame-Options, Referrer-Policy และ CSP
+	// /swagger ใช้ CSP แยกที่ยังให้ Swagger UI ทำงานได้ (ปรับได้ด้วย SECURITY_*)
+	app.Use(middleware.SecurityHeaders(store))
+	fmt.Println("   ✅ ติดตั้ง Security Headers Middleware (HSTS, CSP, X-Frame-Options)")
+
 	// ============================================
 	// 4. ตั้งค่าเส้นทาง API (Routes)
 	// ============================================
@@ -160,9 +258,39 @@ func main() {
 	// - /api/v1/users/* (GET/DELETE) - จัดการผู้ใช้ (ต้องเป็น Admin)
 	// - /swagger/* - เอกสาร API
 	fmt.Println("🛣️  กำลังตั้งค่าเส้นทาง API...")
-	routes.SetupRoutes(app, cfg)
+	routes.SetupRoutes(app, store, db)
 	fmt.Println("✅ ตั้งค่าเส้นทาง API สำเร็จ")
 
+	// ============================================
+	// 4.1 เปิดการโหลดการตั้งค่าใหม่ขณะรัน (Hot Reload)
+	// ============================================
+	// โหลดการตั้งค่าใหม่เมื่อได้รับ SIGHUP หรือเมื่อไฟล์ .env / ไฟล์ YAML/TOML เปลี่ยน
+	// ค่าใหม่ถูกตรวจสอบก่อนสลับเข้าใช้งาน หากไม่ถูกต้องจะใช้ค่าเดิมต่อ
+	// ค่าที่ต้อง restart (PORT, ENVIRONMENT, ฐานข้อมูล, tracing) จะแสดงคำเตือนแทน
+	if err := store.Watch(context.Background()); err != nil {
+		log.Printf("⚠️  ไม่สามารถเปิดการ reload การตั้งค่าอัตโนมัติได้: %v", err)
+	} else {
+		fmt.Println("✅ เปิดการ reload การตั้งค่าอัตโนมัติ (SIGHUP / แก้ไขไฟล์)")
+	}
+
+	// ============================================
+	// 4.2 เริ่ม event bus ภายใน
+	// ============================================
+	// อ่าน domain event จาก outbox (event_outbox) ทุก EVENTS_POLL_INTERVAL แล้วส่งให้ subscriber ที่ลงทะเบียนไว้แบบ at-least-once
+	// subscriber ของ webhook สร้างรายการส่งใน webhook_deliveries ให้ทุก webhook ที่สมัครรับ event
+	bus := events.NewBus(store, db)
+	bus.Subscribe(webhook.NewSubscriber(db))
+	go bus.Run(context.Background())
+	fmt.Println("✅ เริ่ม event bus")
+
+	// ============================================
+	// 4.3 เริ่ม worker ส่ง webhook
+	// ============================================
+	// อ่านรายการส่งจาก outbox ของ webhook (webhook_deliveries) ทุก WEBHOOK_POLL_INTERVAL แล้วส่งไปยังปลายทางที่ลงทะเบียนไว้
+	// ส่งไม่สำเร็จจะส่งซ้ำแบบ exponential backoff สูงสุด WEBHOOK_MAX_ATTEMPTS ครั้ง (ปิดด้วย WEBHOOK_ENABLED=false)
+	go webhook.NewDispatcher(store, db).Run(context.Background())
+	fmt.Println("✅ เริ่ม worker ส่ง webhook")
+
 	// ============================================
 	// 5. เริ่มต้นเซิร์ฟเวอร์
 	// ============================================
@@ -177,25 +305,29 @@ func main() {
 	log.Printf("📱 App Name: %s", cfg.App.Name)
 	log.Printf("🔢 Version: %s", cfg.App.Version)
 	log.Printf("📝 Description: %s", cfg.App.Description)
+	// baseURL ใช้ https เมื่อกำหนด TLS_CERT_FILE/TLS_KEY_FILE
+	baseURL := server.Scheme(cfg) + "://localhost:" + cfg.Server.Port
 	log.Printf("🚀 Server starting on port %s", cfg.Server.Port)
-	log.Printf("🌐 API Base URL: http://localhost:%s/api/v1", cfg.Server.Port)
-	log.Printf("📚 Swagger docs available at: http://localhost:%s/swagger/", cfg.Server.Port)
-	log.Printf("💾 Database: %s@%s:%s/%s", cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+	log.Printf("🌐 API Base URL: %s/api/v1", baseURL)
+	log.Printf("📚 Swagger docs available at: %s/swagger/", baseURL)
+	log.Printf("💾 Database: %s %s@%s:%s/%s", cfg.Database.Driver, cfg.Database.User, cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
 	log.Printf("🔐 JWT Expire: %s", cfg.JWT.Expire)
+	log.Printf("📝 Log Level: %s", cfg.Server.LogLevel)
+	log.Printf("🌐 CORS Origins: %s", strings.Join(cfg.CORS.AllowOrigins, ", "))
 	log.Printf("🏗️  Environment: %s", cfg.Server.Environment)
 
 	fmt.Printf("\n🔗 เส้นทาง API ที่สำคัญ:")
-	fmt.Printf("   Health Check: http://localhost:%s/api/v1/health\n", cfg.Server.Port)
-	fmt.Printf("   Version: http://localhost:%s/api/v1/version\n", cfg.Server.Port)
-	fmt.Printf("   Swagger UI: http://localhost:%s/swagger/\n", cfg.Server.Port)
+	fmt.Printf("   Health Check: %s/api/v1/health\n", baseURL)
+	fmt.Printf("   Version: %s/api/v1/version\n", baseURL)
+	fmt.Printf("   Swagger UI: %s/swagger/\n", baseURL)
 
 	fmt.Println("\n⚡ เซิร์ฟเวอร์พร้อมใช้งาน! กด Ctrl+C เพื่อหยุด")
 	fmt.Println("=====================================")
 
 	// แสดงข้อความเริ่มต้น
 	fmt.Println("\n✅ เซิร์ฟเวอร์เริ่มทำงานแล้ว!")
-	fmt.Printf("📱 เปิดเบราว์เซอร์ไปที่: http://localhost:" + cfg.Server.Port + "/api/v1/health\n")
-	fmt.Printf("📚 Swagger: http://localhost:" + cfg.Server.Port + "/swagger/\n")
+	fmt.Printf("📱 เปิดเบราว์เซอร์ไปที่: " + baseURL + "/api/v1/health\n")
+	fmt.Printf("📚 Swagger: " + baseURL + "/swagger/\n")
 
 	// ตรวจสอบว่าเป็น development mode หรือไม่
 	if cfg.Server.Environment == "development" {
@@ -203,12 +335,12 @@ func main() {
 		fmt.Println("\n🔥 Development Mode: เซิร์ฟเวอร์จะรันต่อไป...")
 		fmt.Println("💡 กด Ctrl+C เพื่อหยุดเซิร์ฟเวอร์")
 
-		// รันเซิร์ฟเวอร์และรอให้มันทำงานตลอด
-		log.Fatal(app.Listen(":" + cfg.Server.Port))
+		// รันเซิร์ฟเวอร์และรอให้มันทำงานตลอด (HTTPS เมื่อกำหนด TLS_CERT_FILE/TLS_KEY_FILE)
+		log.Fatal(server.Listen(app, cfg))
 	} else {
 		// Production mode: ให้ผู้ใช้กด Enter เพื่อปิด
 		go func() {
-			if err := app.Listen(":" + cfg.Server.Port); err != nil {
+			if err := server.Listen(app, cfg); err != nil {
 				log.Printf("❌ เกิดข้อผิดพลาดในการเริ่มเซิร์ฟเวอร์: %v", err)
 				fmt.Println("\n🔴 เซิร์ฟเวอร์หยุดทำงาน!")
 				fmt.Println("กด Enter เพื่อปิดโปรแกรม...")
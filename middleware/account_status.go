@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxStatusCacheEntries จำนวนผู้ใช้สูงสุดที่ AccountStatusCache จำไว้ (เกินแล้วล้างรายการที่หมดอายุ หรือล้างทั้งหมด)
+const maxStatusCacheEntries = 10000
+
+// statusCacheEntry สถานะบัญชีที่อ่านไว้ พร้อมเวลาที่อ่าน
+type statusCacheEntry struct {
+	status   models.AccountStatus
+	loadedAt time.Time
+}
+
+// AccountStatusCache จำสถานะบัญชีของผู้ใช้ไว้ในหน่วยความจำเป็นเวลา JWT_STATUS_CACHE_TTL
+// เพื่อให้ JWTMiddleware และ APIKeyMiddleware ปฏิเสธบัญชีที่ถูกระงับหลังออก token ได้โดยไม่ต้องอ่านฐานข้อมูลทุก request
+// การระงับผ่าน API เพิกถอน session ด้วย token ของผู้ใช้เองจึงใช้ไม่ได้ทันที ส่วนช่วง TTL มีผลกับ token ของแอป (OAuth2)
+// และการเปลี่ยนสถานะจากที่อื่น (เช่น instance อื่นหรือแก้ในฐานข้อมูลโดยตรง)
+type AccountStatusCache struct {
+	store   *config.Store              // อ่าน JWT_STATUS_CACHE_TTL ทุกครั้งเพื่อให้ reload มีผลทันที
+	users   *repository.UserRepository // อ่านสถานะเมื่อไม่มีใน cache หรือหมดอายุ
+	mu      sync.Mutex
+	entries map[int]statusCacheEntry
+}
+
+// NewAccountStatusCache สร้าง AccountStatusCache ใหม่
+func NewAccountStatusCache(store *config.Store, users *repository.UserRepository) *AccountStatusCache {
+	return &AccountStatusCache{store: store, users: users, entries: map[int]statusCacheEntry{}}
+}
+
+// Get คืนค่าสถานะบัญชีของผู้ใช้ (จาก cache ถ้ายังไม่หมดอายุ) คืนค่า repository.ErrNotFound เมื่อผู้ใช้ถูกลบแล้ว
+func (sc *AccountStatusCache) Get(ctx context.Context, userID int) (*models.AccountStatus, error) {
+	ttl := sc.store.Get().JWT.StatusCacheTTL
+	now := time.Now()
+
+	sc.mu.Lock()
+	entry, found := sc.entries[userID]
+	sc.mu.Unlock()
+	if found && now.Sub(entry.loadedAt) < ttl {
+		return &entry.status, nil
+	}
+
+	status, err := sc.users.AccountStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		sc.mu.Lock()
+		if len(sc.entries) >= maxStatusCacheEntries {
+			sc.prune(now, ttl)
+		}
+		sc.entries[userID] = statusCacheEntry{status: *status, loadedAt: now}
+		sc.mu.Unlock()
+	}
+	return status, nil
+}
+
+// prune ลบรายการที่หมดอายุ และล้างทั้งหมดหากยังเต็ม (ผู้เรียกถือ mu อยู่)
+func (sc *AccountStatusCache) prune(now time.Time, ttl time.Duration) {
+	for id, entry := range sc.entries {
+		if now.Sub(entry.loadedAt) >= ttl {
+			delete(sc.entries, id)
+		}
+	}
+	if len(sc.entries) >= maxStatusCacheEntries {
+		sc.entries = map[int]statusCacheEntry{}
+	}
+}
+
+// checkAccountStatus ตรวจว่าบัญชีของผู้ใช้ยังใช้งานได้
+// คืนค่า false เมื่อบัญชีถูกลบ ถูกระงับ หรือยังไม่เปิดใช้งาน (ส่ง response ข้อผิดพลาดไปแล้ว)
+func checkAccountStatus(c *fiber.Ctx, statuses *AccountStatusCache, userID int) (bool, error) {
+	account, err := statuses.Get(c.UserContext(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, utils.ErrorResponse(c, fiber.StatusUnauthorized, "ไม่พบบัญชีของ token นี้", nil)
+	}
+	if err != nil {
+		return false, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบสถานะบัญชีได้", err)
+	}
+	if status := account.Effective(time.Now()); status != models.UserStatusActive {
+		return false, utils.AccountStatusResponse(c, status, account.SuspendedUntil)
+	}
+	return true, nil
+}
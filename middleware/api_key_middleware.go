@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HeaderAPIKey header สำหรับส่ง API key
+const HeaderAPIKey = "X-API-Key"
+
+// lastUsedInterval ระยะเวลาขั้นต่ำระหว่างการอัปเดต last_used_at
+// เพื่อไม่ให้ทุก request ต้องเขียนฐานข้อมูล (batch job อาจเรียกหลายพันครั้งต่อนาที)
+const lastUsedInterval = time.Minute
+
+// apiKeyFromRequest ดึง API key จาก X-API-Key หรือ Authorization: ApiKey <key>
+func apiKeyFromRequest(c *fiber.Ctx) (string, bool) {
+	if key := c.Get(HeaderAPIKey); key != "" {
+		return key, true
+	}
+	scheme, key, found := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if found && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(key), true
+	}
+	return "", false
+}
+
+// APIKeyMiddleware ฟังก์ชันสร้าง middleware สำหรับตรวจสอบ API key
+// รับ key จาก header X-API-Key หรือ Authorization: ApiKey <key>
+// เก็บ user_id, username, role ของเจ้าของ key ใน c.Locals เหมือน JWTMiddleware
+// พร้อม api_key_id และ scopes สำหรับ RequireScope
+// key ของเจ้าของที่ถูกระงับหรือยังไม่เปิดใช้งานถูกปฏิเสธ (ตรวจกับ statuses)
+func APIKeyMiddleware(keys *repository.APIKeyRepository, statuses *AccountStatusCache) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// สร้าง span แยกสำหรับขั้นตอนตรวจสอบ key (ปิดก่อนส่งต่อไปยัง handler ถัดไป)
+		span := startSpan(c, "APIKeyMiddleware")
+		ok, err := checkAPIKey(c, keys, statuses)
+		if err != nil {
+			span.RecordError(err)
+		}
+		if userID, found := c.Locals("user_id").(int); ok && found {
+			span.SetAttributes(attribute.Int("user_id", userID))
+		}
+		span.End()
+
+		if !ok {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// checkAPIKey ตรวจสอบ key ของ request และเก็บข้อมูลเจ้าของใน c.Locals
+// คืนค่า false เมื่อ key ใช้ไม่ได้ (ส่ง response ข้อผิดพลาดไปแล้ว)
+func checkAPIKey(c *fiber.Ctx, keys *repository.APIKeyRepository, statuses *AccountStatusCache) (bool, error) {
+	raw, found := apiKeyFromRequest(c)
+	if !found || raw == "" {
+		return false, utils.ErrorResponse(c, fiber.StatusUnauthorized, "ไม่พบ API key", nil)
+	}
+
+	prefix, valid := utils.ParseAPIKey(raw)
+	if !valid {
+		return false, utils.ErrorResponse(c, fiber.StatusUnauthorized, "รูปแบบ API key ไม่ถูกต้อง", nil)
+	}
+
+	key, owner, err := keys.GetByPrefix(c.UserContext(), prefix)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !utils.CheckAPIKey(key.KeyHash, raw)) {
+		return false, utils.ErrorResponse(c, fiber.StatusUnauthorized, "API key ไม่ถูกต้อง", nil)
+	}
+	if err != nil {
+		return false, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบ API key ได้", err)
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return false, utils.ErrorResponse(c, fiber.StatusUnauthorized, "API key ถูกเพิกถอนหรือหมดอายุแล้ว", nil)
+	}
+	if ok, err := checkAccountStatus(c, statuses, key.UserID); !ok {
+		return false, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := keys.TouchLastUsed(c.UserContext(), key.ID, now); err != nil {
+			log.Printf("⚠️  ไม่สามารถอัปเดต last_used_at ของ API key %d: %v", key.ID, err)
+		}
+	}
+
+	// เก็บข้อมูลเดียวกับ JWTMiddleware เพื่อให้ handler และ AdminMiddleware ทำงานได้เหมือนเดิม
+	// role มาจากข้อมูลปัจจุบันของเจ้าของ key (ถ้าเจ้าของถูกลดสิทธิ์ key ก็ถูกลดสิทธิ์ตาม)
+	c.Locals("user_id", key.UserID)
+	c.Locals("username", owner.Username)
+	c.Locals("role", owner.Role)
+	c.Locals("api_key_id", key.ID)
+	c.Locals("scopes", key.ScopeList())
+	return true, nil
+}
+
+// Authenticate เลือกวิธียืนยันตัวตนตาม header ที่ส่งมา:
+// ใช้ apiKey เมื่อมี X-API-Key หรือ Authorization: ApiKey และใช้ jwt ในกรณีอื่น
+func Authenticate(jwt, apiKey fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := apiKeyFromRequest(c); ok {
+			return apiKey(c)
+		}
+		return jwt(c)
+	}
+}
+
+// RequireScope ฟังก์ชันสร้าง middleware ที่ตรวจว่า API key หรือ access token ของแอป (OAuth2) มี scope ที่กำหนด
+// request ที่ผู้ใช้เข้าสู่ระบบด้วย JWT เองไม่ถูกจำกัดด้วย scope (ยังถูกตรวจ role ตามปกติ)
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, ok := c.Locals("scopes").([]string)
+		if !ok {
+			return c.Next()
+		}
+		for _, s := range scopes {
+			if s == scope {
+				return c.Next()
+			}
+		}
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "API key หรือ token ของแอปไม่มี scope "+scope, nil)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORSPolicy แปลงการตั้งค่า CORS เป็น cors.Config ของ Fiber สำหรับเส้นทางกลุ่มหนึ่ง
+type CORSPolicy func(cfg *config.CORSConfig) cors.Config
+
+// CORS สร้าง middleware จัดการ Cross-Origin Resource Sharing ตาม policy ที่กำหนด
+// เมื่อการตั้งค่า CORS ถูก reload จะสร้าง handler ใหม่และสลับเข้าใช้งานทันทีโดยไม่ต้อง restart
+func CORS(store *config.Store, policy CORSPolicy) fiber.Handler {
+	return reloadable(store,
+		func(cfg *config.Config) *config.CORSConfig { return cfg.CORS },
+		func(cfg *config.CORSConfig) fiber.Handler { return cors.New(policy(cfg)) },
+	)
+}
+
+// APICORSPolicy นโยบาย CORS ของ /api/v1 ตามค่า CORS_* ทั้งหมด
+func APICORSPolicy(cfg *config.CORSConfig) cors.Config {
+	return cors.Config{
+		AllowOrigins:     strings.Join(cfg.AllowOrigins, ","), // รองรับ wildcard subdomain เช่น https://*.example.com
+		AllowOriginsFunc: denyIfEmpty(cfg.AllowOrigins),
+		AllowMethods:     strings.Join(cfg.AllowMethods, ","),
+		AllowHeaders:     strings.Join(cfg.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(cfg.ExposeHeaders, ","),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge / time.Second),
+	}
+}
+
+// SwaggerCORSPolicy นโยบาย CORS ของ /swagger ซึ่งเป็นเอกสารแบบอ่านอย่างเดียว
+// อนุญาตเฉพาะ GET/HEAD จาก CORS_SWAGGER_ALLOW_ORIGINS และไม่ส่ง credentials
+func SwaggerCORSPolicy(cfg *config.CORSConfig) cors.Config {
+	return cors.Config{
+		AllowOrigins:     strings.Join(cfg.SwaggerOrigins, ","),
+		AllowOriginsFunc: denyIfEmpty(cfg.SwaggerOrigins),
+		AllowMethods:     strings.Join([]string{fiber.MethodGet, fiber.MethodHead}, ","),
+		MaxAge:           int(cfg.MaxAge / time.Second),
+	}
+}
+
+// denyIfEmpty คืนค่าฟังก์ชันที่ปฏิเสธทุก origin เมื่อไม่ได้กำหนด origin ไว้เลย
+// (Fiber จะใช้ "*" เป็นค่าเริ่มต้นหากไม่มีทั้ง AllowOrigins และ AllowOriginsFunc)
+func denyIfEmpty(origins []string) func(origin string) bool {
+	if len(origins) > 0 {
+		return nil
+	}
+	return func(string) bool { return false }
+}
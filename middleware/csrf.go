@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CSRFMiddleware ฟังก์ชันสร้าง middleware ป้องกัน Cross-Site Request Forgery แบบ double-submit
+// ใช้กับ request ที่ยืนยันตัวตนด้วย cookie ของ session เท่านั้น (JWTMiddleware ตั้ง Locals "session_cookie")
+// request ที่แนบ Authorization header หรือ API key มาเองไม่ถูกตรวจ เพราะ browser ไม่แนบให้โดยอัตโนมัติ
+//
+// method ที่แก้ไขข้อมูล (POST, PUT, PATCH, DELETE) ต้องส่งค่าจาก cookie ของ CSRF token กลับมาใน header
+// (ค่าเริ่มต้น X-CSRF-Token) และ token ต้องถูกเซ็นผูกกับ session token ปัจจุบัน
+func CSRFMiddleware(store *config.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if fromCookie, _ := c.Locals("session_cookie").(bool); !fromCookie {
+			return c.Next()
+		}
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+			return c.Next()
+		}
+
+		span := startSpan(c, "CSRFMiddleware")
+		cfg := store.Get()
+		header := c.Get(cfg.Session.CSRFHeader)
+		cookie := c.Cookies(cfg.Session.CSRFCookieName)
+
+		// header ต้องตรงกับ cookie (double-submit) และต้องเป็น token ที่ออกให้ session นี้
+		if header == "" || header != cookie || !utils.VerifyCSRFToken(cfg.JWT.Secret, c.Cookies(cfg.Session.CookieName), header) {
+			span.End()
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "CSRF token ไม่ถูกต้องหรือไม่ได้ส่งมา", nil)
+		}
+		span.End()
+		return c.Next()
+	}
+}
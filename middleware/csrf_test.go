@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestCSRFMiddleware ตรวจ double-submit: header ต้องตรงกับ cookie และต้องเป็น token ของ session ใน cookie
+// request ที่ไม่ได้ยืนยันตัวตนด้วย cookie และ method ที่ไม่แก้ไขข้อมูลไม่ถูกตรวจ
+func TestCSRFMiddleware(t *testing.T) {
+	session := &config.SessionConfig{CookieName: "gt_session", CSRFCookieName: "gt_csrf", CSRFHeader: "X-CSRF-Token"}
+	store := config.NewStore(&config.Config{JWT: &config.JWTConfig{Secret: "test-secret"}, Session: session}, nil)
+
+	token, err := utils.GenerateCSRFToken("test-secret", "session-a")
+	if err != nil {
+		t.Fatalf("GenerateCSRFToken: %v", err)
+	}
+	otherToken, err := utils.GenerateCSRFToken("test-secret", "session-b")
+	if err != nil {
+		t.Fatalf("GenerateCSRFToken: %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		// แทน JWTMiddleware: request ที่มี cookie ของ session ถือว่ายืนยันตัวตนด้วย cookie
+		c.Locals("session_cookie", c.Cookies(session.CookieName) != "")
+		return c.Next()
+	})
+	app.Use(CSRFMiddleware(store))
+	app.All("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	tests := []struct {
+		name    string
+		method  string
+		session string // cookie ของ session ("" = ยืนยันตัวตนด้วย header)
+		cookie  string // cookie ของ CSRF token
+		header  string // header X-CSRF-Token
+		want    int
+	}{
+		{"valid", fiber.MethodPost, "session-a", token, token, fiber.StatusNoContent},
+		{"missing header", fiber.MethodPost, "session-a", token, "", fiber.StatusForbidden},
+		{"missing cookie", fiber.MethodDelete, "session-a", "", token, fiber.StatusForbidden},
+		{"header differs from cookie", fiber.MethodPut, "session-a", token, otherToken, fiber.StatusForbidden},
+		{"token of another session", fiber.MethodPatch, "session-a", otherToken, otherToken, fiber.StatusForbidden},
+		{"tampered token", fiber.MethodPost, "session-a", token + "x", token + "x", fiber.StatusForbidden},
+		{"safe method", fiber.MethodGet, "session-a", "", "", fiber.StatusNoContent},
+		{"not cookie authenticated", fiber.MethodPost, "", "", "", fiber.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.session != "" {
+				req.AddCookie(&http.Cookie{Name: session.CookieName, Value: tt.session})
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: session.CSRFCookieName, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(session.CSRFHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, ต้องการ %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
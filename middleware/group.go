@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireGroup ฟังก์ชันสร้าง middleware ตรวจว่าผู้ใช้เป็นสมาชิกของกลุ่มที่ระบุอย่างน้อยหนึ่งกลุ่ม
+// ใช้หลัง TenantMiddleware (ค้นหากลุ่มตามชื่อในองค์กรที่เลือก) แทนหรือร่วมกับ AdminMiddleware
+// Admin ของระบบผ่านเสมอเช่นเดียวกับ AdminMiddleware
+//
+// ตัวอย่าง: reports.Use(middleware.RequireGroup(groups, "finance", "auditors"))
+func RequireGroup(groups *repository.GroupRepository, names ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		span := startSpan(c, "RequireGroup")
+		if c.Locals("role") == "admin" {
+			span.End()
+			return c.Next()
+		}
+
+		userID, _ := c.Locals("user_id").(int)
+		tenantID, _ := c.Locals("tenant_id").(int)
+		member, err := groups.ForTenant(tenantID).InAnyGroup(c.UserContext(), userID, names)
+		if err != nil {
+			span.RecordError(err)
+			span.End()
+			return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบกลุ่มได้", err)
+		}
+		span.End()
+
+		if !member {
+			return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องเป็นสมาชิกของกลุ่ม "+strings.Join(names, ", ")+" เท่านั้น", nil)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// impersonationAuditTimeout เวลาสูงสุดของการบันทึก audit log หลังตอบ request
+const impersonationAuditTimeout = 5 * time.Second
+
+// BlockImpersonation ฟังก์ชันสร้าง middleware ที่ปฏิเสธ token ของการ impersonation (HTTP 403, code impersonation_forbidden)
+// ใช้กับเส้นทางที่เจ้าของบัญชีต้องทำเอง เช่น เปลี่ยนรหัสผ่าน, เพิกถอน session หรืออนุญาตแอป
+func BlockImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("impersonator_id").(int); !ok {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusForbidden).JSON(utils.Response{
+			Status:  false,
+			Message: "ไม่สามารถทำรายการนี้ขณะเข้าสู่ระบบแทนผู้ใช้",
+			Code:    utils.CodeImpersonationForbidden,
+		})
+	}
+}
+
+// AuditImpersonation ฟังก์ชันสร้าง middleware ที่บันทึกทุก request ของ token การ impersonation ลง audit log
+// (action impersonation.request, actor = Admin) พร้อม method, path, ผู้ใช้เป้าหมาย และ HTTP status
+// ต้องอยู่ถัดจาก Authenticate ทันที (ก่อน CSRFMiddleware และ TenantMiddleware) เพื่อให้ request ที่ถูก middleware เหล่านั้นปฏิเสธถูกบันทึกด้วย
+// องค์กรของ request อ่านหลัง c.Next() จึงได้ค่าที่ TenantMiddleware เลือกไว้ (ถ้าผ่าน)
+func AuditImpersonation(audit *repository.AuditRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		impersonatorID, ok := c.Locals("impersonator_id").(int)
+		if !ok {
+			return c.Next()
+		}
+
+		err := c.Next()
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		entry := &models.AuditLog{ActorID: &impersonatorID, Action: "impersonation.request", IPAddress: c.IP()}
+		if tenant, ok := c.Locals("tenant_id").(int); ok && tenant != 0 {
+			entry.OrganizationID = &tenant
+		}
+		details := map[string]interface{}{
+			"user_id": c.Locals("user_id"),
+			"method":  c.Method(),
+			"path":    c.Path(),
+			"status":  status,
+		}
+		if sessionID, ok := c.Locals("session_id").(int); ok {
+			details["session_id"] = sessionID
+		}
+		// บันทึกด้วย context ของตัวเอง (request อาจถูกยกเลิกแล้ว) ความผิดพลาดบันทึก log เท่านั้น
+		ctx, cancel := context.WithTimeout(context.Background(), impersonationAuditTimeout)
+		defer cancel()
+		if auditErr := audit.Record(ctx, entry, details); auditErr != nil {
+			log.Printf("⚠️  ไม่สามารถบันทึก audit log ของการ impersonation โดย Admin %d: %v", impersonatorID, auditErr)
+		}
+		return err
+	}
+}
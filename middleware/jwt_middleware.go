@@ -1,63 +1,174 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"strings"
+	"time"
 
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
 	"github.com/Sing254463/GoTemplate/Backend/utils"
 	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// lastSeenInterval ระยะเวลาขั้นต่ำระหว่างการอัปเดต last_seen_at ของ session
+// เพื่อไม่ให้ทุก request ต้องเขียนฐานข้อมูล
+const lastSeenInterval = time.Minute
+
 // JWTMiddleware ฟังก์ชันสร้าง middleware สำหรับตรวจสอบ JWT token
-// รับพารามิเตอร์ secret (กุญแจลับ) และคืนค่า fiber.Handler
-func JWTMiddleware(secret string) fiber.Handler {
+// รับ config.Store และอ่าน JWT_SECRET ทุก request เพื่อให้การ reload การตั้งค่ามีผลทันที
+// รับ token จาก Authorization: Bearer หรือจาก cookie ของ session (เมื่อเปิด SESSION_MODE=cookie/both)
+// access token ที่ออกโดย OAuth2 authorization server (มี client_id) จะถูกตรวจการเพิกถอนกับ tokens
+// และถูกจำกัดด้วย scope ที่ผู้ใช้อนุญาตเช่นเดียวกับ API key
+// token ที่ผู้ใช้เข้าสู่ระบบเองต้องมี session ที่ยังไม่ถูกเพิกถอนใน sessions (ค้นหาด้วย jti)
+// บัญชีของ token ต้องยังใช้งานได้ (ตรวจกับ statuses) จึงปฏิเสธบัญชีที่ถูกระงับหลังออก token ได้
+// token ของการ impersonation (มี claim act) ต้องตรงกับ session ที่ Admin คนนั้นออก และบัญชีของ Admin ต้องยังใช้งานได้
+// โดยเก็บ Admin ไว้ใน c.Locals("impersonator_id") และ c.Locals("impersonator_username")
+func JWTMiddleware(store *config.Store, tokens *repository.OAuthRepository, sessions *repository.SessionRepository, statuses *AccountStatusCache) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		// สร้าง span แยกสำหรับขั้นตอนตรวจสอบ token (ปิดก่อนส่งต่อไปยัง handler ถัดไป)
+		span := startSpan(c, "JWTMiddleware")
+
+		cfg := store.Get()
+
 		// ดึง token จาก Authorization header
 		// รูปแบบที่คาดหวัง: "Bearer <token>"
+		// เมื่อเปิด SESSION_MODE=cookie/both และไม่มี header จะใช้ token จาก cookie ของ session แทน
 		authHeader := c.Get("Authorization")
-		if authHeader == "" {
-			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "ไม่พบ authorization header", nil)
-		}
+		tokenString := ""
+		if authHeader != "" {
+			// แยก header เป็นส่วนๆ และตรวจสอบรูปแบบ
+			// ต้องเป็น "Bearer <token>" เท่านั้น
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "รูปแบบ authorization header ไม่ถูกต้อง", nil)
+			}
 
-		// แยก header เป็นส่วนๆ และตรวจสอบรูปแบบ
-		// ต้องเป็น "Bearer <token>" เท่านั้น
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "รูปแบบ authorization header ไม่ถูกต้อง", nil)
+			// ดึง token string จากส่วนที่ 2
+			tokenString = parts[1]
+		} else if cfg.Session.CookieEnabled() && c.Cookies(cfg.Session.CookieName) != "" {
+			tokenString = c.Cookies(cfg.Session.CookieName)
+			// CSRFMiddleware ตรวจ CSRF token เฉพาะ request ที่ยืนยันตัวตนด้วย cookie
+			c.Locals("session_cookie", true)
+		} else {
+			span.End()
+			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "ไม่พบ authorization header", nil)
 		}
 
-		// ดึง token string จากส่วนที่ 2
-		tokenString := parts[1]
-
 		// ตรวจสอบและแยกข้อมูลจาก JWT token
-		claims, err := utils.ParseJWT(tokenString, secret)
+		claims, err := utils.ParseJWT(tokenString, cfg.JWT.Secret)
 		if err != nil {
+			span.RecordError(err)
+			span.End()
 			return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Token ไม่ถูกต้องหรือหมดอายุ", err)
 		}
 
+		// ตรวจสถานะบัญชีก่อน session เพื่อให้ client ของบัญชีที่ถูกระงับ (session ถูกเพิกถอนพร้อมกัน) ได้รหัส account_suspended
+		if claims.UserID != 0 {
+			if ok, err := checkAccountStatus(c, statuses, claims.UserID); !ok {
+				span.End()
+				return err
+			}
+		}
+		if claims.Act != nil {
+			if ok, err := checkAccountStatus(c, statuses, claims.Act.UserID); !ok {
+				span.End()
+				return err
+			}
+		}
+
+		// token ของแอป: client_credentials (ไม่มีผู้ใช้) ใช้กับ resource server ภายนอกผ่าน introspection เท่านั้น
+		if claims.ClientID != "" {
+			if claims.UserID == 0 {
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "token ของแอปที่ไม่มีผู้ใช้ไม่สามารถเรียก API นี้ได้", nil)
+			}
+			revoked, err := tokens.IsAccessTokenRevoked(c.UserContext(), claims.ID)
+			if err != nil {
+				span.RecordError(err)
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบ token ได้", err)
+			}
+			if revoked {
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Token ถูกเพิกถอนแล้ว", nil)
+			}
+			c.Locals("client_id", claims.ClientID)
+			c.Locals("scopes", strings.Fields(claims.Scope))
+		} else {
+			// token ของผู้ใช้: session ต้องยังใช้งานได้ (ผู้ใช้หรือ Admin เพิกถอนได้ก่อน token หมดอายุ)
+			session, err := sessions.GetByJTI(c.UserContext(), claims.ID)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				span.RecordError(err)
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบ session ได้", err)
+			}
+			now := time.Now()
+			if session == nil || session.UserID != claims.UserID || !session.Active(now) {
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Session ถูกเพิกถอนหรือออกจากระบบแล้ว", nil)
+			}
+			if !sameImpersonator(session.ImpersonatorID, claims.Act) {
+				span.End()
+				return utils.ErrorResponse(c, fiber.StatusUnauthorized, "Token ไม่ตรงกับ session", nil)
+			}
+			if now.Sub(session.LastSeenAt) >= lastSeenInterval {
+				if err := sessions.TouchLastSeen(c.UserContext(), session.ID, now); err != nil {
+					log.Printf("⚠️  ไม่สามารถอัปเดต last_seen_at ของ session %d: %v", session.ID, err)
+				}
+			}
+			c.Locals("session_id", session.ID)
+			// องค์กรที่เลือกตอนเข้าสู่ระบบ TenantMiddleware ใช้เมื่อ request ไม่ได้ระบุองค์กร
+			c.Locals("token_tenant_id", claims.TenantID)
+			if claims.Act != nil {
+				c.Locals("impersonator_id", claims.Act.UserID)
+				c.Locals("impersonator_username", claims.Act.Username)
+				span.SetAttributes(attribute.Int("impersonator_id", claims.Act.UserID))
+			}
+		}
+
 		// เก็บข้อมูลผู้ใช้ใน context เพื่อให้ handler ต่อไปใช้งานได้
 		// ข้อมูลที่เก็บ: user_id, username, role
 		c.Locals("user_id", claims.UserID)
 		c.Locals("username", claims.Username)
 		c.Locals("role", claims.Role)
+		span.SetAttributes(attribute.Int("user_id", claims.UserID))
+		span.End()
 
 		// ส่งต่อไปยัง handler ถัดไป
 		return c.Next()
 	}
 }
 
+// sameImpersonator ตรวจว่า claim act ของ token ตรงกับ Admin ที่บันทึกไว้ใน session
+// (token ของผู้ใช้เองต้องไม่มีทั้งสองอย่าง)
+func sameImpersonator(impersonatorID *int, act *utils.Actor) bool {
+	if impersonatorID == nil || act == nil {
+		return impersonatorID == nil && act == nil
+	}
+	return *impersonatorID == act.UserID
+}
+
 // AdminMiddleware ฟังก์ชันสร้าง middleware สำหรับตรวจสอบสิทธิ์ Admin
 // ใช้ร่วมกับ JWTMiddleware เพื่อให้มั่นใจว่าผู้ใช้เป็น Admin
 func AdminMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		span := startSpan(c, "AdminMiddleware")
+
 		// ดึงข้อมูล role จาก context (ที่เก็บไว้โดย JWTMiddleware)
 		role, ok := c.Locals("role").(string)
 
 		// ตรวจสอบว่า role เป็น "admin" หรือไม่
 		if !ok || role != "admin" {
+			span.End()
 			return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องมีสิทธิ์ Admin เท่านั้น", nil)
 		}
 
 		// หากเป็น Admin ให้ส่งต่อไปยัง handler ถัดไป
+		span.End()
 		return c.Next()
 	}
 }
@@ -1,17 +1,47 @@
 package middleware
 
 import (
+	"io"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
+// minLogStatus รหัสสถานะต่ำสุดที่จะถูกบันทึก log ปรับได้ขณะรันด้วย SetLogLevel
+var minLogStatus atomic.Int32
+
+// SetLogLevel กำหนดระดับการบันทึก log ของ request (ค่าของ LOG_LEVEL)
+// - debug, info: บันทึกทุก request
+// - warn: บันทึกเฉพาะ request ที่ตอบกลับด้วย 4xx และ 5xx
+// - error: บันทึกเฉพาะ request ที่ตอบกลับด้วย 5xx
+// ปลอดภัยที่จะเรียกขณะเซิร์ฟเวอร์ทำงาน (ใช้กับ config.Store.OnChange)
+func SetLogLevel(level string) {
+	switch level {
+	case "warn":
+		minLogStatus.Store(fiber.StatusBadRequest)
+	case "error":
+		minLogStatus.Store(fiber.StatusInternalServerError)
+	default:
+		minLogStatus.Store(0)
+	}
+}
+
 func Logger() fiber.Handler {
 	return logger.New(logger.Config{
 		Format:     "${time} | ${status} | ${latency} | ${ip} | ${method} | ${path} | ${error}\n",
 		TimeFormat: time.RFC3339,
 		TimeZone:   "Local",
+		// สถานะของ response จะรู้หลังจาก handler ทำงานเสร็จแล้วเท่านั้น
+		// จึงสร้างข้อความ log ทิ้งไว้ แล้วเลือกเขียนออกใน Done ตามระดับ log ปัจจุบัน
+		Output: io.Discard,
+		Done: func(c *fiber.Ctx, logString []byte) {
+			if int32(c.Response().StatusCode()) >= minLogStatus.Load() {
+				os.Stdout.Write(logString)
+			}
+		},
 	})
 }
 
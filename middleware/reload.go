@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"reflect"
+	"sync/atomic"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reloadable สร้าง handler จากการตั้งค่าส่วนที่ section เลือก และสร้าง handler ใหม่
+// เมื่อส่วนนั้นเปลี่ยนหลังการ reload การตั้งค่า ใช้กับ middleware ของ Fiber ที่อ่านการตั้งค่าครั้งเดียวตอนสร้าง
+func reloadable[T any](store *config.Store, section func(*config.Config) T, build func(T) fiber.Handler) fiber.Handler {
+	var handler atomic.Pointer[fiber.Handler]
+	swap := func(cfg T) {
+		h := build(cfg)
+		handler.Store(&h)
+	}
+
+	swap(section(store.Get()))
+	store.OnChange(func(old, new *config.Config) {
+		if next := section(new); !reflect.DeepEqual(section(old), next) {
+			swap(next)
+		}
+	})
+
+	return func(c *fiber.Ctx) error {
+		return (*handler.Load())(c)
+	}
+}
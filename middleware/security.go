@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/helmet"
+)
+
+// SecurityHeaders สร้าง middleware ที่ส่ง security headers ตาม SECURITY_*
+// (HSTS, X-Content-Type-Options, X-Frame-Options, Referrer-Policy, Content-Security-Policy)
+// /swagger ใช้ SECURITY_SWAGGER_CSP เพื่อให้ Swagger UI ยังทำงานได้ ส่วนเส้นทางอื่นใช้ SECURITY_CSP
+// HSTS ถูกส่งเฉพาะเมื่อ request มาทาง HTTPS และการตั้งค่าเปลี่ยนได้ขณะรันผ่านการ reload
+func SecurityHeaders(store *config.Store) fiber.Handler {
+	section := func(cfg *config.Config) *config.SecurityConfig { return cfg.Security }
+	api := reloadable(store, section, func(cfg *config.SecurityConfig) fiber.Handler {
+		return securityHandler(cfg, cfg.ContentSecurityPolicy, "require-corp")
+	})
+	// Swagger UI โหลด font จาก Google Fonts จึงไม่บังคับ Cross-Origin-Embedder-Policy
+	swagger := reloadable(store, section, func(cfg *config.SecurityConfig) fiber.Handler {
+		return securityHandler(cfg, cfg.SwaggerCSP, "unsafe-none")
+	})
+
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Path(), "/swagger") {
+			return swagger(c)
+		}
+		return api(c)
+	}
+}
+
+// securityHandler สร้าง helmet middleware จากการตั้งค่าและ CSP ที่กำหนด
+func securityHandler(cfg *config.SecurityConfig, csp, embedderPolicy string) fiber.Handler {
+	if !cfg.Headers {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return helmet.New(helmet.Config{
+		ContentTypeNosniff:        "nosniff",
+		XFrameOptions:             cfg.FrameOptions,
+		ReferrerPolicy:            cfg.ReferrerPolicy,
+		ContentSecurityPolicy:     csp,
+		CSPReportOnly:             cfg.CSPReportOnly,
+		HSTSMaxAge:                int(cfg.HSTSMaxAge / time.Second),
+		HSTSExcludeSubdomains:     !cfg.HSTSIncludeSubdomains,
+		HSTSPreloadEnabled:        cfg.HSTSPreload,
+		CrossOriginEmbedderPolicy: embedderPolicy,
+	})
+}
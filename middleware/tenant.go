@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TenantMiddleware ฟังก์ชันสร้าง middleware เลือกองค์กร (tenant) ของ request
+// ใช้หลัง JWTMiddleware/APIKeyMiddleware และเก็บ tenant_id กับ tenant_role (role ในองค์กร) ใน c.Locals
+//
+// องค์กรมาจาก header TENANT_HEADER หรือ subdomain ของ TENANT_BASE_DOMAIN ก่อน แล้วจึงใช้ claim tid ของ token
+// API key และ token ของแอปที่ไม่ได้ระบุองค์กรใช้องค์กรแรกที่เจ้าของเป็นสมาชิก
+// ผู้ใช้ที่ไม่ใช่ Admin ของระบบต้องเป็นสมาชิกขององค์กร และระบุองค์กรอื่นจากที่อยู่ใน token ไม่ได้ (ต้องเข้าสู่ระบบใหม่)
+// Admin ของระบบ (users.role = admin) เลือกองค์กรใดก็ได้ และเมื่อไม่เลือกจะมี tenant_id = 0 (เห็นทุกองค์กร)
+func TenantMiddleware(store *config.Store, orgs *repository.OrganizationRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		span := startSpan(c, "TenantMiddleware")
+		ok, err := checkTenant(c, store.Get(), orgs)
+		if err != nil {
+			span.RecordError(err)
+		}
+		if tenantID, found := c.Locals("tenant_id").(int); ok && found {
+			span.SetAttributes(attribute.Int("tenant_id", tenantID))
+		}
+		span.End()
+
+		if !ok {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// checkTenant เลือกองค์กรของผู้ใช้ที่ยืนยันตัวตนแล้ว และเก็บ tenant_id กับ tenant_role ใน c.Locals
+// คืนค่า false เมื่อ request ใช้องค์กรที่ระบุไม่ได้ (ส่ง response ข้อผิดพลาดไปแล้ว)
+func checkTenant(c *fiber.Ctx, cfg *config.Config, orgs *repository.OrganizationRepository) (bool, error) {
+	userID := c.Locals("user_id").(int)
+	platformAdmin := c.Locals("role") == "admin"
+	claimed, hasClaim := c.Locals("token_tenant_id").(int)
+
+	tenantID := claimed
+	if slug := utils.TenantFromRequest(c, cfg.Tenant); slug != "" {
+		org, err := orgs.GetBySlug(c.UserContext(), slug)
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, utils.ErrorResponse(c, fiber.StatusNotFound, "ไม่พบองค์กร "+slug, nil)
+		}
+		if err != nil {
+			return false, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบองค์กรได้", err)
+		}
+		if hasClaim && org.ID != claimed && !platformAdmin {
+			return false, utils.ErrorResponse(c, fiber.StatusForbidden, "token ไม่ได้ออกให้องค์กรนี้ กรุณาเข้าสู่ระบบกับองค์กร "+slug, nil)
+		}
+		tenantID = org.ID
+	} else if !hasClaim && !platformAdmin {
+		memberships, err := orgs.ForUser(c.UserContext(), userID)
+		if err != nil {
+			return false, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบองค์กรได้", err)
+		}
+		if len(memberships) > 0 {
+			tenantID = memberships[0].ID
+		}
+	}
+
+	role := ""
+	if tenantID != 0 {
+		// ตรวจการเป็นสมาชิกทุก request เพื่อให้การนำออกจากองค์กรหรือเปลี่ยน role มีผลทันที
+		var err error
+		role, err = orgs.MemberRole(c.UserContext(), tenantID, userID)
+		switch {
+		case err == nil:
+		case !errors.Is(err, repository.ErrNotFound):
+			return false, utils.ErrorResponse(c, fiber.StatusInternalServerError, "ไม่สามารถตรวจสอบการเป็นสมาชิกได้", err)
+		case platformAdmin:
+			role = "admin"
+		case tenantID == claimed:
+			// ถูกนำออกจากองค์กรหลังเข้าสู่ระบบ: ยังใช้ endpoint ส่วนตัวได้ แต่ไม่เห็นข้อมูลขององค์กร
+			tenantID = 0
+		default:
+			return false, utils.ErrorResponse(c, fiber.StatusForbidden, "คุณไม่ได้เป็นสมาชิกขององค์กรนี้", nil)
+		}
+	}
+
+	c.Locals("tenant_id", tenantID)
+	c.Locals("tenant_role", role)
+	return true, nil
+}
+
+// TenantAdminMiddleware ฟังก์ชันสร้าง middleware ตรวจสิทธิ์ Admin ขององค์กร
+// ใช้หลัง TenantMiddleware: ผ่านเมื่อเป็น Admin ของระบบ หรือมี role admin ในองค์กรที่เลือก
+// handler ต้องจำกัดข้อมูลด้วย tenant_id เอง (repository ที่มี ForTenant)
+func TenantAdminMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		span := startSpan(c, "TenantAdminMiddleware")
+		tenantID, _ := c.Locals("tenant_id").(int)
+		allowed := c.Locals("role") == "admin" || (tenantID != 0 && c.Locals("tenant_role") == "admin")
+		span.End()
+
+		if allowed {
+			return c.Next()
+		}
+		return utils.ErrorResponse(c, fiber.StatusForbidden, "ต้องมีสิทธิ์ Admin ของระบบหรือขององค์กรเท่านั้น", nil)
+	}
+}
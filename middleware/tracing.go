@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/Sing254463/GoTemplate/Backend/telemetry"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing ฟังก์ชันสร้าง middleware สำหรับสร้าง span ให้กับทุก request
+// - อ่าน W3C traceparent จาก header เพื่อต่อ trace กับบริการต้นทาง
+// - เก็บ context ของ span ไว้ใน c.UserContext() เพื่อให้ query ฐานข้อมูลเป็น child span
+// - เพิ่ม attribute route และ user_id (ถ้าผ่าน JWTMiddleware แล้ว) หลังประมวลผลเสร็จ
+// ควรติดตั้งก่อน middleware อื่นๆ เพื่อให้ครอบคลุมเวลาทั้งหมดของ request
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.UserContext(), requestCarrier{c})
+
+		ctx, span := telemetry.Tracer().Start(ctx, c.Method()+" "+c.Path(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.ClientAddress(c.IP()),
+				semconv.UserAgentOriginal(c.Get(fiber.HeaderUserAgent)),
+			),
+		)
+		defer span.End()
+
+		// ส่ง context ที่มี span ต่อให้ handler และส่ง traceparent กลับใน response
+		c.SetUserContext(ctx)
+		propagator.Inject(ctx, responseCarrier{c})
+
+		err := c.Next()
+
+		// route จะรู้หลังจาก router จับคู่เส้นทางแล้วเท่านั้น เช่น /api/v1/users/:id
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+
+		if userID, ok := c.Locals("user_id").(int); ok {
+			span.SetAttributes(attribute.Int("user_id", userID))
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			span.RecordError(err)
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+
+		return err
+	}
+}
+
+// startSpan ฟังก์ชันช่วยสร้าง child span ภายใน middleware อื่นๆ
+// ใช้เพื่อแยกเวลาที่ใช้ในแต่ละขั้นตอน เช่น การตรวจสอบ JWT หรือสิทธิ์ Admin
+func startSpan(c *fiber.Ctx, name string) trace.Span {
+	_, span := telemetry.Tracer().Start(c.UserContext(), name)
+	return span
+}
+
+// requestCarrier อ่าน header ของ request สำหรับการ extract trace context
+type requestCarrier struct{ c *fiber.Ctx }
+
+func (rc requestCarrier) Get(key string) string { return rc.c.Get(key) }
+
+func (rc requestCarrier) Set(key, value string) { rc.c.Request().Header.Set(key, value) }
+
+func (rc requestCarrier) Keys() []string {
+	keys := make([]string, 0)
+	rc.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// responseCarrier เขียน header ของ response สำหรับการ inject trace context
+type responseCarrier struct{ c *fiber.Ctx }
+
+func (rc responseCarrier) Get(key string) string { return rc.c.GetRespHeader(key) }
+
+func (rc responseCarrier) Set(key, value string) { rc.c.Set(key, value) }
+
+func (rc responseCarrier) Keys() []string {
+	keys := make([]string, 0)
+	rc.c.Response().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceparent ของบริการต้นทางที่ใช้ทดสอบการต่อ trace
+const (
+	remoteTraceID    = "4bf92f3577b34da6a3ce929d0e0e4736"
+	remoteSpanID     = "00f067aa0ba902b7"
+	remoteParentText = "00-" + remoteTraceID + "-" + remoteSpanID + "-01"
+)
+
+// TestTracing ตรวจ span ของ request: ชื่อตาม route, attribute http.route และ status code
+// และ span ของ query ฐานข้อมูลใน handler เป็น child ของ span ของ request
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	db, err := database.Open(context.Background(), &config.DatabaseConfig{Driver: database.DriverSQLite, DBName: ":memory:"})
+	if err != nil {
+		t.Fatalf("database.Open: %v", err)
+	}
+	defer db.Close()
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		var one int
+		if err := db.GetContext(c.UserContext(), &one, "SELECT 1"); err != nil {
+			return err
+		}
+		return fiber.NewError(fiber.StatusNotFound, "ไม่พบผู้ใช้")
+	})
+
+	tests := []struct {
+		name        string
+		traceparent string
+	}{
+		{name: "new trace"},
+		{name: "continues remote trace", traceparent: remoteParentText},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			req := httptest.NewRequest(fiber.MethodGet, "/users/42", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test: %v", err)
+			}
+			if resp.StatusCode != fiber.StatusNotFound {
+				t.Fatalf("status = %d, ต้องการ %d", resp.StatusCode, fiber.StatusNotFound)
+			}
+
+			server := findSpan(t, exporter.GetSpans(), trace.SpanKindServer)
+			if server.Name != "GET /users/:id" {
+				t.Errorf("ชื่อ span = %q, ต้องการ %q", server.Name, "GET /users/:id")
+			}
+			for _, want := range []attribute.KeyValue{
+				semconv.HTTPRoute("/users/:id"),
+				semconv.HTTPResponseStatusCode(fiber.StatusNotFound),
+				semconv.HTTPRequestMethodKey.String(fiber.MethodGet),
+			} {
+				if !hasAttribute(server, want) {
+					t.Errorf("span ไม่มี attribute %s=%s: %v", want.Key, want.Value.Emit(), server.Attributes)
+				}
+			}
+
+			query := findSpan(t, exporter.GetSpans(), trace.SpanKindClient)
+			if got, want := query.Parent.SpanID(), server.SpanContext.SpanID(); got != want {
+				t.Errorf("parent ของ span query = %s, ต้องการ span ของ request %s", got, want)
+			}
+			if query.SpanContext.TraceID() != server.SpanContext.TraceID() {
+				t.Errorf("span query อยู่คนละ trace กับ span ของ request")
+			}
+
+			if tt.traceparent != "" {
+				if got := server.SpanContext.TraceID().String(); got != remoteTraceID {
+					t.Errorf("trace ID = %s, ต้องการ %s จาก traceparent", got, remoteTraceID)
+				}
+				if got := server.Parent.SpanID().String(); got != remoteSpanID {
+					t.Errorf("parent = %s, ต้องการ %s จาก traceparent", got, remoteSpanID)
+				}
+			}
+			if resp.Header.Get("traceparent") == "" {
+				t.Error("response ไม่มี header traceparent")
+			}
+		})
+	}
+}
+
+// findSpan คืนค่า span แรกที่มีชนิดตามที่กำหนด (server = request, client = query ของ otelsql)
+func findSpan(t *testing.T, spans tracetest.SpanStubs, kind trace.SpanKind) *tracetest.SpanStub {
+	t.Helper()
+	for i := range spans {
+		if spans[i].SpanKind == kind {
+			return &spans[i]
+		}
+	}
+	t.Fatalf("ไม่พบ span ชนิด %s ใน %d span", kind, len(spans))
+	return nil
+}
+
+// hasAttribute ตรวจว่า span มี attribute ที่มีค่าตามที่กำหนด
+func hasAttribute(span *tracetest.SpanStub, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes {
+		if attr.Key == want.Key && attr.Value == want.Value {
+			return true
+		}
+	}
+	return false
+}
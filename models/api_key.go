@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Scope ของ API key - กำหนดว่า key เรียกเส้นทางใดได้บ้าง
+// (การเข้าสู่ระบบด้วย JWT ไม่ถูกจำกัดด้วย scope แต่ยังต้องมี role ที่เหมาะสม)
+const (
+	ScopeProfileRead   = "profile:read"         // ดูข้อมูลโปรไฟล์ของเจ้าของ key
+	ScopeProfileWrite  = "profile:write"        // แก้ไขโปรไฟล์และรูปโปรไฟล์ของเจ้าของ key
+	ScopeUsersRead     = "users:read"           // ดูข้อมูลผู้ใช้ (ต้องเป็น key ของ Admin)
+	ScopeUsersWrite    = "users:write"          // แก้ไข/ลบผู้ใช้ (ต้องเป็น key ของ Admin)
+	ScopeAPIKeysManage = "api_keys:manage"      // จัดการ API key (ต้องเป็น key ของ Admin)
+	ScopeOAuthClients  = "oauth_clients:manage" // จัดการแอปของ OAuth2 authorization server (ต้องเป็น key ของ Admin)
+	ScopeWebhooks      = "webhooks:manage"      // จัดการ webhook และดูบันทึกการส่ง (ต้องเป็น key ของ Admin)
+)
+
+// APIKey โครงสร้างข้อมูล API key ในฐานข้อมูล
+// key จริงไม่ถูกเก็บไว้ เก็บเฉพาะ prefix สำหรับค้นหา และ SHA-256 ของ key
+type APIKey struct {
+	ID         int        `json:"id" db:"id"`                     // ID ของ key (Primary Key)
+	UserID     int        `json:"user_id" db:"user_id"`           // เจ้าของ key (request ที่ใช้ key จะทำงานในนามผู้ใช้นี้)
+	Name       string     `json:"name" db:"name"`                 // ชื่อสำหรับอธิบายว่า key ใช้ทำอะไร เช่น "nightly-export"
+	Prefix     string     `json:"prefix" db:"prefix"`             // ส่วนที่ไม่เป็นความลับของ key ใช้ค้นหาและแสดงผล
+	KeyHash    string     `json:"-" db:"key_hash"`                // SHA-256 ของ key ทั้งหมด (ไม่ส่งกลับไปยัง client)
+	Scopes     string     `json:"-" db:"scopes"`                  // scope คั่นด้วย comma
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`     // เวลาหมดอายุ (nil = ไม่หมดอายุ)
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"` // เวลาที่ใช้งานล่าสุด
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`     // เวลาที่ถูกเพิกถอน (nil = ยังใช้งานได้)
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`     // วันที่สร้าง
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`     // วันที่อัปเดตล่าสุด
+}
+
+// APIKeyCreate โครงสร้างสำหรับรับข้อมูลการสร้าง API key
+type APIKeyCreate struct {
+	Name      string     `json:"name" validate:"required,min=1,max=100"`                                                                                                             // ชื่อของ key
+	UserID    int        `json:"user_id" validate:"omitempty,min=1"`                                                                                                                 // เจ้าของ key (ไม่ระบุ = ผู้ที่สร้าง)
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=profile:read profile:write users:read users:write api_keys:manage oauth_clients:manage webhooks:manage"` // scope ที่อนุญาต
+	ExpiresAt *time.Time `json:"expires_at"`                                                                                                                                         // เวลาหมดอายุ (ไม่ระบุ = ไม่หมดอายุ)
+}
+
+// APIKeyUpdate โครงสร้างสำหรับรับข้อมูลการแก้ไข API key (ส่งเฉพาะ field ที่ต้องการเปลี่ยน)
+type APIKeyUpdate struct {
+	Name      *string    `json:"name" validate:"omitempty,min=1,max=100"`                                                                                                             // ชื่อใหม่
+	Scopes    []string   `json:"scopes" validate:"omitempty,min=1,dive,oneof=profile:read profile:write users:read users:write api_keys:manage oauth_clients:manage webhooks:manage"` // scope ใหม่
+	ExpiresAt *time.Time `json:"expires_at"`                                                                                                                                          // เวลาหมดอายุใหม่
+}
+
+// APIKeyResponse โครงสร้างสำหรับส่งข้อมูล API key กลับไป
+type APIKeyResponse struct {
+	APIKey
+	Scopes []string `json:"scopes"`        // scope ในรูปแบบ array
+	Key    string   `json:"key,omitempty"` // key เต็ม แสดงเพียงครั้งเดียวตอนสร้าง
+}
+
+// ScopeList คืนค่า scope ของ key ในรูปแบบ slice
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// HasScope ตรวจว่า key มี scope ที่กำหนดหรือไม่
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Active ตรวจว่า key ยังใช้งานได้ (ไม่ถูกเพิกถอนและยังไม่หมดอายุ) ณ เวลาที่กำหนด
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// ConvertToResponse แปลง APIKey เป็น APIKeyResponse (ไม่รวม key เต็ม)
+func (k *APIKey) ConvertToResponse() APIKeyResponse {
+	return APIKeyResponse{APIKey: *k, Scopes: k.ScopeList()}
+}
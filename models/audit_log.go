@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog บันทึกการกระทำของผู้ดูแลในฐานข้อมูล (ตาราง audit_logs)
+type AuditLog struct {
+	ID             int       `json:"id" db:"id"`                           // ID ของบันทึก
+	OrganizationID *int      `json:"organization_id" db:"organization_id"` // องค์กรที่เกี่ยวข้อง (NULL = ระดับระบบ)
+	ActorID        *int      `json:"actor_id" db:"actor_id"`               // ผู้กระทำ (NULL = ระบบ)
+	Action         string    `json:"action" db:"action"`                   // การกระทำ เช่น users.batch.delete
+	Details        *string   `json:"-" db:"details"`                       // รายละเอียดเป็น JSON object
+	IPAddress      string    `json:"ip_address" db:"ip_address"`           // IP ของผู้กระทำ
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`           // เวลาที่บันทึก
+}
+
+// AuditLogResponse โครงสร้างสำหรับส่งบันทึกกลับไปพร้อมรายละเอียดเป็น JSON
+type AuditLogResponse struct {
+	AuditLog
+	Details json.RawMessage `json:"details,omitempty" swaggertype:"object"` // รายละเอียดของการกระทำ
+}
+
+// ConvertToResponse แปลง AuditLog เป็น AuditLogResponse
+func (a *AuditLog) ConvertToResponse() AuditLogResponse {
+	response := AuditLogResponse{AuditLog: *a}
+	if a.Details != nil {
+		response.Details = json.RawMessage(*a.Details)
+	}
+	return response
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ชื่อของ domain event (เก็บในคอลัมน์ event_outbox.name จึงห้ามเปลี่ยนชื่อที่ใช้ไปแล้ว)
+const (
+	EventUserRegistered = "user.registered" // บัญชีผู้ใช้ถูกสร้าง (ลงทะเบียน, รับคำเชิญ, import หรือ social login ครั้งแรก)
+	EventUserLoggedIn   = "user.logged_in"  // ผู้ใช้เข้าสู่ระบบด้วยรหัสผ่านสำเร็จ
+	EventUserUpdated    = "user.updated"    // โปรไฟล์, รูปโปรไฟล์, role ของระบบ หรือสถานะบัญชีเปลี่ยน
+	EventUserDeleted    = "user.deleted"    // บัญชีผู้ใช้ถูกลบ (การนำออกจากองค์กรที่ยังเหลือบัญชีไม่นับ)
+)
+
+// สถานะของ event ใน outbox
+const (
+	OutboxPending   = "pending"   // รอส่งให้ subscriber (ครั้งแรกหรือรอส่งซ้ำตาม next_attempt_at)
+	OutboxProcessed = "processed" // subscriber ทุกตัวประมวลผลสำเร็จแล้ว
+	OutboxFailed    = "failed"    // ยังมี subscriber ที่ล้มเหลวครบ EVENTS_MAX_ATTEMPTS ครั้ง
+)
+
+// DomainEvent event ที่เกิดขึ้นในระบบ ถูกบันทึกลง outbox เป็น JSON พร้อมชื่อจาก EventName
+type DomainEvent interface {
+	EventName() string
+}
+
+// UserRegistered บัญชีผู้ใช้ใหม่ถูกสร้าง ผ่าน /auth/register, การรับคำเชิญ, การ import หรือการเข้าสู่ระบบด้วย OAuth/OIDC ครั้งแรก
+type UserRegistered struct {
+	User UserResponse `json:"user"` // บัญชีที่สร้าง
+}
+
+// UserLoggedIn ผู้ใช้เข้าสู่ระบบด้วยรหัสผ่านสำเร็จ
+type UserLoggedIn struct {
+	User           UserResponse `json:"user"`            // ผู้ใช้ที่เข้าสู่ระบบ
+	OrganizationID *int         `json:"organization_id"` // องค์กรที่ token ผูกไว้ (nil = ไม่มีองค์กร)
+}
+
+// UserUpdated ข้อมูลบัญชีของผู้ใช้เปลี่ยน (โปรไฟล์, รูปโปรไฟล์, role ของระบบ หรือสถานะบัญชี)
+type UserUpdated struct {
+	User UserResponse `json:"user"` // ข้อมูลหลังเปลี่ยน
+}
+
+// UserDeleted บัญชีผู้ใช้ถูกลบ
+type UserDeleted struct {
+	User UserResponse `json:"user"` // ข้อมูลก่อนลบ
+}
+
+// EventName คืนค่าชื่อ event
+func (UserRegistered) EventName() string { return EventUserRegistered }
+
+// EventName คืนค่าชื่อ event
+func (UserLoggedIn) EventName() string { return EventUserLoggedIn }
+
+// EventName คืนค่าชื่อ event
+func (UserUpdated) EventName() string { return EventUserUpdated }
+
+// EventName คืนค่าชื่อ event
+func (UserDeleted) EventName() string { return EventUserDeleted }
+
+// eventTypes สร้างค่าว่างของ event แต่ละชื่อสำหรับอ่าน payload กลับเป็นชนิดเดิม
+var eventTypes = map[string]func() DomainEvent{
+	EventUserRegistered: func() DomainEvent { return &UserRegistered{} },
+	EventUserLoggedIn:   func() DomainEvent { return &UserLoggedIn{} },
+	EventUserUpdated:    func() DomainEvent { return &UserUpdated{} },
+	EventUserDeleted:    func() DomainEvent { return &UserDeleted{} },
+}
+
+// DecodeEvent อ่าน payload ของ event ตามชื่อกลับเป็นชนิดเดิม (pointer เช่น *UserRegistered)
+func DecodeEvent(name string, payload []byte) (DomainEvent, error) {
+	newEvent, ok := eventTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("ไม่รู้จัก event %q", name)
+	}
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// OutboxEvent แถวในตาราง event_outbox
+type OutboxEvent struct {
+	ID            int        `json:"id" db:"id"`                           // ID (Primary Key) ใช้เรียงลำดับ
+	EventID       string     `json:"event_id" db:"event_id"`               // idempotency key ของ event (ไม่ซ้ำ ส่งต่อให้ subscriber)
+	Name          string     `json:"name" db:"name"`                       // ชื่อ event เช่น user.registered
+	Payload       string     `json:"-" db:"payload"`                       // event ในรูปแบบ JSON
+	Status        string     `json:"status" db:"status"`                   // pending, processed หรือ failed
+	Attempts      int        `json:"attempts" db:"attempts"`               // จำนวนรอบที่ส่งแล้ว
+	NextAttemptAt *time.Time `json:"next_attempt_at" db:"next_attempt_at"` // เวลาที่จะส่งครั้งถัดไป (เฉพาะ pending)
+	LastError     *string    `json:"last_error" db:"last_error"`           // ข้อผิดพลาดของ subscriber ครั้งล่าสุด
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`           // เวลาที่ event เกิด
+	ProcessedAt   *time.Time `json:"processed_at" db:"processed_at"`       // เวลาที่ subscriber ทุกตัวประมวลผลสำเร็จ
+}
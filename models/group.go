@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Group โครงสร้างข้อมูลกลุ่ม (ทีม) ของผู้ใช้ในฐานข้อมูล (ตาราง user_groups)
+// กลุ่มอยู่ในองค์กรเดียว และใช้ตรวจสิทธิ์ร่วมกับ role ของผู้ใช้ได้ (middleware.RequireGroup)
+type Group struct {
+	ID             int       `json:"id" db:"id"`                           // ID ของกลุ่ม (Primary Key)
+	OrganizationID *int      `json:"organization_id" db:"organization_id"` // องค์กรของกลุ่ม (NULL = กลุ่มระดับระบบ)
+	Name           string    `json:"name" db:"name"`                       // ชื่อกลุ่ม (ไม่ซ้ำในองค์กรเดียวกัน)
+	Description    string    `json:"description" db:"description"`         // คำอธิบาย
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`           // วันที่สร้าง
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`           // วันที่อัปเดตล่าสุด
+}
+
+// GroupCreate โครงสร้างสำหรับรับข้อมูลการสร้างกลุ่ม
+type GroupCreate struct {
+	Name        string `json:"name" validate:"required,max=100"` // ชื่อกลุ่ม
+	Description string `json:"description" validate:"max=255"`   // คำอธิบาย
+}
+
+// GroupUpdate โครงสร้างสำหรับรับข้อมูลการแก้ไขกลุ่ม (ส่งเฉพาะ field ที่ต้องการเปลี่ยน)
+type GroupUpdate struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=100"`  // ชื่อใหม่
+	Description *string `json:"description" validate:"omitempty,max=255"` // คำอธิบายใหม่
+}
+
+// GroupMember สมาชิกของกลุ่มพร้อมข้อมูลผู้ใช้
+type GroupMember struct {
+	UserID    int       `json:"user_id" db:"user_id"`       // ผู้ใช้
+	Username  string    `json:"username" db:"username"`     // ชื่อผู้ใช้
+	Email     string    `json:"email" db:"email"`           // อีเมล
+	Role      string    `json:"role" db:"role"`             // role ในกลุ่ม (member/owner)
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // วันที่เข้าร่วม
+}
+
+// GroupMemberUpdate โครงสร้างสำหรับรับข้อมูลการเพิ่มสมาชิกหรือเปลี่ยน role ในกลุ่ม
+type GroupMemberUpdate struct {
+	Role string `json:"role" validate:"omitempty,oneof=member owner"` // role ในกลุ่ม (ไม่ระบุ = member) owner จัดการสมาชิกของกลุ่มได้
+}
+
+// GroupMembership กลุ่มที่ผู้ใช้เป็นสมาชิกพร้อม role ในกลุ่มนั้น
+type GroupMembership struct {
+	Group
+	Role string `json:"role" db:"role"` // role ในกลุ่ม (member/owner)
+}
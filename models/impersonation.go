@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ImpersonationRequest โครงสร้างสำหรับรับข้อมูลการเข้าสู่ระบบแทนผู้ใช้ (impersonation)
+type ImpersonationRequest struct {
+	Reason string `json:"reason" validate:"max=255"` // เหตุผล เช่น เลขที่ ticket ของฝ่ายสนับสนุน (บันทึกใน audit log)
+}
+
+// Impersonator Admin ที่เข้าสู่ระบบแทนผู้ใช้
+type Impersonator struct {
+	ID       int    `json:"id"`       // ID ของ Admin
+	Username string `json:"username"` // ชื่อผู้ใช้ของ Admin
+}
+
+// ImpersonationResponse โครงสร้างสำหรับส่ง token ของการ impersonation กลับไป
+// token ไม่ถูกเก็บใน cookie เพื่อไม่ให้ทับ session ของ Admin เอง
+type ImpersonationResponse struct {
+	Token        string       `json:"token"`        // JWT ของผู้ใช้เป้าหมายที่มี claim act
+	ExpiresAt    time.Time    `json:"expires_at"`   // เวลาหมดอายุ (JWT_IMPERSONATION_EXPIRE)
+	User         UserResponse `json:"user"`         // ผู้ใช้เป้าหมาย
+	Impersonator Impersonator `json:"impersonator"` // Admin ที่ออก token
+	AuditID      int          `json:"audit_id"`     // ID ของบันทึกใน audit log
+}
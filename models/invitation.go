@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// สถานะของคำเชิญ (คำนวณจากเวลาที่รับ/เพิกถอน/หมดอายุ ไม่ได้เก็บในฐานข้อมูล)
+const (
+	InvitationPending  = "pending"  // ยังรับคำเชิญได้
+	InvitationAccepted = "accepted" // สร้างบัญชีจากคำเชิญแล้ว
+	InvitationRevoked  = "revoked"  // Admin เพิกถอนแล้ว
+	InvitationExpired  = "expired"  // หมดอายุก่อนถูกรับ
+)
+
+// Invitation โครงสร้างข้อมูลคำเชิญผู้ใช้ในฐานข้อมูล
+// token จริงไม่ถูกเก็บไว้ เก็บเฉพาะ SHA-256 และส่ง token ให้ผู้รับเชิญทางอีเมลเท่านั้น
+type Invitation struct {
+	ID             int        `json:"id" db:"id"`                           // ID ของคำเชิญ (Primary Key)
+	Email          string     `json:"email" db:"email"`                     // อีเมลที่ได้รับเชิญ (บัญชีที่สร้างจะใช้อีเมลนี้)
+	Role           string     `json:"role" db:"role"`                       // role ที่กำหนดไว้ล่วงหน้า (user/admin) เป็น role ในองค์กรเมื่อเป็นคำเชิญขององค์กร
+	TokenHash      string     `json:"-" db:"token_hash"`                    // SHA-256 ของ token (ไม่ส่งกลับไปยัง client)
+	InvitedBy      *int       `json:"invited_by" db:"invited_by"`           // Admin ที่สร้างคำเชิญ (nil = ผู้ใช้ถูกลบไปแล้ว)
+	UserID         *int       `json:"user_id" db:"user_id"`                 // ผู้ใช้ที่สร้างจากคำเชิญนี้
+	OrganizationID *int       `json:"organization_id" db:"organization_id"` // องค์กรที่เชิญเข้าร่วม (nil = คำเชิญระดับระบบ)
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`           // เวลาหมดอายุของ token ปัจจุบัน
+	SentAt         time.Time  `json:"sent_at" db:"sent_at"`                 // เวลาที่ส่งอีเมลคำเชิญครั้งล่าสุด
+	AcceptedAt     *time.Time `json:"accepted_at" db:"accepted_at"`         // เวลาที่รับคำเชิญ
+	RevokedAt      *time.Time `json:"revoked_at" db:"revoked_at"`           // เวลาที่ถูกเพิกถอน
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`           // วันที่สร้าง
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`           // วันที่อัปเดตล่าสุด
+}
+
+// InvitationCreate โครงสร้างสำหรับรับข้อมูลการเชิญผู้ใช้
+type InvitationCreate struct {
+	Email string `json:"email" validate:"required,email,max=100"`    // อีเมลที่จะเชิญ
+	Role  string `json:"role" validate:"omitempty,oneof=user admin"` // role ของบัญชีที่จะสร้าง (ไม่ระบุ = user)
+}
+
+// InvitationAccept โครงสร้างสำหรับรับข้อมูลการรับคำเชิญ
+type InvitationAccept struct {
+	Token    string `json:"token" validate:"required"`                 // token จากอีเมลคำเชิญ
+	Username string `json:"username" validate:"required,min=3,max=20"` // ชื่อผู้ใช้ที่ต้องการ (3-20 ตัวอักษร)
+	Password string `json:"password" validate:"required"`              // รหัสผ่าน (ตรวจตามนโยบาย PASSWORD_*)
+}
+
+// InvitationResponse โครงสร้างสำหรับส่งข้อมูลคำเชิญกลับไป
+type InvitationResponse struct {
+	Invitation
+	Status string `json:"status"` // pending, accepted, revoked หรือ expired
+}
+
+// Status คืนค่าสถานะของคำเชิญ ณ เวลาที่กำหนด
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationAccepted
+	case i.RevokedAt != nil:
+		return InvitationRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationExpired
+	default:
+		return InvitationPending
+	}
+}
+
+// ConvertToResponse แปลง Invitation เป็น InvitationResponse พร้อมสถานะ ณ ปัจจุบัน
+func (i *Invitation) ConvertToResponse() InvitationResponse {
+	return InvitationResponse{Invitation: *i, Status: i.Status(time.Now())}
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LinkedIdentity บัญชีของผู้ให้บริการภายนอก (OIDC/OAuth2) ที่เชื่อมกับผู้ใช้ในระบบ
+// ค้นหาด้วย provider + subject เสมอ เพราะอีเมลที่ provider อาจเปลี่ยนได้
+type LinkedIdentity struct {
+	ID        int       `json:"id" db:"id"`                 // ID (Primary Key)
+	UserID    int       `json:"user_id" db:"user_id"`       // ผู้ใช้ในระบบที่เชื่อมไว้
+	Provider  string    `json:"provider" db:"provider"`     // ชื่อ provider เช่น google, github
+	Subject   string    `json:"subject" db:"subject"`       // ID ของผู้ใช้ที่ provider
+	Email     string    `json:"email" db:"email"`           // อีเมลที่ provider ส่งมาตอนเชื่อมบัญชี
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // วันที่เชื่อมบัญชี
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // วันที่อัปเดตล่าสุด
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// grant type ที่ OAuth2 authorization server รองรับ
+const (
+	GrantAuthorizationCode = "authorization_code" // ผู้ใช้อนุญาตให้แอปเข้าถึงข้อมูลในนามตน (ต้องใช้ PKCE)
+	GrantClientCredentials = "client_credentials" // แอปขอ token ในนามตัวเอง (เฉพาะ confidential client)
+	GrantRefreshToken      = "refresh_token"      // แลก refresh token เป็น access token ใหม่
+)
+
+// OAuthScopes scope ที่แอปภายนอกขอได้ (ชุดเดียวกับ scope ของ API key ยกเว้นการจัดการระบบ)
+var OAuthScopes = []string{ScopeProfileRead, ScopeProfileWrite, ScopeUsersRead, ScopeUsersWrite}
+
+// OAuthClient แอปที่ลงทะเบียนกับ OAuth2 authorization server
+// list ทั้งหมด (redirect URI, grant type, scope) เก็บแบบคั่นด้วยช่องว่างตามรูปแบบของ OAuth2
+type OAuthClient struct {
+	ID           int       `json:"id" db:"id"`                   // ID (Primary Key)
+	ClientID     string    `json:"client_id" db:"client_id"`     // client_id ที่แอปใช้อ้างอิงตัวเอง
+	SecretHash   string    `json:"-" db:"secret_hash"`           // SHA-256 ของ client secret (ว่าง = public client)
+	Name         string    `json:"name" db:"name"`               // ชื่อแอปที่แสดงในหน้าขออนุญาต
+	RedirectURIs string    `json:"-" db:"redirect_uris"`         // redirect URI ที่อนุญาต (ต้องตรงทุกตัวอักษร)
+	GrantTypes   string    `json:"-" db:"grant_types"`           // grant type ที่อนุญาต
+	Scopes       string    `json:"-" db:"scopes"`                // scope สูงสุดที่แอปขอได้
+	FirstParty   bool      `json:"first_party" db:"first_party"` // แอปของเราเอง (ไม่ต้องถามความยินยอมจากผู้ใช้)
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`   // วันที่ลงทะเบียน
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`   // วันที่อัปเดตล่าสุด
+}
+
+// OAuthClientCreate โครงสร้างสำหรับรับข้อมูลการลงทะเบียนแอป
+type OAuthClientCreate struct {
+	Name         string   `json:"name" validate:"required,min=1,max=100"`                                                               // ชื่อแอป
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,url"`                                                          // redirect URI (จำเป็นสำหรับ authorization_code)
+	GrantTypes   []string `json:"grant_types" validate:"required,min=1,dive,oneof=authorization_code client_credentials refresh_token"` // grant type ที่อนุญาต
+	Scopes       []string `json:"scopes" validate:"required,min=1,dive,oneof=profile:read profile:write users:read users:write"`        // scope สูงสุดที่แอปขอได้
+	Public       bool     `json:"public"`                                                                                               // true = public client (SPA/mobile) ไม่มี secret
+	FirstParty   bool     `json:"first_party"`                                                                                          // true = แอปของเราเอง ข้ามหน้าขออนุญาต
+}
+
+// OAuthClientResponse โครงสร้างสำหรับส่งข้อมูลแอปกลับไป
+type OAuthClientResponse struct {
+	OAuthClient
+	RedirectURIs []string `json:"redirect_uris"`           // redirect URI ในรูปแบบ array
+	GrantTypes   []string `json:"grant_types"`             // grant type ในรูปแบบ array
+	Scopes       []string `json:"scopes"`                  // scope ในรูปแบบ array
+	Confidential bool     `json:"confidential"`            // มี client secret หรือไม่
+	ClientSecret string   `json:"client_secret,omitempty"` // client secret แสดงเพียงครั้งเดียวตอนลงทะเบียน
+}
+
+// Confidential ตรวจว่าเป็น confidential client (มี secret) หรือไม่
+func (c *OAuthClient) Confidential() bool {
+	return c.SecretHash != ""
+}
+
+// AllowsGrant ตรวจว่าแอปใช้ grant type ที่กำหนดได้หรือไม่
+func (c *OAuthClient) AllowsGrant(grant string) bool {
+	return containsField(c.GrantTypes, grant)
+}
+
+// AllowsRedirectURI ตรวจว่า redirect URI ตรงกับที่ลงทะเบียนไว้ทุกตัวอักษร
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return uri != "" && containsField(c.RedirectURIs, uri)
+}
+
+// AllowsScopes ตรวจว่า scope ทั้งหมดที่ขออยู่ในขอบเขตที่แอปลงทะเบียนไว้
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsField(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertToResponse แปลง OAuthClient เป็น OAuthClientResponse (ไม่รวม secret)
+func (c *OAuthClient) ConvertToResponse() OAuthClientResponse {
+	return OAuthClientResponse{
+		OAuthClient:  *c,
+		RedirectURIs: strings.Fields(c.RedirectURIs),
+		GrantTypes:   strings.Fields(c.GrantTypes),
+		Scopes:       strings.Fields(c.Scopes),
+		Confidential: c.Confidential(),
+	}
+}
+
+// OAuthCode authorization code ที่รอการแลกเป็น token
+type OAuthCode struct {
+	ID            int       `db:"id"`             // ID (Primary Key)
+	CodeHash      string    `db:"code_hash"`      // SHA-256 ของ code
+	ClientID      string    `db:"client_id"`      // แอปที่ขอ code
+	UserID        int       `db:"user_id"`        // ผู้ใช้ที่อนุญาต
+	RedirectURI   string    `db:"redirect_uri"`   // redirect URI ที่ใช้ตอนขอ (ต้องส่งค่าเดิมตอนแลก)
+	Scopes        string    `db:"scopes"`         // scope ที่ได้รับอนุญาต คั่นด้วยช่องว่าง
+	CodeChallenge string    `db:"code_challenge"` // PKCE code_challenge (S256)
+	ExpiresAt     time.Time `db:"expires_at"`     // เวลาหมดอายุ
+	CreatedAt     time.Time `db:"created_at"`     // เวลาที่ออก code
+}
+
+// OAuthRefreshToken refresh token ที่ออกให้แอป
+type OAuthRefreshToken struct {
+	ID        int        `db:"id"`         // ID (Primary Key)
+	TokenHash string     `db:"token_hash"` // SHA-256 ของ token
+	ClientID  string     `db:"client_id"`  // แอปที่ได้รับ token
+	UserID    int        `db:"user_id"`    // ผู้ใช้ที่อนุญาต
+	Scopes    string     `db:"scopes"`     // scope ที่ได้รับอนุญาต คั่นด้วยช่องว่าง
+	ExpiresAt time.Time  `db:"expires_at"` // เวลาหมดอายุ
+	RevokedAt *time.Time `db:"revoked_at"` // เวลาที่ถูกเพิกถอน (รวมถึงเมื่อถูกใช้แลก token ใหม่แล้ว)
+	CreatedAt time.Time  `db:"created_at"` // เวลาที่ออก token
+}
+
+// Active ตรวจว่า refresh token ยังใช้งานได้ ณ เวลาที่กำหนด
+func (t *OAuthRefreshToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// containsField ตรวจว่า list ที่คั่นด้วยช่องว่างมีค่าที่กำหนดหรือไม่
+func containsField(list, value string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package models
+
+// OAuthAuthorizeRequest พารามิเตอร์ของคำขออนุญาต (RFC 6749 ข้อ 4.1.1 และ PKCE ตาม RFC 7636)
+// GET รับจาก query string ส่วน POST รับจาก body พร้อมผลการตัดสินใจของผู้ใช้ (Approve)
+type OAuthAuthorizeRequest struct {
+	ResponseType        string `query:"response_type" json:"response_type" form:"response_type"`                         // ต้องเป็น code
+	ClientID            string `query:"client_id" json:"client_id" form:"client_id"`                                     // client_id ของแอป
+	RedirectURI         string `query:"redirect_uri" json:"redirect_uri" form:"redirect_uri"`                            // ไม่ระบุได้เมื่อแอปลงทะเบียนไว้เพียงรายการเดียว
+	Scope               string `query:"scope" json:"scope" form:"scope"`                                                 // scope คั่นด้วยช่องว่าง (ไม่ระบุ = ทุก scope ที่แอปลงทะเบียนไว้)
+	State               string `query:"state" json:"state" form:"state"`                                                 // ค่าที่ส่งกลับไปให้แอปตรวจ CSRF
+	CodeChallenge       string `query:"code_challenge" json:"code_challenge" form:"code_challenge"`                      // PKCE code_challenge
+	CodeChallengeMethod string `query:"code_challenge_method" json:"code_challenge_method" form:"code_challenge_method"` // ต้องเป็น S256
+	Approve             bool   `query:"-" json:"approve" form:"approve"`                                                 // POST เท่านั้น: true = ผู้ใช้อนุญาต
+}
+
+// OAuthTokenResponse ผลลัพธ์ของ token endpoint (RFC 6749 ข้อ 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`            // JWT access token
+	TokenType    string `json:"token_type"`              // Bearer เสมอ
+	ExpiresIn    int    `json:"expires_in"`              // อายุของ access token (วินาที)
+	RefreshToken string `json:"refresh_token,omitempty"` // refresh token (ไม่มีสำหรับ client_credentials)
+	Scope        string `json:"scope"`                   // scope ที่ได้รับ คั่นด้วยช่องว่าง
+}
+
+// OAuthErrorResponse ข้อผิดพลาดของ token, introspection และ revocation endpoint (RFC 6749 ข้อ 5.2)
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`                       // รหัสข้อผิดพลาด เช่น invalid_grant
+	ErrorDescription string `json:"error_description,omitempty"` // คำอธิบาย
+}
+
+// OAuthIntrospection ผลลัพธ์ของ introspection endpoint (RFC 7662 ข้อ 2.2)
+// token ที่ใช้ไม่ได้จะมีเพียง active=false
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`               // token ยังใช้งานได้หรือไม่
+	Scope     string `json:"scope,omitempty"`      // scope คั่นด้วยช่องว่าง
+	ClientID  string `json:"client_id,omitempty"`  // แอปที่ได้รับ token
+	Username  string `json:"username,omitempty"`   // ผู้ใช้ที่อนุญาต (ไม่มีสำหรับ client_credentials)
+	TokenType string `json:"token_type,omitempty"` // Bearer หรือ refresh_token
+	Exp       int64  `json:"exp,omitempty"`        // เวลาหมดอายุ (Unix timestamp)
+	Iat       int64  `json:"iat,omitempty"`        // เวลาที่ออก (Unix timestamp)
+	Sub       string `json:"sub,omitempty"`        // ID ผู้ใช้ หรือ client_id สำหรับ client_credentials
+	Iss       string `json:"iss,omitempty"`        // issuer
+	Jti       string `json:"jti,omitempty"`        // ID ของ access token
+}
+
+// OAuthDiscovery เอกสาร metadata ของ authorization server (RFC 8414)
+// เผยแพร่ที่ /.well-known/oauth-authorization-server
+type OAuthDiscovery struct {
+	Issuer                                    string   `json:"issuer"`
+	AuthorizationEndpoint                     string   `json:"authorization_endpoint"`
+	TokenEndpoint                             string   `json:"token_endpoint"`
+	IntrospectionEndpoint                     string   `json:"introspection_endpoint"`
+	RevocationEndpoint                        string   `json:"revocation_endpoint"`
+	ScopesSupported                           []string `json:"scopes_supported"`
+	ResponseTypesSupported                    []string `json:"response_types_supported"`
+	GrantTypesSupported                       []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported             []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported         []string `json:"token_endpoint_auth_methods_supported"`
+	IntrospectionEndpointAuthMethodsSupported []string `json:"introspection_endpoint_auth_methods_supported"`
+	RevocationEndpointAuthMethodsSupported    []string `json:"revocation_endpoint_auth_methods_supported"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Organization โครงสร้างข้อมูลองค์กร (tenant) ในฐานข้อมูล
+// ผู้ใช้หนึ่งคนเป็นสมาชิกได้หลายองค์กร และ Admin ขององค์กรจัดการได้เฉพาะผู้ใช้ในองค์กรเดียวกัน
+type Organization struct {
+	ID        int       `json:"id" db:"id"`                 // ID ขององค์กร (Primary Key)
+	Slug      string    `json:"slug" db:"slug"`             // ชื่อสั้นที่ใช้ใน header X-Tenant และ subdomain เช่น "acme"
+	Name      string    `json:"name" db:"name"`             // ชื่อที่แสดง
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // วันที่สร้าง
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"` // วันที่อัปเดตล่าสุด
+}
+
+// OrganizationCreate โครงสร้างสำหรับรับข้อมูลการสร้างองค์กร
+type OrganizationCreate struct {
+	Slug string `json:"slug" validate:"required,max=50,dns_rfc1035_label"` // ตัวพิมพ์เล็ก ตัวเลข และ - ขึ้นต้นด้วยตัวอักษร
+	Name string `json:"name" validate:"required,max=100"`                  // ชื่อที่แสดง
+}
+
+// Membership โครงสร้างข้อมูลการเป็นสมาชิกขององค์กร
+type Membership struct {
+	ID             int       `json:"id" db:"id"`                           // ID ของการเป็นสมาชิก (Primary Key)
+	OrganizationID int       `json:"organization_id" db:"organization_id"` // องค์กร
+	UserID         int       `json:"user_id" db:"user_id"`                 // ผู้ใช้
+	Role           string    `json:"role" db:"role"`                       // role ในองค์กร (user/admin) แยกจาก role ของระบบใน users
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`           // วันที่เข้าร่วม
+}
+
+// MembershipUpdate โครงสร้างสำหรับรับข้อมูลการเพิ่มสมาชิกหรือเปลี่ยน role ในองค์กร
+type MembershipUpdate struct {
+	Role string `json:"role" validate:"omitempty,oneof=user admin"` // role ในองค์กร (ไม่ระบุ = user)
+}
+
+// OrganizationMembership องค์กรที่ผู้ใช้เป็นสมาชิกพร้อม role ในองค์กรนั้น
+type OrganizationMembership struct {
+	Organization
+	Role string `json:"role" db:"role"` // role ในองค์กร (user/admin)
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Session โครงสร้างข้อมูล session การเข้าสู่ระบบในฐานข้อมูล
+// หนึ่ง session ต่อหนึ่ง JWT ที่ออกให้ผู้ใช้ (ผูกด้วย jti) ใช้แสดงอุปกรณ์ที่เข้าสู่ระบบอยู่และเพิกถอน token ก่อนหมดอายุ
+type Session struct {
+	ID         int        `json:"id" db:"id"`                     // ID ของ session (Primary Key)
+	UserID     int        `json:"user_id" db:"user_id"`           // เจ้าของ session
+	JTI        string     `json:"-" db:"jti"`                     // jti ของ JWT (ไม่ส่งกลับไปยัง client)
+	Device     string     `json:"device" db:"device"`             // ชื่ออุปกรณ์ที่อ่านจาก User-Agent เช่น "Chrome on Windows"
+	UserAgent  string     `json:"user_agent" db:"user_agent"`     // User-Agent ตอนเข้าสู่ระบบ
+	IPAddress  string     `json:"ip_address" db:"ip_address"`     // IP ตอนเข้าสู่ระบบ
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`     // เวลาที่เข้าสู่ระบบ
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"` // เวลาที่ใช้งานล่าสุด (อัปเดตไม่เกินนาทีละครั้ง)
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`     // เวลาหมดอายุของ token
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`     // เวลาที่ถูกเพิกถอน (nil = ยังใช้งานได้)
+
+	ImpersonatorID *int `json:"impersonator_id,omitempty" db:"impersonator_id"` // Admin ที่เข้าสู่ระบบแทนผู้ใช้ (nil = ผู้ใช้เข้าสู่ระบบเอง)
+}
+
+// SessionResponse โครงสร้างสำหรับส่งข้อมูล session กลับไป
+type SessionResponse struct {
+	Session
+	Current bool `json:"current"` // true = session ของ token ที่ใช้เรียก request นี้
+}
+
+// Active ตรวจว่า session ยังใช้งานได้ (ไม่ถูกเพิกถอนและยังไม่หมดอายุ) ณ เวลาที่กำหนด
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -1,9 +1,37 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
+// สถานะบัญชีผู้ใช้ (คอลัมน์ users.status)
+const (
+	UserStatusActive    = "active"    // ใช้งานได้ตามปกติ
+	UserStatusSuspended = "suspended" // ถูกระงับโดย Admin (เข้าสู่ระบบไม่ได้จนกว่าจะยกเลิกหรือถึง suspended_until)
+	UserStatusPending   = "pending"   // ยังไม่เปิดใช้งาน (เข้าสู่ระบบไม่ได้จนกว่า Admin จะเปิดใช้)
+)
+
+// EffectiveStatus คืนค่าสถานะที่มีผล ณ เวลา now: การระงับที่ถึง suspendedUntil แล้วถือว่า active
+// (ไม่ต้องมีงานเบื้องหลังคอยยกเลิกการระงับที่หมดเวลา)
+func EffectiveStatus(status string, suspendedUntil *time.Time, now time.Time) string {
+	if status == UserStatusSuspended && suspendedUntil != nil && !now.Before(*suspendedUntil) {
+		return UserStatusActive
+	}
+	return status
+}
+
+// AccountStatus สถานะบัญชีของผู้ใช้ที่ middleware ตรวจทุก request (อ่านจาก cache)
+type AccountStatus struct {
+	Status         string     `db:"status"`          // สถานะที่บันทึกไว้
+	SuspendedUntil *time.Time `db:"suspended_until"` // เวลาสิ้นสุดการระงับ (NULL = จนกว่าจะยกเลิก)
+}
+
+// Effective คืนค่าสถานะที่มีผล ณ เวลา now
+func (s AccountStatus) Effective(now time.Time) string {
+	return EffectiveStatus(s.Status, s.SuspendedUntil, now)
+}
+
 // User โครงสร้างหลักสำหรับเก็บข้อมูลผู้ใช้ในฐานข้อมูล
 // ใช้ tags สำหรับ JSON serialization, database mapping, และ validation
 type User struct {
@@ -12,8 +40,22 @@ type User struct {
 	Email     string    `json:"email" db:"email" validate:"required,email"`                 // อีเมล (ต้องเป็นรูปแบบอีเมล)
 	Password  string    `json:"password,omitempty" db:"password" validate:"required,min=6"` // รหัสผ่าน (ขั้นต่ำ 6 ตัวอักษร, omitempty = ไม่แสดงใน JSON)
 	Role      string    `json:"role" db:"role"`                                             // สิทธิ์ผู้ใช้ (user/admin)
+	Status    string    `json:"status" db:"status"`                                         // สถานะบัญชี (active/suspended/pending)
 	CreatedAt time.Time `json:"created_at" db:"created_at"`                                 // วันที่สร้างบัญชี
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`                                 // วันที่อัปเดตล่าสุด
+
+	// การระงับบัญชี (กำหนดโดย Admin ที่ POST /users/:id/suspend)
+	SuspensionReason *string    `json:"-" db:"suspension_reason"` // เหตุผลที่ระงับ (ไม่แสดงในคำตอบของการเข้าสู่ระบบ)
+	SuspendedUntil   *time.Time `json:"-" db:"suspended_until"`   // เวลาสิ้นสุดการระงับ (NULL = จนกว่าจะยกเลิก)
+
+	// ข้อมูลโปรไฟล์ (แก้ไขได้ที่ PATCH /auth/profile)
+	DisplayName string  `json:"display_name" db:"display_name"` // ชื่อที่แสดง
+	Bio         string  `json:"bio" db:"bio"`                   // แนะนำตัว
+	Locale      string  `json:"locale" db:"locale"`             // ภาษา (BCP 47 เช่น th-TH)
+	Timezone    string  `json:"timezone" db:"timezone"`         // เขตเวลา (IANA เช่น Asia/Bangkok)
+	Phone       string  `json:"phone" db:"phone"`               // เบอร์โทรศัพท์ (E.164 เช่น +66812345678)
+	Metadata    *string `json:"-" db:"metadata"`                // JSON object ที่ frontend กำหนดเอง (NULL = ไม่มี)
+	AvatarKey   *string `json:"-" db:"avatar_key"`              // key ของรูปโปรไฟล์หลักใน blob storage (NULL = ไม่มีรูป)
 }
 
 // UserLogin โครงสร้างสำหรับรับข้อมูลการเข้าสู่ระบบ
@@ -28,27 +70,93 @@ type UserLogin struct {
 type UserRegister struct {
 	Username string `json:"username" validate:"required,min=3,max=20"` // ชื่อผู้ใช้ (จำเป็น, 3-20 ตัวอักษร)
 	Email    string `json:"email" validate:"required,email"`           // อีเมล (จำเป็น, รูปแบบอีเมล)
-	Password string `json:"password" validate:"required,min=6"`        // รหัสผ่าน (จำเป็น, ขั้นต่ำ 6 ตัวอักษร)
+	Password string `json:"password" validate:"required"`              // รหัสผ่าน (จำเป็น, ตรวจตามนโยบาย PASSWORD_*)
+}
+
+// PasswordChange โครงสร้างสำหรับรับข้อมูลการเปลี่ยนรหัสผ่าน
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password" validate:"required"` // รหัสผ่านปัจจุบัน
+	NewPassword     string `json:"new_password" validate:"required"`     // รหัสผ่านใหม่ (ตรวจตามนโยบาย PASSWORD_*)
+}
+
+// ProfileUpdate โครงสร้างสำหรับรับข้อมูลการแก้ไขโปรไฟล์ (ส่งเฉพาะ field ที่ต้องการเปลี่ยน, ค่าว่าง = ลบค่า)
+type ProfileUpdate struct {
+	DisplayName *string         `json:"display_name" validate:"omitempty,max=100"`                   // ชื่อที่แสดง
+	Bio         *string         `json:"bio" validate:"omitempty,max=500"`                            // แนะนำตัว
+	Locale      *string         `json:"locale" validate:"omitempty,max=35,bcp47_language_tag"`       // ภาษา (BCP 47 เช่น th-TH)
+	Timezone    *string         `json:"timezone" validate:"omitempty,max=64,timezone"`               // เขตเวลา (IANA เช่น Asia/Bangkok)
+	Phone       *string         `json:"phone" validate:"omitempty,e164"`                             // เบอร์โทรศัพท์ (E.164 เช่น +66812345678)
+	Metadata    json.RawMessage `json:"metadata" swaggertype:"object" validate:"omitempty,max=4096"` // JSON object ขนาดไม่เกิน 4 KB (null = ลบ)
 }
 
 // UserResponse โครงสร้างสำหรับส่งข้อมูลผู้ใช้กลับไป
 // ไม่รวมข้อมูลที่อ่อนไหว เช่น รหัสผ่าน, เวลาสร้าง/อัปเดต
 type UserResponse struct {
-	ID       int    `json:"id"`       // ID ผู้ใช้
-	Username string `json:"username"` // ชื่อผู้ใช้
-	Email    string `json:"email"`    // อีเมล
-	Role     string `json:"role"`     // สิทธิ์ผู้ใช้
+	ID          int             `json:"id"`                                      // ID ผู้ใช้
+	Username    string          `json:"username"`                                // ชื่อผู้ใช้
+	Email       string          `json:"email"`                                   // อีเมล
+	Role        string          `json:"role"`                                    // สิทธิ์ผู้ใช้
+	Status      string          `json:"status"`                                  // สถานะบัญชีที่มีผล (active/suspended/pending)
+	Suspension  *Suspension     `json:"suspension,omitempty"`                    // รายละเอียดการระงับ (เฉพาะบัญชีที่ถูกระงับ)
+	DisplayName string          `json:"display_name"`                            // ชื่อที่แสดง
+	Bio         string          `json:"bio"`                                     // แนะนำตัว
+	Locale      string          `json:"locale"`                                  // ภาษา
+	Timezone    string          `json:"timezone"`                                // เขตเวลา
+	Phone       string          `json:"phone"`                                   // เบอร์โทรศัพท์
+	Metadata    json.RawMessage `json:"metadata,omitempty" swaggertype:"object"` // JSON object ที่ frontend กำหนดเอง
+	AvatarURL   string          `json:"avatar_url,omitempty"`                    // ลิงก์รูปโปรไฟล์หลัก (ไม่มีรูป = ไม่ส่ง)
 }
 
 // ConvertToResponse method สำหรับแปลง User เป็น UserResponse
 // ใช้เพื่อซ่อนข้อมูลที่อ่อนไหวก่อนส่งกลับไปยัง client
 // เช่น รหัสผ่าน, เวลาสร้าง/อัปเดต ที่ไม่จำเป็นต้องแสดง
 func (u *User) ConvertToResponse() UserResponse {
-	return UserResponse{
-		ID:       u.ID,       // คัดลอก ID
-		Username: u.Username, // คัดลอกชื่อผู้ใช้
-		Email:    u.Email,    // คัดลอกอีเมล
-		Role:     u.Role,     // คัดลอกสิทธิ์
+	response := UserResponse{
+		ID:       u.ID,                          // คัดลอก ID
+		Username: u.Username,                    // คัดลอกชื่อผู้ใช้
+		Email:    u.Email,                       // คัดลอกอีเมล
+		Role:     u.Role,                        // คัดลอกสิทธิ์
+		Status:   u.EffectiveStatus(time.Now()), // สถานะที่มีผล (การระงับที่หมดเวลาแล้วถือว่า active)
+		// ข้อมูลโปรไฟล์
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		Locale:      u.Locale,
+		Timezone:    u.Timezone,
+		Phone:       u.Phone,
 		// ไม่รวม Password, CreatedAt, UpdatedAt เพื่อความปลอดภัย
+		// AvatarURL ต้องสร้างจาก AvatarKey ด้วย storage ที่ตั้งค่าไว้ (ผู้เรียกกำหนดเอง)
+	}
+	if u.Metadata != nil {
+		response.Metadata = json.RawMessage(*u.Metadata)
 	}
+	if response.Status == UserStatusSuspended {
+		response.Suspension = &Suspension{Until: u.SuspendedUntil}
+		if u.SuspensionReason != nil {
+			response.Suspension.Reason = *u.SuspensionReason
+		}
+	}
+	return response
+}
+
+// EffectiveStatus คืนค่าสถานะบัญชีที่มีผล ณ เวลา now (ดู EffectiveStatus)
+func (u *User) EffectiveStatus(now time.Time) string {
+	return EffectiveStatus(u.Status, u.SuspendedUntil, now)
+}
+
+// Suspension รายละเอียดการระงับบัญชี
+type Suspension struct {
+	Reason string     `json:"reason,omitempty"` // เหตุผลที่ระงับ
+	Until  *time.Time `json:"until,omitempty"`  // เวลาสิ้นสุดการระงับ (ไม่มี = จนกว่าจะยกเลิก)
+}
+
+// UserSuspend โครงสร้างสำหรับรับข้อมูลการระงับบัญชีผู้ใช้
+type UserSuspend struct {
+	Reason string     `json:"reason" validate:"max=255"` // เหตุผลที่ระงับ (ผู้ใช้ที่ถูกระงับไม่เห็น)
+	Until  *time.Time `json:"until"`                     // เวลาสิ้นสุดการระงับ RFC 3339 (ไม่ระบุ = จนกว่าจะยกเลิก)
+}
+
+// AvatarResponse โครงสร้างสำหรับส่งลิงก์รูปโปรไฟล์กลับไปหลังอัปโหลด
+type AvatarResponse struct {
+	AvatarURL  string            `json:"avatar_url"` // ลิงก์รูปโปรไฟล์หลัก (ขนาดแรกใน AVATAR_SIZES)
+	Thumbnails map[string]string `json:"thumbnails"` // ลิงก์ thumbnail ตามขนาด (pixel) เช่น {"64": "/uploads/..."}
 }
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// การกระทำของ POST /users/batch
+const (
+	UserBatchDelete  = "delete"   // ลบผู้ใช้ (ในองค์กร = นำออกจากองค์กร)
+	UserBatchSetRole = "set_role" // เปลี่ยน role (ในองค์กร = role ในองค์กร)
+	UserBatchDisable = "disable"  // ระงับบัญชี (status = suspended) และเพิกถอน session ทั้งหมด
+	UserBatchEnable  = "enable"   // ยกเลิกการระงับหรือเปิดใช้บัญชีที่รอเปิดใช้งาน (status = active)
+)
+
+// ผลของผู้ใช้แต่ละรายใน UserBatchResult.Status
+const (
+	UserBatchOK        = "ok"        // ดำเนินการแล้ว
+	UserBatchUnchanged = "unchanged" // อยู่ในสถานะที่ต้องการอยู่แล้ว
+	UserBatchSkipped   = "skipped"   // ไม่ดำเนินการเพราะติดข้อห้าม (ดู Reason)
+	UserBatchNotFound  = "not_found" // ไม่พบผู้ใช้ในขอบเขตของผู้ดูแล
+)
+
+// UserBatchRequest โครงสร้างสำหรับรับคำสั่งจัดการผู้ใช้หลายรายพร้อมกัน
+// ระบุผู้ใช้ด้วย IDs หรือ Filter อย่างใดอย่างหนึ่ง
+type UserBatchRequest struct {
+	Action string      `json:"action" validate:"required,oneof=delete set_role disable enable"`                 // การกระทำ
+	Role   string      `json:"role" validate:"required_if=Action set_role,omitempty,oneof=user admin"`          // role ใหม่ (เฉพาะ set_role)
+	Reason string      `json:"reason" validate:"excluded_unless=Action disable,max=255"`                        // เหตุผลที่ระงับ (เฉพาะ disable)
+	Until  *time.Time  `json:"until" validate:"excluded_unless=Action disable"`                                 // เวลาสิ้นสุดการระงับ (เฉพาะ disable, ไม่ระบุ = จนกว่าจะยกเลิก)
+	IDs    []int       `json:"ids" validate:"required_without=Filter,excluded_with=Filter,max=1000,dive,min=1"` // ID ของผู้ใช้ (ไม่เกิน 1000)
+	Filter *UserFilter `json:"filter" validate:"required_without=IDs"`                                          // เงื่อนไขเลือกผู้ใช้ (แทน IDs)
+}
+
+// UserFilter เงื่อนไขเลือกผู้ใช้ของ UserBatchRequest (ทุกเงื่อนไขต้องตรงพร้อมกัน และต้องระบุอย่างน้อยหนึ่งเงื่อนไข)
+type UserFilter struct {
+	Role          string     `json:"role" validate:"omitempty,oneof=user admin"`                 // role (ในองค์กร = role ในองค์กร)
+	Status        string     `json:"status" validate:"omitempty,oneof=active suspended pending"` // สถานะบัญชีที่บันทึกไว้
+	EmailDomain   string     `json:"email_domain" validate:"omitempty,fqdn"`                     // โดเมนของอีเมล เช่น spam.example
+	CreatedAfter  *time.Time `json:"created_after"`                                              // สร้างหลังเวลานี้ (RFC 3339)
+	CreatedBefore *time.Time `json:"created_before"`                                             // สร้างก่อนเวลานี้ (RFC 3339)
+}
+
+// IsEmpty ตรวจว่าไม่ได้ระบุเงื่อนไขใดเลย (ห้ามใช้ เพื่อป้องกันการเลือกผู้ใช้ทั้งหมดโดยไม่ตั้งใจ)
+func (f *UserFilter) IsEmpty() bool {
+	return f.Role == "" && f.Status == "" && f.EmailDomain == "" && f.CreatedAfter == nil && f.CreatedBefore == nil
+}
+
+// UserBatchResult ผลของผู้ใช้หนึ่งรายในคำสั่ง batch
+type UserBatchResult struct {
+	ID     int    `json:"id"`               // ID ผู้ใช้
+	Status string `json:"status"`           // ok, unchanged, skipped หรือ not_found
+	Reason string `json:"reason,omitempty"` // เหตุผลที่ข้าม: self (ผู้ดูแลเอง) หรือ last_admin (Admin คนสุดท้าย)
+}
+
+// UserBatchResponse ผลของคำสั่ง batch ทั้งหมด
+type UserBatchResponse struct {
+	Action    string            `json:"action"`    // การกระทำ
+	Matched   int               `json:"matched"`   // จำนวนผู้ใช้ที่ระบุหรือตรงกับ filter
+	Succeeded int               `json:"succeeded"` // จำนวนที่ดำเนินการแล้ว (status = ok)
+	AuditID   int               `json:"audit_id"`  // ID ของบันทึกใน audit log
+	Results   []UserBatchResult `json:"results"`   // ผลรายผู้ใช้
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// UserImportError ข้อผิดพลาดของแถวหนึ่งในไฟล์นำเข้าผู้ใช้ (หนึ่งแถวมีได้หลายรายการ)
+type UserImportError struct {
+	Line    int    `json:"line"`    // บรรทัดในไฟล์ (CSV นับบรรทัดหัวตารางเป็นบรรทัดที่ 1)
+	Field   string `json:"field"`   // field ที่ไม่ผ่าน เช่น email (ว่าง = ทั้งแถว)
+	Code    string `json:"code"`    // รหัสของกฎที่ไม่ผ่าน เช่น required, duplicate, exists
+	Message string `json:"message"` // คำอธิบาย
+}
+
+// UserImportResult รายงานผลการนำเข้าผู้ใช้
+// เมื่อมีข้อผิดพลาดแม้แถวเดียว จะไม่มีผู้ใช้ถูกสร้าง (Created = 0)
+type UserImportResult struct {
+	DryRun  bool              `json:"dry_run"` // true = ตรวจสอบอย่างเดียว ไม่บันทึก
+	Total   int               `json:"total"`   // จำนวนแถวข้อมูลในไฟล์
+	Valid   int               `json:"valid"`   // จำนวนแถวที่ผ่านการตรวจสอบ
+	Created int               `json:"created"` // จำนวนผู้ใช้ที่สร้างแล้ว
+	Errors  []UserImportError `json:"errors"`  // ข้อผิดพลาดรายแถว
+}
+
+// UserExport ข้อมูลผู้ใช้หนึ่งแถวในไฟล์ export (ไม่รวมรหัสผ่านและ metadata)
+type UserExport struct {
+	ID          int       `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	DisplayName string    `json:"display_name"`
+	Locale      string    `json:"locale"`
+	Timezone    string    `json:"timezone"`
+	Phone       string    `json:"phone"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// UserExportColumns หัวตารางของไฟล์ export แบบ CSV (ลำดับเดียวกับ UserExport.Record)
+var UserExportColumns = []string{"id", "username", "email", "role", "status", "display_name", "locale", "timezone", "phone", "created_at"}
+
+// ConvertToExport แปลง User เป็นแถวของไฟล์ export
+func (u *User) ConvertToExport() UserExport {
+	return UserExport{
+		ID:          u.ID,
+		Username:    u.Username,
+		Email:       u.Email,
+		Role:        u.Role,
+		Status:      u.EffectiveStatus(time.Now()),
+		DisplayName: u.DisplayName,
+		Locale:      u.Locale,
+		Timezone:    u.Timezone,
+		Phone:       u.Phone,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// Record คืนค่าแถวของไฟล์ export แบบ CSV ตามลำดับของ UserExportColumns
+func (e UserExport) Record() []string {
+	return []string{
+		strconv.Itoa(e.ID), e.Username, e.Email, e.Role, e.Status, e.DisplayName, e.Locale, e.Timezone, e.Phone,
+		e.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+// UserSearchResult ผู้ใช้หนึ่งรายในผลการค้นหา GET /users/search พร้อมคะแนนและข้อความที่ไฮไลต์
+type UserSearchResult struct {
+	UserResponse
+	Score      float64           `json:"score"`                // คะแนนความตรง 0-1 (ผลลัพธ์เรียงจากมากไปน้อย)
+	Highlights map[string]string `json:"highlights,omitempty"` // field ที่ตรง (username/email/display_name) เป็น HTML ที่ escape แล้ว ครอบส่วนที่ตรงด้วย <mark>
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ชนิด event ที่ webhook สมัครรับได้
+const (
+	WebhookUserRegistered = "user.registered" // บัญชีผู้ใช้ใหม่ถูกสร้าง (ลงทะเบียน, รับคำเชิญ, import หรือ social login ครั้งแรก)
+	WebhookUserLogin      = "user.login"      // ผู้ใช้เข้าสู่ระบบด้วยรหัสผ่านสำเร็จ
+	WebhookUserDeleted    = "user.deleted"    // บัญชีผู้ใช้ถูกลบ (การนำออกจากองค์กรที่ยังเหลือบัญชีไม่นับ)
+	WebhookUserUpdated    = "user.updated"    // โปรไฟล์, รูปโปรไฟล์, role ของระบบ หรือสถานะบัญชีเปลี่ยน
+)
+
+// WebhookEvents ชนิด event ทั้งหมดที่สมัครรับได้
+var WebhookEvents = []string{WebhookUserRegistered, WebhookUserLogin, WebhookUserDeleted, WebhookUserUpdated}
+
+// สถานะของการส่ง webhook หนึ่งรายการ
+const (
+	WebhookDeliveryPending   = "pending"   // รอส่ง (ครั้งแรกหรือรอส่งซ้ำตาม next_attempt_at)
+	WebhookDeliveryDelivered = "delivered" // ปลายทางตอบ 2xx แล้ว
+	WebhookDeliveryFailed    = "failed"    // ส่งไม่สำเร็จครบ WEBHOOK_MAX_ATTEMPTS ครั้ง (ส่งซ้ำเองได้ด้วย redeliver)
+)
+
+// Webhook ปลายทางที่ Admin ลงทะเบียนให้รับ event ของผู้ใช้
+type Webhook struct {
+	ID          int       `json:"id" db:"id"`                   // ID (Primary Key)
+	URL         string    `json:"url" db:"url"`                 // ปลายทางที่รับ POST
+	Description string    `json:"description" db:"description"` // คำอธิบาย เช่น "CRM"
+	Events      string    `json:"-" db:"events"`                // ชนิด event ที่สมัครรับ คั่นด้วยช่องว่าง
+	Secret      string    `json:"-" db:"secret"`                // ใช้เซ็น payload (แสดงเฉพาะตอนสร้างหรือเปลี่ยน secret)
+	Active      bool      `json:"active" db:"active"`           // false = หยุดรับ event ใหม่ชั่วคราว
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`   // วันที่สร้าง
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`   // วันที่อัปเดตล่าสุด
+}
+
+// Subscribes ตรวจว่า webhook สมัครรับ event นี้และยังเปิดใช้อยู่หรือไม่
+func (w *Webhook) Subscribes(event string) bool {
+	return w.Active && containsField(w.Events, event)
+}
+
+// WebhookCreate โครงสร้างสำหรับรับข้อมูลการลงทะเบียน webhook
+type WebhookCreate struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`                                                             // ปลายทาง (http/https)
+	Description string   `json:"description" validate:"max=255"`                                                                   // คำอธิบาย
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=user.registered user.login user.deleted user.updated"` // ชนิด event ที่สมัครรับ
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=255"`                                                       // secret สำหรับเซ็น (ไม่ระบุ = สร้างให้)
+	Active      *bool    `json:"active"`                                                                                           // เปิดใช้ทันที (ไม่ระบุ = true)
+}
+
+// WebhookUpdate โครงสร้างสำหรับรับข้อมูลการแก้ไข webhook (ส่งเฉพาะ field ที่ต้องการเปลี่ยน)
+type WebhookUpdate struct {
+	URL          *string  `json:"url" validate:"omitempty,url,max=2048"`                                                             // ปลายทางใหม่
+	Description  *string  `json:"description" validate:"omitempty,max=255"`                                                          // คำอธิบายใหม่
+	Events       []string `json:"events" validate:"omitempty,min=1,dive,oneof=user.registered user.login user.deleted user.updated"` // ชนิด event ใหม่
+	Active       *bool    `json:"active"`                                                                                            // เปิด/ปิดการรับ event ใหม่
+	RotateSecret bool     `json:"rotate_secret"`                                                                                     // true = สร้าง secret ใหม่ (แสดงใน response ครั้งเดียว)
+}
+
+// WebhookResponse โครงสร้างสำหรับส่งข้อมูล webhook กลับไป
+type WebhookResponse struct {
+	Webhook
+	Events []string `json:"events"`           // ชนิด event ในรูปแบบ array
+	Secret string   `json:"secret,omitempty"` // secret แสดงเฉพาะตอนสร้างหรือเปลี่ยน secret
+}
+
+// ConvertToResponse แปลง Webhook เป็น WebhookResponse (ไม่รวม secret)
+func (w *Webhook) ConvertToResponse() WebhookResponse {
+	return WebhookResponse{Webhook: *w, Events: strings.Fields(w.Events)}
+}
+
+// WebhookDelivery การส่ง event หนึ่งครั้งไปยัง webhook หนึ่ง (แถวใน outbox และบันทึกผลการส่ง)
+type WebhookDelivery struct {
+	ID             int        `json:"id" db:"id"`                           // ID ของการส่ง (ส่งใน header X-Webhook-Delivery)
+	WebhookID      int        `json:"webhook_id" db:"webhook_id"`           // webhook ปลายทาง
+	EventID        string     `json:"event_id" db:"event_id"`               // ID ของ event (เหมือนเดิมเมื่อส่งซ้ำ ใช้ตัดรายการซ้ำ)
+	Event          string     `json:"event" db:"event"`                     // ชนิด event
+	Payload        string     `json:"-" db:"payload"`                       // body ที่ส่ง (JSON)
+	Status         string     `json:"status" db:"status"`                   // pending, delivered หรือ failed
+	Attempts       int        `json:"attempts" db:"attempts"`               // จำนวนครั้งที่ส่งแล้ว
+	NextAttemptAt  *time.Time `json:"next_attempt_at" db:"next_attempt_at"` // เวลาที่จะส่งครั้งถัดไป (เฉพาะ pending)
+	LastAttemptAt  *time.Time `json:"last_attempt_at" db:"last_attempt_at"` // เวลาที่ส่งล่าสุด
+	ResponseStatus *int       `json:"response_status" db:"response_status"` // HTTP status ที่ปลายทางตอบครั้งล่าสุด
+	LastError      *string    `json:"last_error" db:"last_error"`           // ข้อผิดพลาดครั้งล่าสุด
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`           // เวลาที่ event เกิด
+	DeliveredAt    *time.Time `json:"delivered_at" db:"delivered_at"`       // เวลาที่ส่งสำเร็จ
+}
+
+// WebhookDeliveryResponse โครงสร้างสำหรับส่งบันทึกการส่งกลับไปพร้อม payload เป็น JSON
+type WebhookDeliveryResponse struct {
+	WebhookDelivery
+	Payload json.RawMessage `json:"payload" swaggertype:"object"` // body ที่ส่ง
+}
+
+// ConvertToResponse แปลง WebhookDelivery เป็น WebhookDeliveryResponse
+func (d *WebhookDelivery) ConvertToResponse() WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{WebhookDelivery: *d, Payload: json.RawMessage(d.Payload)}
+}
+
+// WebhookPayload body ของทุก webhook
+type WebhookPayload struct {
+	ID        string      `json:"id"`         // ID ของ event (ตรงกับ header X-Webhook-Event-ID)
+	Event     string      `json:"event"`      // ชนิด event เช่น user.registered
+	CreatedAt time.Time   `json:"created_at"` // เวลาที่ event เกิด
+	Data      interface{} `json:"data"`       // domain event ในรูปแบบ JSON เช่น {"user": UserResponse}
+}
@@ -0,0 +1,82 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// flowAudience ค่า aud ของ Flow ที่ถูกเซ็น ป้องกันไม่ให้นำ token ชนิดอื่นมาใช้แทน
+const flowAudience = "oauth-flow"
+
+// Flow ค่าลับของการเข้าสู่ระบบหนึ่งครั้ง ตั้งแต่ /start จนถึง /callback
+// ถูกเซ็นแล้วเก็บใน cookie ของ browser จึงไม่ต้องเก็บสถานะไว้ที่เซิร์ฟเวอร์
+type Flow struct {
+	Provider string `json:"provider"` // provider ที่เริ่ม flow (callback ต้องมาจาก provider เดียวกัน)
+	State    string `json:"state"`    // ป้องกัน CSRF: ต้องตรงกับ ?state= ใน callback
+	Nonce    string `json:"nonce"`    // ผูก id_token กับ flow นี้ (OIDC เท่านั้น)
+	Verifier string `json:"verifier"` // PKCE code verifier
+	jwt.RegisteredClaims
+}
+
+// NewFlow สร้าง state, nonce และ PKCE verifier แบบสุ่มสำหรับ provider ที่กำหนด
+func NewFlow(provider string) (*Flow, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	return &Flow{
+		Provider: provider,
+		State:    state,
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+	}, nil
+}
+
+// Seal เซ็น Flow ด้วย key ที่ได้จาก secret และกำหนดอายุเป็น ttl
+func (f *Flow) Seal(secret string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	f.RegisteredClaims = jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{flowAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, f).SignedString(flowKey(secret))
+}
+
+// OpenFlow ตรวจลายเซ็นและอายุของ Flow ที่ได้จาก Seal
+func OpenFlow(sealed, secret string) (*Flow, error) {
+	flow := &Flow{}
+	_, err := jwt.ParseWithClaims(sealed, flow, func(token *jwt.Token) (interface{}, error) {
+		return flowKey(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(flowAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errors.Join(errors.New("สถานะการเข้าสู่ระบบไม่ถูกต้องหรือหมดอายุ"), err)
+	}
+	return flow, nil
+}
+
+// flowKey แยก key สำหรับเซ็น Flow ออกจาก JWT_SECRET
+// เพื่อไม่ให้ cookie ของ flow ถูกนำไปใช้เป็น access token ได้ (และในทางกลับกัน)
+func flowKey(secret string) []byte {
+	key := sha256.Sum256([]byte("oauth-flow:" + secret))
+	return key[:]
+}
+
+// randomString สร้างค่าสุ่ม 256 bits ในรูปแบบ base64url
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,107 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"golang.org/x/oauth2"
+)
+
+// githubProvider provider สำหรับ GitHub OAuth App
+// GitHub ไม่ออก id_token จึงไม่มี nonce การป้องกัน CSRF และ code injection อาศัย state และ PKCE
+type githubProvider struct {
+	oauth2 oauth2.Config
+	apiURL string
+}
+
+// newGitHubProvider สร้าง provider จากการตั้งค่า (ไม่ต้องติดต่อเครือข่าย)
+func newGitHubProvider(cfg config.OAuthGitHubConfig, redirectURL string) *githubProvider {
+	return &githubProvider{
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     oauth2.Endpoint{AuthURL: cfg.AuthURL, TokenURL: cfg.TokenURL},
+			RedirectURL:  redirectURL,
+			Scopes:       cfg.Scopes,
+		},
+		apiURL: strings.TrimRight(cfg.APIURL, "/"),
+	}
+}
+
+// AuthCodeURL สร้าง URL ของหน้าเข้าสู่ระบบพร้อม PKCE (S256) ไม่ใช้ nonce
+func (p *githubProvider) AuthCodeURL(state, _, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Identify แลก code เป็น access token แล้วอ่านผู้ใช้จาก /user และอีเมลหลักที่ยืนยันแล้วจาก /user/emails
+func (p *githubProvider) Identify(ctx context.Context, code, verifier, _ string) (*Identity, error) {
+	ctx = withHTTPClient(ctx)
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("แลก authorization code ไม่สำเร็จ: %w", err)
+	}
+	client := p.oauth2.Client(ctx, token)
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	}
+	if err := p.get(ctx, client, "/user", &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("GitHub ไม่ได้ส่ง ID ของผู้ใช้กลับมา")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.get(ctx, client, "/user/emails", &emails); err != nil {
+		return nil, err
+	}
+
+	identity := &Identity{
+		Provider: ProviderGitHub,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: user.Login,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+	return identity, nil
+}
+
+// get เรียก GitHub REST API และแปลง JSON ที่ได้ลงใน out
+func (p *githubProvider) get(ctx context.Context, client *http.Client, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("เรียก GitHub API %s ไม่สำเร็จ: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API %s ตอบกลับด้วยสถานะ %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("อ่านผลลัพธ์จาก GitHub API %s ไม่สำเร็จ: %w", path, err)
+	}
+	return nil
+}
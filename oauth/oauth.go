@@ -0,0 +1,118 @@
+// Package oauth รวมการเข้าสู่ระบบผ่านผู้ให้บริการภายนอก (OIDC และ OAuth2)
+// แต่ละ provider แปลงผลการเข้าสู่ระบบเป็น Identity เดียวกัน เพื่อให้ controller
+// จับคู่หรือสร้างผู้ใช้ในระบบได้โดยไม่ต้องรู้รายละเอียดของ provider
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"golang.org/x/oauth2"
+)
+
+// ชื่อ provider ที่รองรับ (ใช้ใน URL /auth/oauth/:provider และคอลัมน์ linked_identities.provider)
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+// httpTimeout เวลาสูงสุดของแต่ละ request ไปยัง provider (discovery, token, userinfo)
+const httpTimeout = 10 * time.Second
+
+var (
+	// ErrUnknownProvider คืนค่าเมื่อไม่รู้จักชื่อ provider
+	ErrUnknownProvider = errors.New("ไม่รู้จักผู้ให้บริการนี้")
+	// ErrProviderDisabled คืนค่าเมื่อ provider ไม่ได้กำหนด CLIENT_ID ไว้
+	ErrProviderDisabled = errors.New("ผู้ให้บริการนี้ยังไม่ได้เปิดใช้งาน")
+)
+
+// Identity ข้อมูลผู้ใช้ที่ได้จาก provider หลังเข้าสู่ระบบสำเร็จ
+type Identity struct {
+	Provider      string // ชื่อ provider เช่น google, github
+	Subject       string // ID ของผู้ใช้ที่ provider (ไม่เปลี่ยนแม้ผู้ใช้เปลี่ยนอีเมล)
+	Email         string // อีเมลของผู้ใช้
+	EmailVerified bool   // provider ยืนยันแล้วว่าผู้ใช้เป็นเจ้าของอีเมล
+	Username      string // ชื่อผู้ใช้ที่แนะนำ (preferred_username หรือ GitHub login) อาจว่าง
+}
+
+// Provider ผู้ให้บริการเข้าสู่ระบบหนึ่งราย
+type Provider interface {
+	// AuthCodeURL สร้าง URL ของหน้าเข้าสู่ระบบที่ provider พร้อม state, nonce และ PKCE challenge
+	AuthCodeURL(state, nonce, verifier string) string
+	// Identify แลก authorization code เป็น token (ด้วย PKCE verifier) แล้วอ่านข้อมูลผู้ใช้
+	// provider แบบ OIDC จะตรวจ id_token และ nonce ด้วย
+	Identify(ctx context.Context, code, verifier, nonce string) (*Identity, error)
+}
+
+// Registry สร้างและเก็บ provider ตามการตั้งค่าปัจจุบัน
+// OIDC provider ต้องอ่าน discovery document จากเครือข่าย จึงสร้างเมื่อถูกใช้ครั้งแรกแล้วเก็บไว้
+// เมื่อการตั้งค่า OAuth ถูก reload จะสร้าง provider ใหม่ทั้งหมด
+type Registry struct {
+	store *config.Store
+
+	mu        sync.Mutex
+	current   config.OAuthConfig  // การตั้งค่าที่ใช้สร้าง provider ใน providers
+	providers map[string]Provider // provider ที่สร้างแล้ว
+}
+
+// NewRegistry สร้าง Registry ที่อ่านการตั้งค่าจาก store
+func NewRegistry(store *config.Store) *Registry {
+	return &Registry{store: store, providers: map[string]Provider{}}
+}
+
+// Provider คืนค่า provider ตามชื่อ
+// คืนค่า ErrUnknownProvider หรือ ErrProviderDisabled เมื่อใช้งานไม่ได้
+func (r *Registry) Provider(ctx context.Context, name string) (Provider, error) {
+	cfg := r.store.Get().OAuth
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !reflect.DeepEqual(r.current, *cfg) {
+		r.current = *cfg
+		r.providers = map[string]Provider{}
+	}
+	if provider, ok := r.providers[name]; ok {
+		return provider, nil
+	}
+
+	var (
+		provider Provider
+		err      error
+	)
+	switch name {
+	case ProviderGoogle:
+		if cfg.Google.ClientID == "" {
+			return nil, ErrProviderDisabled
+		}
+		provider, err = newOIDCProvider(ctx, name, cfg.Google, callbackURL(cfg, name))
+	case ProviderGitHub:
+		if cfg.GitHub.ClientID == "" {
+			return nil, ErrProviderDisabled
+		}
+		provider = newGitHubProvider(cfg.GitHub, callbackURL(cfg, name))
+	default:
+		return nil, ErrUnknownProvider
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.providers[name] = provider
+	return provider, nil
+}
+
+// callbackURL สร้าง redirect URI ที่ต้องลงทะเบียนไว้กับ provider
+func callbackURL(cfg *config.OAuthConfig, provider string) string {
+	return cfg.RedirectBaseURL + "/api/v1/auth/oauth/" + provider + "/callback"
+}
+
+// withHTTPClient กำหนด http.Client ที่มี timeout ให้ไลบรารี oauth2 และ go-oidc ใช้
+func withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: httpTimeout})
+}
@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// oidcProvider provider ที่รองรับ OpenID Connect (Google หรือ mock OIDC server)
+type oidcProvider struct {
+	name     string
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// newOIDCProvider อ่าน discovery document ของ issuer แล้วสร้าง provider
+// ใช้ context ที่ไม่ถูกยกเลิกตาม request เพราะ go-oidc เก็บ context ไว้ใช้ดึง signing keys ภายหลัง
+func newOIDCProvider(ctx context.Context, name string, cfg config.OAuthGoogleConfig, redirectURL string) (*oidcProvider, error) {
+	discovery, cancel := context.WithTimeout(ctx, httpTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(withHTTPClient(discovery), cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถอ่าน OIDC discovery ของ %s: %w", cfg.Issuer, err)
+	}
+	return &oidcProvider{
+		name: name,
+		oauth2: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  redirectURL,
+			Scopes:       cfg.Scopes,
+		},
+		// key set ใช้ context ที่ไม่ผูกกับ request เพื่อให้ดึง signing key ใหม่ได้เมื่อ provider หมุนเวียน key
+		verifier: provider.VerifierContext(withHTTPClient(context.WithoutCancel(ctx)), &oidc.Config{ClientID: cfg.ClientID}),
+	}, nil
+}
+
+// AuthCodeURL สร้าง URL ของหน้าเข้าสู่ระบบพร้อม nonce และ PKCE (S256)
+func (p *oidcProvider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Identify แลก code เป็น token แล้วตรวจลายเซ็น, issuer, audience, อายุ และ nonce ของ id_token
+func (p *oidcProvider) Identify(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	ctx = withHTTPClient(ctx)
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("แลก authorization code ไม่สำเร็จ: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("provider ไม่ได้ส่ง id_token กลับมา")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token ไม่ถูกต้อง: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("nonce ของ id_token ไม่ตรงกับที่ส่งไป")
+	}
+
+	var claims struct {
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("อ่าน claims ของ id_token ไม่สำเร็จ: %w", err)
+	}
+
+	return &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Username:      claims.PreferredUsername,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package password
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// benchmarkCandidates ชุดพารามิเตอร์ที่ใช้เปรียบเทียบ
+// argon2id ตามชุดขั้นต่ำของ OWASP Password Storage Cheat Sheet (หน่วยความจำ x รอบ เทียบเท่ากัน) และชุดที่สองของ RFC 9106
+var benchmarkCandidates = []Hasher{
+	Argon2id{Memory: 7168, Iterations: 5, Parallelism: 1},
+	Argon2id{Memory: 9216, Iterations: 4, Parallelism: 1},
+	Argon2id{Memory: 12288, Iterations: 3, Parallelism: 1},
+	Argon2id{Memory: 19456, Iterations: 2, Parallelism: 1},
+	Argon2id{Memory: 47104, Iterations: 1, Parallelism: 1},
+	Argon2id{Memory: 65536, Iterations: 3, Parallelism: 4},
+	Bcrypt{Cost: 10},
+	Bcrypt{Cost: 11},
+	Bcrypt{Cost: 12},
+	Bcrypt{Cost: 13},
+}
+
+// Benchmark วัดเวลาเฉลี่ยของการ hash หนึ่งครั้งด้วยพารามิเตอร์ที่ตั้งค่าไว้และชุดที่ใช้เปรียบเทียบ แล้วเขียนตารางลง w
+// ใช้เลือกพารามิเตอร์บนเครื่องที่ใช้งานจริง: ยิ่งช้ายิ่งเดารหัสยาก แต่ทุกการเข้าสู่ระบบต้องรอเวลาเท่านี้
+// (และ argon2id ใช้หน่วยความจำตาม m ต่อการเข้าสู่ระบบที่เกิดพร้อมกันหนึ่งครั้ง)
+func Benchmark(w io.Writer, cfg *config.PasswordConfig, rounds int) error {
+	configured := NewHasher(cfg)
+	hashers := append([]Hasher{configured}, benchmarkCandidates...)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ALGORITHM\tPARAMETERS\tTIME/HASH\t")
+	for i, hasher := range hashers {
+		if i > 0 && hasher == configured {
+			continue
+		}
+		elapsed, err := measure(hasher, rounds)
+		if err != nil {
+			return err
+		}
+		note := ""
+		if i == 0 {
+			note = "← การตั้งค่าปัจจุบัน"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", hasher.Algorithm(), describe(hasher), elapsed.Round(time.Millisecond/10), note)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "แนะนำให้เลือกชุดที่ใช้เวลาประมาณ 100-500ms ต่อการ hash บนเครื่อง production")
+	fmt.Fprintln(w, "แล้วกำหนด PASSWORD_ALGORITHM และ PASSWORD_ARGON2_* หรือ PASSWORD_BCRYPT_COST")
+	fmt.Fprintln(w, "hash เดิมของผู้ใช้จะถูก hash ใหม่ด้วยค่าใหม่เมื่อผู้ใช้เข้าสู่ระบบสำเร็จครั้งถัดไป")
+	return nil
+}
+
+// measure คืนค่าเวลาเฉลี่ยของการ hash หนึ่งครั้ง
+func measure(hasher Hasher, rounds int) (time.Duration, error) {
+	if rounds < 1 {
+		rounds = 1
+	}
+	start := time.Now()
+	for i := 0; i < rounds; i++ {
+		if _, err := hasher.Hash("benchmark-Passw0rd"); err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(start) / time.Duration(rounds), nil
+}
+
+// describe แสดงพารามิเตอร์ของ hasher
+func describe(hasher Hasher) string {
+	switch h := hasher.(type) {
+	case Argon2id:
+		return fmt.Sprintf("m=%d KiB (%.0f MiB), t=%d, p=%d", h.Memory, float64(h.Memory)/1024, h.Iterations, h.Parallelism)
+	case Bcrypt:
+		return fmt.Sprintf("cost=%d", h.Cost)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,99 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// prefixLength ความยาวของ prefix ของ SHA-1 (hex) ที่ใช้แบ่งกลุ่มแบบ k-anonymity เหมือน range API ของ Have I Been Pwned
+const prefixLength = 5
+
+// BreachedList รายการ SHA-1 ของรหัสผ่านที่รั่วไหล จัดกลุ่มตาม prefix 5 ตัวแรกของ hash
+// ไฟล์มีหนึ่ง hash ต่อบรรทัดในรูปแบบ <SHA-1 hex 40 ตัว>[:<จำนวนครั้งที่พบ>] (รูปแบบเดียวกับไฟล์ที่ดาวน์โหลดจาก HIBP)
+// บรรทัดว่างและบรรทัดที่ขึ้นต้นด้วย # ถูกข้าม
+// การค้นหาใช้เฉพาะ prefix เพื่อดึงกลุ่มของ suffix แล้วเทียบ suffix ภายในกลุ่ม
+// จึงเปลี่ยนไปใช้บริการภายนอกแบบ k-anonymity ได้โดยไม่ต้องส่ง hash เต็มออกไป
+type BreachedList struct {
+	path    string              // ไฟล์ที่โหลด
+	modTime time.Time           // เวลาแก้ไขไฟล์ตอนโหลด
+	ranges  map[string][]string // prefix → suffix (35 ตัว) ที่เรียงแล้ว
+	count   int                 // จำนวน hash ทั้งหมด
+}
+
+// LoadBreachedList อ่านรายการจากไฟล์
+func LoadBreachedList(path string) (*BreachedList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถเปิดไฟล์รหัสผ่านที่รั่วไหล: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &BreachedList{path: path, modTime: info.ModTime(), ranges: map[string][]string{}}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("%s บรรทัด %d: ต้องเป็น SHA-1 แบบ hex 40 ตัวอักษร", path, line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("%s บรรทัด %d: %w", path, line, err)
+		}
+		prefix := hash[:prefixLength]
+		list.ranges[prefix] = append(list.ranges[prefix], hash[prefixLength:])
+		list.count++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, suffixes := range list.ranges {
+		sort.Strings(suffixes)
+	}
+	return list, nil
+}
+
+// Range คืนค่า suffix ทั้งหมดของ hash ที่ขึ้นต้นด้วย prefix (5 ตัวอักษร hex)
+func (l *BreachedList) Range(prefix string) []string {
+	return l.ranges[strings.ToUpper(prefix)]
+}
+
+// Contains ตรวจว่ารหัสผ่านอยู่ในรายการหรือไม่
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes := l.Range(hash[:prefixLength])
+	i := sort.SearchStrings(suffixes, hash[prefixLength:])
+	return i < len(suffixes) && suffixes[i] == hash[prefixLength:]
+}
+
+// Len คืนค่าจำนวน hash ในรายการ
+func (l *BreachedList) Len() int {
+	return l.count
+}
+
+// stale ตรวจว่ารายการนี้ไม่ตรงกับไฟล์ path ในปัจจุบัน (เปลี่ยนไฟล์หรือไฟล์ถูกแก้ไข)
+func (l *BreachedList) stale(path string) bool {
+	if l.path != path {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err != nil || !info.ModTime().Equal(l.modTime)
+}
@@ -0,0 +1,108 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestLoadBreachedList ตรวจการอ่านไฟล์ตัวอย่าง: ข้ามบรรทัดว่างและ comment, รับ hex ตัวพิมพ์เล็ก และมีหรือไม่มีจำนวนครั้งก็ได้
+func TestLoadBreachedList(t *testing.T) {
+	list, err := LoadBreachedList("testdata/breached.txt")
+	if err != nil {
+		t.Fatalf("LoadBreachedList: %v", err)
+	}
+	if list.Len() != 4 {
+		t.Errorf("Len = %d, ต้องการ 4", list.Len())
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"password", true},
+		{"123456", true}, // บรรทัดที่เป็นตัวพิมพ์เล็กและไม่มีจำนวนครั้ง
+		{"P@ssw0rd!", true},
+		{"Password", false},
+		{"correct horse battery staple", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.password); got != tt.want {
+			t.Errorf("Contains(%q) = %v, ต้องการ %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+// TestBreachedListRange ตรวจการค้นด้วย prefix: คืนค่า suffix ทั้งกลุ่มที่เรียงแล้ว ไม่สนตัวพิมพ์ และกลุ่มที่ไม่มีได้ค่าว่าง
+func TestBreachedListRange(t *testing.T) {
+	list, err := LoadBreachedList("testdata/breached.txt")
+	if err != nil {
+		t.Fatalf("LoadBreachedList: %v", err)
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"5BAA6", []string{"00000000000000000000000000000000000", "1E4C9B93F3F0682250B6CF8331B7EE68FD8"}},
+		{"5baa6", []string{"00000000000000000000000000000000000", "1E4C9B93F3F0682250B6CF8331B7EE68FD8"}},
+		{"7C4A8", []string{"D09CA3762AF61E59520943DC26494F8941B"}},
+		{"FFFFF", nil},
+	}
+	for _, tt := range tests {
+		if got := list.Range(tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Range(%q) = %q, ต้องการ %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+// TestLoadBreachedListErrors ตรวจว่าไฟล์ที่ไม่มีอยู่และบรรทัดที่ไม่ใช่ SHA-1 คืนค่า error
+func TestLoadBreachedListErrors(t *testing.T) {
+	for _, path := range []string{
+		"testdata/missing.txt",
+		"testdata/breached_malformed.txt", // hash สั้นกว่า 40 ตัวอักษร
+		"testdata/breached_badhex.txt",    // ไม่ใช่ hex
+	} {
+		if list, err := LoadBreachedList(path); err == nil {
+			t.Errorf("LoadBreachedList(%q) = %d รายการ, ต้องการ error", path, list.Len())
+		}
+	}
+}
+
+// TestBreachedListStale ตรวจว่ารายการต้องโหลดใหม่เมื่อเปลี่ยนไฟล์หรือไฟล์ถูกแก้ไข
+func TestBreachedListStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	data, err := os.ReadFile("testdata/breached.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadBreachedList(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedList: %v", err)
+	}
+
+	if list.stale(path) {
+		t.Error("รายการที่เพิ่งโหลดไม่ควร stale")
+	}
+	if !list.stale("testdata/breached.txt") {
+		t.Error("รายการของไฟล์อื่นต้อง stale")
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !list.stale(path) {
+		t.Error("รายการต้อง stale เมื่อไฟล์ถูกแก้ไข")
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if !list.stale(path) {
+		t.Error("รายการต้อง stale เมื่อไฟล์ถูกลบ")
+	}
+}
@@ -0,0 +1,190 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ชื่ออัลกอริธึม (ค่าของ PASSWORD_ALGORITHM และ id ใน PHC string)
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// ความยาวของ salt และผลลัพธ์ของ argon2id (bytes) ตามคำแนะนำของ RFC 9106
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// ErrUnknownHash คืนค่าเมื่อ hash ที่เก็บไว้ไม่ใช่รูปแบบที่รู้จัก
+var ErrUnknownHash = errors.New("ไม่รู้จักรูปแบบของ password hash")
+
+// Hasher อัลกอริธึมสำหรับ hash และตรวจรหัสผ่าน
+// hash ถูกเก็บเป็น string ที่บอกอัลกอริธึมและพารามิเตอร์ในตัว (PHC string format สำหรับ argon2id
+// และ modular crypt format $2a$/$2b$ ของ bcrypt) จึงตรวจ hash เก่าได้แม้เปลี่ยนการตั้งค่าแล้ว
+type Hasher interface {
+	// Algorithm คืนค่าชื่ออัลกอริธึม เช่น argon2id
+	Algorithm() string
+	// Hash สร้าง hash ของรหัสผ่านด้วย salt แบบสุ่ม
+	Hash(password string) (string, error)
+	// Verify ตรวจว่ารหัสผ่านตรงกับ hash ของอัลกอริธึมนี้หรือไม่ (ใช้พารามิเตอร์ที่อยู่ใน hash)
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash คืนค่า true เมื่อ hash ใช้อัลกอริธึมอื่นหรือพารามิเตอร์ไม่ตรงกับ Hasher นี้
+	NeedsRehash(encoded string) bool
+}
+
+// NewHasher สร้าง Hasher ตาม PASSWORD_ALGORITHM และพารามิเตอร์ในการตั้งค่า
+func NewHasher(cfg *config.PasswordConfig) Hasher {
+	if cfg.Algorithm == AlgorithmBcrypt {
+		return Bcrypt{Cost: cfg.BcryptCost}
+	}
+	return Argon2id{
+		Memory:      uint32(cfg.Argon2Memory),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	}
+}
+
+// Identify คืนค่าชื่ออัลกอริธึมของ hash ที่เก็บไว้ หรือ "" เมื่อไม่รู้จัก
+func Identify(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$"+AlgorithmArgon2id+"$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt
+	default:
+		return ""
+	}
+}
+
+// Verify ตรวจรหัสผ่านกับ hash ที่เก็บไว้ โดยเลือกอัลกอริธึมจากรูปแบบของ hash
+// คืนค่า false โดยไม่มี error เมื่อรหัสผ่านไม่ตรง
+func Verify(encoded, password string) (bool, error) {
+	switch Identify(encoded) {
+	case AlgorithmArgon2id:
+		return Argon2id{}.Verify(encoded, password)
+	case AlgorithmBcrypt:
+		return Bcrypt{}.Verify(encoded, password)
+	default:
+		return false, ErrUnknownHash
+	}
+}
+
+// Bcrypt hash รหัสผ่านด้วย bcrypt (ใช้เฉพาะ 72 bytes แรกของรหัสผ่าน)
+type Bcrypt struct {
+	Cost int // log2 ของจำนวนรอบ
+}
+
+// Algorithm คืนค่า bcrypt
+func (b Bcrypt) Algorithm() string {
+	return AlgorithmBcrypt
+}
+
+// Hash สร้าง hash ในรูปแบบ $2a$<cost>$<salt><hash>
+func (b Bcrypt) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify ตรวจรหัสผ่านกับ hash ของ bcrypt
+func (b Bcrypt) Verify(encoded, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NeedsRehash คืนค่า true เมื่อ hash ไม่ใช่ bcrypt หรือ cost ไม่เท่ากับ Cost
+func (b Bcrypt) NeedsRehash(encoded string) bool {
+	if Identify(encoded) != AlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
+}
+
+// Argon2id hash รหัสผ่านด้วย argon2id (RFC 9106) ซึ่งใช้หน่วยความจำมากจึงทนต่อการเดารหัสด้วย GPU/ASIC
+type Argon2id struct {
+	Memory      uint32 // หน่วยความจำ (KiB)
+	Iterations  uint32 // จำนวนรอบ
+	Parallelism uint8  // จำนวน thread
+}
+
+// Algorithm คืนค่า argon2id
+func (a Argon2id) Algorithm() string {
+	return AlgorithmArgon2id
+}
+
+// Hash สร้าง hash ในรูปแบบ PHC: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>
+// salt และ hash เข้ารหัสด้วย base64 แบบไม่มี padding
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Iterations, a.Memory, a.Parallelism, argon2KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", AlgorithmArgon2id, argon2.Version, a.Memory, a.Iterations, a.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify ตรวจรหัสผ่านกับ hash ของ argon2id โดยใช้พารามิเตอร์ที่อยู่ใน hash
+func (a Argon2id) Verify(encoded, password string) (bool, error) {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+// NeedsRehash คืนค่า true เมื่อ hash ไม่ใช่ argon2id หรือพารามิเตอร์ไม่ตรงกับการตั้งค่า
+func (a Argon2id) NeedsRehash(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+	return err != nil || params != a || len(key) != argon2KeyLength
+}
+
+// decodeArgon2id แยกพารามิเตอร์, salt และ hash จาก PHC string ของ argon2id
+func decodeArgon2id(encoded string) (Argon2id, []byte, []byte, error) {
+	var params Argon2id
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("ไม่รองรับ argon2 เวอร์ชัน %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, fmt.Errorf("พารามิเตอร์ของ argon2id ไม่ถูกต้อง: %w", err)
+	}
+	// argon2.IDKey panic เมื่อ parallelism เป็น 0
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, fmt.Errorf("พารามิเตอร์ของ argon2id ไม่ถูกต้อง: %q", parts[3])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("salt ของ argon2id ไม่ถูกต้อง: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("hash ของ argon2id ไม่ถูกต้อง: %w", err)
+	}
+	if len(key) == 0 {
+		return params, nil, nil, ErrUnknownHash
+	}
+	return params, salt, key, nil
+}
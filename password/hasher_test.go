@@ -0,0 +1,189 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// พารามิเตอร์ต่ำสุดที่ใช้ในการทดสอบเพื่อให้รันได้เร็ว (ไม่ใช่ค่าที่ควรใช้จริง)
+var (
+	testArgon2id = Argon2id{Memory: 64, Iterations: 1, Parallelism: 1}
+	testBcrypt   = Bcrypt{Cost: bcrypt.MinCost}
+)
+
+// TestHashVerify ตรวจว่า hash ของทุกอัลกอริธึมตรวจกลับได้ด้วย Verify ทั้งแบบระบุและไม่ระบุอัลกอริธึม
+func TestHashVerify(t *testing.T) {
+	for _, hasher := range []Hasher{testArgon2id, testBcrypt} {
+		t.Run(hasher.Algorithm(), func(t *testing.T) {
+			encoded, err := hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if got := Identify(encoded); got != hasher.Algorithm() {
+				t.Errorf("Identify = %q, ต้องการ %q", got, hasher.Algorithm())
+			}
+
+			for _, tt := range []struct {
+				password string
+				want     bool
+			}{
+				{"correct horse", true},
+				{"correct horsE", false},
+				{"", false},
+			} {
+				if ok, err := Verify(encoded, tt.password); err != nil || ok != tt.want {
+					t.Errorf("Verify(%q) = %v, %v ต้องการ %v", tt.password, ok, err, tt.want)
+				}
+				if ok, err := hasher.Verify(encoded, tt.password); err != nil || ok != tt.want {
+					t.Errorf("%s.Verify(%q) = %v, %v ต้องการ %v", hasher.Algorithm(), tt.password, ok, err, tt.want)
+				}
+			}
+
+			// salt แบบสุ่มทำให้รหัสผ่านเดียวกันได้ hash ต่างกัน
+			again, err := hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if again == encoded {
+				t.Error("hash สองครั้งของรหัสผ่านเดียวกันไม่ควรเหมือนกัน")
+			}
+		})
+	}
+}
+
+// TestArgon2idPHC ตรวจว่า PHC string ที่ Hash สร้างอ่านกลับได้เป็นพารามิเตอร์, salt และ hash เดิม
+func TestArgon2idPHC(t *testing.T) {
+	hasher := Argon2id{Memory: 96, Iterations: 2, Parallelism: 3}
+	encoded, err := hasher.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if prefix := "$argon2id$v=19$m=96,t=2,p=3$"; !strings.HasPrefix(encoded, prefix) {
+		t.Errorf("hash = %q, ต้องขึ้นต้นด้วย %q", encoded, prefix)
+	}
+
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		t.Fatalf("decodeArgon2id: %v", err)
+	}
+	if params != hasher {
+		t.Errorf("พารามิเตอร์ = %+v, ต้องการ %+v", params, hasher)
+	}
+	if len(salt) != argon2SaltLength || len(key) != argon2KeyLength {
+		t.Errorf("ความยาว salt/hash = %d/%d, ต้องการ %d/%d", len(salt), len(key), argon2SaltLength, argon2KeyLength)
+	}
+
+	// hash ที่สร้างจากภายนอกด้วยพารามิเตอร์อื่นยังตรวจได้ เพราะพารามิเตอร์อยู่ใน hash
+	if ok, err := testArgon2id.Verify(encoded, "secret"); err != nil || !ok {
+		t.Errorf("Verify ด้วย Argon2id ที่พารามิเตอร์ต่างกัน = %v, %v", ok, err)
+	}
+}
+
+// TestArgon2idMalformed ตรวจว่า PHC string ที่ไม่ถูกต้องคืนค่า error แทนการ panic
+func TestArgon2idMalformed(t *testing.T) {
+	valid, err := testArgon2id.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	parts := strings.Split(valid, "$")
+
+	tests := map[string]string{
+		"empty":            "",
+		"bcrypt":           "$2a$04$abcdefghijklmnopqrstuu",
+		"missing hash":     strings.Join(parts[:5], "$"),
+		"wrong version":    strings.Replace(valid, "v=19", "v=16", 1),
+		"zero parallelism": strings.Replace(valid, "p=1", "p=0", 1),
+		"bad params":       strings.Replace(valid, "m=64,t=1,p=1", "m=x", 1),
+		"bad salt":         strings.Join([]string{"", parts[1], parts[2], parts[3], "!!!", parts[5]}, "$"),
+		"empty hash":       strings.Join([]string{"", parts[1], parts[2], parts[3], parts[4], ""}, "$"),
+	}
+	for name, encoded := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(encoded); err == nil {
+				t.Errorf("decodeArgon2id(%q) ไม่คืนค่า error", encoded)
+			}
+			if ok, err := testArgon2id.Verify(encoded, "secret"); ok || err == nil {
+				t.Errorf("Verify(%q) = %v, %v ต้องการ false และ error", encoded, ok, err)
+			}
+		})
+	}
+
+	if _, err := Verify("plaintext", "plaintext"); !errors.Is(err, ErrUnknownHash) {
+		t.Errorf("Verify ของ hash ที่ไม่รู้จัก = %v, ต้องการ ErrUnknownHash", err)
+	}
+}
+
+// TestNeedsRehash ตรวจว่า hash ที่สร้างด้วยอัลกอริธึมหรือพารามิเตอร์อื่นต้อง hash ใหม่เมื่อเข้าสู่ระบบ
+func TestNeedsRehash(t *testing.T) {
+	argon2Hash, err := testArgon2id.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	bcryptHash, err := testBcrypt.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		current Hasher
+		encoded string
+		want    bool
+	}{
+		{"argon2id same parameters", testArgon2id, argon2Hash, false},
+		{"argon2id memory changed", Argon2id{Memory: 128, Iterations: 1, Parallelism: 1}, argon2Hash, true},
+		{"argon2id iterations changed", Argon2id{Memory: 64, Iterations: 2, Parallelism: 1}, argon2Hash, true},
+		{"argon2id parallelism changed", Argon2id{Memory: 64, Iterations: 1, Parallelism: 2}, argon2Hash, true},
+		{"bcrypt to argon2id", testArgon2id, bcryptHash, true},
+		{"bcrypt same cost", testBcrypt, bcryptHash, false},
+		{"bcrypt cost changed", Bcrypt{Cost: bcrypt.MinCost + 1}, bcryptHash, true},
+		{"argon2id to bcrypt", testBcrypt, argon2Hash, true},
+		{"unknown hash", testArgon2id, "plaintext", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.current.NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash = %v, ต้องการ %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkArgon2id วัดเวลา hash ของ argon2id ด้วยชุดพารามิเตอร์เดียวกับคำสั่ง benchmark
+func BenchmarkArgon2id(b *testing.B) {
+	benchmarkHashers(b, AlgorithmArgon2id)
+}
+
+// BenchmarkBcrypt วัดเวลา hash ของ bcrypt ด้วยชุดพารามิเตอร์เดียวกับคำสั่ง benchmark
+func BenchmarkBcrypt(b *testing.B) {
+	benchmarkHashers(b, AlgorithmBcrypt)
+}
+
+// benchmarkHashers วัดเวลา hash ของทุกชุดพารามิเตอร์ใน benchmarkCandidates ของอัลกอริธึมที่กำหนด
+func benchmarkHashers(b *testing.B, algorithm string) {
+	for _, hasher := range benchmarkCandidates {
+		if hasher.Algorithm() != algorithm {
+			continue
+		}
+		b.Run(benchmarkName(hasher), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := hasher.Hash("correct horse battery staple"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// benchmarkName ชื่อย่อของชุดพารามิเตอร์สำหรับ sub-benchmark เช่น m=19456,t=2,p=1
+func benchmarkName(hasher Hasher) string {
+	if h, ok := hasher.(Argon2id); ok {
+		return fmt.Sprintf("m=%d,t=%d,p=%d", h.Memory, h.Iterations, h.Parallelism)
+	}
+	return describe(hasher)
+}
@@ -0,0 +1,181 @@
+// Package password ตรวจรหัสผ่านใหม่ตามนโยบาย (PASSWORD_*) ก่อนบันทึก
+// ได้แก่ ความยาว, ชนิดตัวอักษร, การมีชื่อผู้ใช้หรืออีเมลอยู่ในรหัสผ่าน และรายการรหัสผ่านที่รั่วไหล
+// การตรวจการใช้รหัสผ่านซ้ำต้องอ่านประวัติจากฐานข้อมูล controller จึงอ่านประวัติแล้วตรวจด้วย Reused
+package password
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// ความยาวสูงสุดของรหัสผ่าน (bytes)
+const (
+	MaxLength       = 1024 // ทุกอัลกอริธึม กันการส่งรหัสผ่านยาวมากเพื่อให้ hash ช้า (DoS)
+	BcryptMaxLength = 72   // เมื่อ PASSWORD_ALGORITHM=bcrypt เพราะ bcrypt ใช้เฉพาะ 72 bytes แรกและปฏิเสธรหัสผ่านที่ยาวกว่านี้
+)
+
+// minUserInfoLength ความยาวขั้นต่ำของชื่อผู้ใช้/ส่วนหน้า @ ของอีเมลที่นำไปตรวจ
+// (ชื่อสั้นมากเช่น "al" จะตรงกับรหัสผ่านทั่วไปโดยบังเอิญ)
+const minUserInfoLength = 3
+
+// รหัสของการละเมิดนโยบาย (ใช้เป็น code ใน errors ของ response)
+const (
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeMissingUpper  = "missing_upper"
+	CodeMissingLower  = "missing_lower"
+	CodeMissingDigit  = "missing_digit"
+	CodeMissingSymbol = "missing_symbol"
+	CodeContainsUser  = "contains_username"
+	CodeContainsEmail = "contains_email"
+	CodeBreached      = "breached"
+	CodeReused        = "reused"
+)
+
+// Violation การละเมิดนโยบายหนึ่งข้อ
+type Violation struct {
+	Code    string // รหัสของกฎ เช่น too_short
+	Message string // คำอธิบายสำหรับแสดงผู้ใช้
+}
+
+// UserInfo ข้อมูลของเจ้าของรหัสผ่านที่ต้องไม่ปรากฏในรหัสผ่าน
+type UserInfo struct {
+	Username string
+	Email    string
+}
+
+// ReusedViolation การละเมิดเมื่อรหัสผ่านใหม่ตรงกับรหัสผ่านที่เคยใช้ history ครั้งล่าสุด
+func ReusedViolation(history int) Violation {
+	return Violation{Code: CodeReused, Message: fmt.Sprintf("ห้ามใช้รหัสผ่านซ้ำกับ %d รหัสผ่านล่าสุด", history)}
+}
+
+// Reused ตรวจว่ารหัสผ่านใหม่ตรงกับ hash ใดใน hashes (รหัสผ่านปัจจุบันและประวัติ)
+// hash แต่ละตัวอาจใช้อัลกอริธึมต่างกัน จึงตรวจด้วย Verify ที่เลือกตามรูปแบบของ hash (hash ที่ไม่รู้จักถือว่าไม่ตรง)
+func Reused(password string, hashes []string) bool {
+	for _, hash := range hashes {
+		if matched, _ := Verify(hash, password); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Checker ตรวจรหัสผ่านตามการตั้งค่าปัจจุบัน (อ่านจาก config.Store ทุกครั้ง)
+// รายการรหัสผ่านที่รั่วไหลถูกโหลดครั้งแรกที่ใช้ และโหลดใหม่เมื่อ PASSWORD_BREACHED_FILE หรือเวลาแก้ไขไฟล์เปลี่ยน
+type Checker struct {
+	store *config.Store
+
+	mu       sync.Mutex
+	breached *BreachedList // รายการที่โหลดไว้ (nil = ยังไม่ได้โหลด)
+}
+
+// NewChecker สร้าง Checker ใหม่
+func NewChecker(store *config.Store) *Checker {
+	return &Checker{store: store}
+}
+
+// Check ตรวจรหัสผ่านใหม่ของผู้ใช้ และคืนค่าการละเมิดทั้งหมด (ว่าง = ผ่าน)
+// คืนค่า error เมื่ออ่านไฟล์รายการรหัสผ่านที่รั่วไหลไม่ได้
+func (pc *Checker) Check(password string, user UserInfo) ([]Violation, error) {
+	cfg := pc.store.Get().Password
+	violations := checkRules(cfg, password, user)
+
+	if cfg.BreachedFile != "" {
+		list, err := pc.breachedList(cfg.BreachedFile)
+		if err != nil {
+			return nil, err
+		}
+		if list.Contains(password) {
+			violations = append(violations, Violation{Code: CodeBreached, Message: "รหัสผ่านนี้อยู่ในรายการรหัสผ่านที่รั่วไหล กรุณาเลือกรหัสผ่านอื่น"})
+		}
+	}
+	return violations, nil
+}
+
+// breachedList คืนค่ารายการจากไฟล์ path โดยโหลดใหม่เมื่อไฟล์เปลี่ยน
+func (pc *Checker) breachedList(path string) (*BreachedList, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if pc.breached != nil && !pc.breached.stale(path) {
+		return pc.breached, nil
+	}
+	list, err := LoadBreachedList(path)
+	if err != nil {
+		return nil, err
+	}
+	pc.breached = list
+	log.Printf("🔐 โหลดรายการรหัสผ่านที่รั่วไหล %d รายการจาก %s", list.Len(), path)
+	return list, nil
+}
+
+// checkRules ตรวจกฎที่ไม่ต้องใช้ข้อมูลภายนอก
+func checkRules(cfg *config.PasswordConfig, password string, user UserInfo) []Violation {
+	var violations []Violation
+	if utf8.RuneCountInString(password) < cfg.MinLength {
+		violations = append(violations, Violation{Code: CodeTooShort, Message: fmt.Sprintf("รหัสผ่านต้องมีอย่างน้อย %d ตัวอักษร", cfg.MinLength)})
+	}
+	if limit := maxLength(cfg.Algorithm); len(password) > limit {
+		violations = append(violations, Violation{Code: CodeTooLong, Message: fmt.Sprintf("รหัสผ่านต้องยาวไม่เกิน %d bytes", limit)})
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if cfg.RequireUpper && !upper {
+		violations = append(violations, Violation{Code: CodeMissingUpper, Message: "รหัสผ่านต้องมีตัวพิมพ์ใหญ่อย่างน้อยหนึ่งตัว"})
+	}
+	if cfg.RequireLower && !lower {
+		violations = append(violations, Violation{Code: CodeMissingLower, Message: "รหัสผ่านต้องมีตัวพิมพ์เล็กอย่างน้อยหนึ่งตัว"})
+	}
+	if cfg.RequireDigit && !digit {
+		violations = append(violations, Violation{Code: CodeMissingDigit, Message: "รหัสผ่านต้องมีตัวเลขอย่างน้อยหนึ่งตัว"})
+	}
+	if cfg.RequireSymbol && !symbol {
+		violations = append(violations, Violation{Code: CodeMissingSymbol, Message: "รหัสผ่านต้องมีสัญลักษณ์อย่างน้อยหนึ่งตัว"})
+	}
+
+	if cfg.DenyUserInfo {
+		lowered := strings.ToLower(password)
+		if containsInfo(lowered, user.Username) {
+			violations = append(violations, Violation{Code: CodeContainsUser, Message: "รหัสผ่านต้องไม่มีชื่อผู้ใช้อยู่ภายใน"})
+		}
+		local, _, _ := strings.Cut(user.Email, "@")
+		if containsInfo(lowered, user.Email) || containsInfo(lowered, local) {
+			violations = append(violations, Violation{Code: CodeContainsEmail, Message: "รหัสผ่านต้องไม่มีอีเมลอยู่ภายใน"})
+		}
+	}
+	return violations
+}
+
+// maxLength ความยาวสูงสุดของรหัสผ่าน (bytes) สำหรับอัลกอริธึมที่ใช้ hash รหัสผ่านใหม่
+func maxLength(algorithm string) int {
+	if algorithm == AlgorithmBcrypt {
+		return BcryptMaxLength
+	}
+	return MaxLength
+}
+
+// containsInfo ตรวจว่ารหัสผ่าน (ตัวพิมพ์เล็กแล้ว) มีข้อมูลของผู้ใช้อยู่ภายในหรือไม่ โดยไม่สนใจตัวพิมพ์
+func containsInfo(lowered, info string) bool {
+	if utf8.RuneCountInString(info) < minUserInfoLength {
+		return false
+	}
+	return strings.Contains(lowered, strings.ToLower(info))
+}
@@ -0,0 +1,150 @@
+package password
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// testPolicy นโยบายที่เปิดทุกกฎ (แก้ไขเฉพาะค่าที่ต่างกันด้วย change)
+func testPolicy(change func(cfg *config.PasswordConfig)) *config.PasswordConfig {
+	cfg := &config.PasswordConfig{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		DenyUserInfo:  true,
+		Algorithm:     AlgorithmArgon2id,
+	}
+	if change != nil {
+		change(cfg)
+	}
+	return cfg
+}
+
+// TestCheckRules ตรวจรหัสของการละเมิดแต่ละกฎ: ความยาว (ตามอัลกอริธึม), ชนิดตัวอักษร และชื่อผู้ใช้/อีเมลในรหัสผ่าน
+func TestCheckRules(t *testing.T) {
+	bcryptPolicy := func(cfg *config.PasswordConfig) { cfg.Algorithm = AlgorithmBcrypt }
+	user := UserInfo{Username: "jsmith", Email: "alice.w@example.com"}
+
+	tests := []struct {
+		name     string
+		cfg      *config.PasswordConfig
+		password string
+		user     UserInfo
+		want     []string
+	}{
+		{"valid", testPolicy(nil), "Tr0ub4dor&3", user, nil},
+		{"too short", testPolicy(nil), "Tr0b&3", user, []string{CodeTooShort}},
+		{"length counts characters", testPolicy(nil), "Ää1!Ää1!", user, nil},
+		{"max length", testPolicy(nil), "Tr0ub4dor&3" + strings.Repeat("x", MaxLength-11), user, nil},
+		{"too long", testPolicy(nil), "Tr0ub4dor&3" + strings.Repeat("x", MaxLength-10), user, []string{CodeTooLong}},
+		{"bcrypt max length", testPolicy(bcryptPolicy), "Tr0ub4dor&3" + strings.Repeat("x", BcryptMaxLength-11), user, nil},
+		{"bcrypt too long", testPolicy(bcryptPolicy), "Tr0ub4dor&3" + strings.Repeat("x", BcryptMaxLength-10), user, []string{CodeTooLong}},
+		{"bcrypt limit in bytes", testPolicy(bcryptPolicy), "Tr0ub4dor&3" + strings.Repeat("ä", 31), user, []string{CodeTooLong}},
+		{"missing upper", testPolicy(nil), "tr0ub4dor&3", user, []string{CodeMissingUpper}},
+		{"missing lower", testPolicy(nil), "TR0UB4DOR&3", user, []string{CodeMissingLower}},
+		{"missing digit", testPolicy(nil), "Troubador&x", user, []string{CodeMissingDigit}},
+		{"missing symbol", testPolicy(nil), "Tr0ub4dor33", user, []string{CodeMissingSymbol}},
+		{"space counts as symbol", testPolicy(nil), "Tr0ub4 dor3", user, nil},
+		{"optional classes", testPolicy(func(cfg *config.PasswordConfig) {
+			cfg.RequireUpper, cfg.RequireDigit, cfg.RequireSymbol = false, false, false
+		}), "troubadors", user, nil},
+		{"every class missing", testPolicy(nil), "        ", user, []string{CodeMissingUpper, CodeMissingLower, CodeMissingDigit}},
+		{"contains username", testPolicy(nil), "xJSmith!2024", user, []string{CodeContainsUser}},
+		{"contains email local part", testPolicy(nil), "Alice.W#2024", user, []string{CodeContainsEmail}},
+		{"contains email", testPolicy(nil), "1!Alice.W@Example.com", user, []string{CodeContainsEmail}},
+		{"short username ignored", testPolicy(nil), "Al!2024xyz", UserInfo{Username: "al", Email: "al@example.com"}, nil},
+		{"user info allowed", testPolicy(func(cfg *config.PasswordConfig) { cfg.DenyUserInfo = false }), "xJSmith!2024", user, nil},
+		{"several violations", testPolicy(nil), "jsmith", user, []string{CodeTooShort, CodeMissingUpper, CodeMissingDigit, CodeMissingSymbol, CodeContainsUser}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(checkRules(tt.cfg, tt.password, tt.user)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkRules(%q) = %v, ต้องการ %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckBreached ตรวจว่า Checker รายงานรหัสผ่านที่อยู่ในไฟล์ PASSWORD_BREACHED_FILE และคืนค่า error เมื่ออ่านไฟล์ไม่ได้
+func TestCheckBreached(t *testing.T) {
+	policy := testPolicy(func(cfg *config.PasswordConfig) {
+		cfg.MinLength = 1
+		cfg.RequireUpper, cfg.RequireLower, cfg.RequireDigit, cfg.RequireSymbol = false, false, false, false
+		cfg.BreachedFile = "testdata/breached.txt"
+	})
+	checker := NewChecker(config.NewStore(&config.Config{Password: policy}, nil))
+
+	for _, tt := range []struct {
+		password string
+		want     []string
+	}{
+		{"password", []string{CodeBreached}},
+		{"P@ssw0rd!", []string{CodeBreached}},
+		{"correct horse battery staple", nil},
+	} {
+		violations, err := checker.Check(tt.password, UserInfo{})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", tt.password, err)
+		}
+		if got := codes(violations); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Check(%q) = %v, ต้องการ %v", tt.password, got, tt.want)
+		}
+	}
+
+	missing := testPolicy(func(cfg *config.PasswordConfig) { cfg.BreachedFile = "testdata/missing.txt" })
+	checker = NewChecker(config.NewStore(&config.Config{Password: missing}, nil))
+	if _, err := checker.Check("Tr0ub4dor&3", UserInfo{}); err == nil {
+		t.Error("Check ด้วยไฟล์ที่ไม่มีอยู่ไม่คืนค่า error")
+	}
+}
+
+// TestReused ตรวจการใช้รหัสผ่านซ้ำกับประวัติที่ hash ด้วยอัลกอริธึมต่างกัน
+func TestReused(t *testing.T) {
+	argon2Hash, err := testArgon2id.Hash("Old-Passw0rd")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	bcryptHash, err := testBcrypt.Hash("Older-Passw0rd")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	history := []string{argon2Hash, "plaintext", bcryptHash}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"Old-Passw0rd", true},
+		{"Older-Passw0rd", true},
+		{"old-passw0rd", false},
+		{"New-Passw0rd", false},
+		{"plaintext", false}, // hash ที่ไม่รู้จักไม่ถูกเทียบแบบข้อความธรรมดา
+	}
+	for _, tt := range tests {
+		if got := Reused(tt.password, history); got != tt.want {
+			t.Errorf("Reused(%q) = %v, ต้องการ %v", tt.password, got, tt.want)
+		}
+	}
+	if Reused("Old-Passw0rd", nil) {
+		t.Error("Reused ของประวัติว่างต้องเป็น false")
+	}
+
+	violation := ReusedViolation(5)
+	if violation.Code != CodeReused || !strings.Contains(violation.Message, "5") {
+		t.Errorf("ReusedViolation(5) = %+v", violation)
+	}
+}
+
+// codes คืนค่ารหัสของการละเมิดตามลำดับ (nil เมื่อไม่มี)
+func codes(violations []Violation) []string {
+	var result []string
+	for _, v := range violations {
+		result = append(result, v.Code)
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// apiKeyColumns คอลัมน์ของตาราง api_keys ที่อ่านเข้าสู่ models.APIKey
+const apiKeyColumns = "id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at"
+
+// APIKeyRepository จัดการข้อมูลในตาราง api_keys
+type APIKeyRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// APIKeyOwner ข้อมูลเจ้าของ key ที่ middleware ใช้กำหนด c.Locals
+type APIKeyOwner struct {
+	Username string `db:"username"` // ชื่อผู้ใช้ของเจ้าของ key
+	Role     string `db:"role"`     // role ปัจจุบันของเจ้าของ key
+}
+
+// NewAPIKeyRepository สร้าง APIKeyRepository ใหม่
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+// Create บันทึก key ใหม่และคืนค่า ID
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) (int, error) {
+	query := "INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at) VALUES (?, ?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt)
+}
+
+// List คืนค่า key ทั้งหมด หรือเฉพาะของผู้ใช้ที่กำหนด (userID > 0) เรียงจากใหม่ไปเก่า
+func (r *APIKeyRepository) List(ctx context.Context, userID int) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	query := "SELECT " + apiKeyColumns + " FROM api_keys"
+	var args []interface{}
+	if userID > 0 {
+		query += " WHERE user_id = ?"
+		args = append(args, userID)
+	}
+	query += " ORDER BY id DESC"
+	if err := r.DB.SelectContext(ctx, &keys, r.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetByID ค้นหา key ตาม ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id int) (*models.APIKey, error) {
+	var key models.APIKey
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE id = ?"
+	if err := r.DB.GetContext(ctx, &key, r.DB.Rebind(query), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &key, nil
+}
+
+// GetByPrefix ค้นหา key ตาม prefix พร้อมข้อมูลเจ้าของ (ใช้ตอนตรวจสอบ key ในแต่ละ request)
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*models.APIKey, *APIKeyOwner, error) {
+	var row struct {
+		models.APIKey
+		APIKeyOwner
+	}
+	query := "SELECT k." + strings.ReplaceAll(apiKeyColumns, ", ", ", k.") + ", u.username, u.role" +
+		" FROM api_keys k JOIN users u ON u.id = k.user_id WHERE k.prefix = ?"
+	if err := r.DB.GetContext(ctx, &row, r.DB.Rebind(query), prefix); err != nil {
+		return nil, nil, notFound(err)
+	}
+	return &row.APIKey, &row.APIKeyOwner, nil
+}
+
+// Update บันทึกชื่อ, scope และเวลาหมดอายุของ key
+func (r *APIKeyRepository) Update(ctx context.Context, key *models.APIKey) error {
+	query := "UPDATE api_keys SET name = ?, scopes = ?, expires_at = ?, updated_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), key.Name, key.Scopes, key.ExpiresAt, time.Now().UTC(), key.ID)
+	return err
+}
+
+// Revoke เพิกถอน key (key ที่ถูกเพิกถอนแล้วจะใช้งานไม่ได้อีก แต่ยังเก็บไว้เพื่อตรวจสอบย้อนหลัง)
+func (r *APIKeyRepository) Revoke(ctx context.Context, id int) error {
+	now := time.Now().UTC()
+	query := "UPDATE api_keys SET revoked_at = ?, updated_at = ? WHERE id = ? AND revoked_at IS NULL"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), now, now, id)
+	return err
+}
+
+// TouchLastUsed อัปเดตเวลาที่ใช้งาน key ล่าสุด
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int, at time.Time) error {
+	query := "UPDATE api_keys SET last_used_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), at.UTC(), id)
+	return err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// auditColumns คอลัมน์ของตาราง audit_logs ที่อ่านเข้าสู่ models.AuditLog
+const auditColumns = "id, organization_id, actor_id, action, details, ip_address, created_at"
+
+// AuditRepository จัดการข้อมูลในตาราง audit_logs
+// เมื่อกำหนด TenantID (ผ่าน ForTenant) จะเห็นเฉพาะบันทึกขององค์กรนั้น
+type AuditRepository struct {
+	DB       *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+	TenantID int      // องค์กรที่จำกัดขอบเขต (0 = ทุกองค์กร สำหรับ Admin ของระบบ)
+}
+
+// NewAuditRepository สร้าง AuditRepository ใหม่
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// ForTenant คืนค่า AuditRepository ที่จำกัดขอบเขตไว้ที่องค์กร tenantID (0 = ทุกองค์กร)
+func (r *AuditRepository) ForTenant(tenantID int) *AuditRepository {
+	return &AuditRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// Record บันทึกการกระทำพร้อมรายละเอียด (แปลงเป็น JSON) และเติม ID กับเวลาที่บันทึกลงใน entry
+func (r *AuditRepository) Record(ctx context.Context, entry *models.AuditLog, details interface{}) error {
+	return recordAudit(ctx, r.DB, entry, details)
+}
+
+// List คืนค่าบันทึกในขอบเขตจากใหม่ไปเก่า ไม่เกิน limit รายการ
+// beforeID มากกว่า 0 จะคืนเฉพาะบันทึกที่เก่ากว่า ID นั้น (ใช้แบ่งหน้าต่อจากรายการสุดท้าย)
+func (r *AuditRepository) List(ctx context.Context, beforeID, limit int) ([]models.AuditLog, error) {
+	logs := []models.AuditLog{}
+	query := "SELECT " + auditColumns + " FROM audit_logs WHERE 1 = 1"
+	var args []interface{}
+	if r.TenantID != 0 {
+		query += " AND organization_id = ?"
+		args = append(args, r.TenantID)
+	}
+	if beforeID > 0 {
+		query += " AND id < ?"
+		args = append(args, beforeID)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	if err := r.DB.SelectContext(ctx, &logs, r.DB.Rebind(query), append(args, limit)...); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// recordAudit เพิ่มแถวใน audit_logs ใช้ได้ทั้งกับ *sqlx.DB และ *sqlx.Tx
+// (บันทึกใน transaction เดียวกับการกระทำ จึงไม่มีการกระทำที่สำเร็จโดยไม่มีบันทึก)
+func recordAudit(ctx context.Context, ext sqlx.ExtContext, entry *models.AuditLog, details interface{}) error {
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return err
+		}
+		text := string(data)
+		entry.Details = &text
+	}
+	entry.CreatedAt = time.Now().UTC()
+	query := "INSERT INTO audit_logs (organization_id, actor_id, action, details, ip_address, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	id, err := database.InsertID(ctx, ext, query, entry.OrganizationID, entry.ActorID, entry.Action, entry.Details, entry.IPAddress, entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// outboxColumns คอลัมน์ของตาราง event_outbox ที่อ่านเข้าสู่ models.OutboxEvent
+const outboxColumns = "id, event_id, name, payload, status, attempts, next_attempt_at, last_error, created_at, processed_at"
+
+// EventRepository จัดการ outbox ของ domain event (event_outbox) และ idempotency key ของ subscriber (event_consumptions)
+type EventRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewEventRepository สร้าง EventRepository ใหม่
+func NewEventRepository(db *sqlx.DB) *EventRepository {
+	return &EventRepository{DB: db}
+}
+
+// Publish บันทึก event ลง outbox ใช้ได้ทั้งกับ *sqlx.DB และ *sqlx.Tx
+// ผู้เรียกควรส่ง transaction ของการเปลี่ยนแปลง เพื่อให้ event ถูกบันทึกก็ต่อเมื่อการเปลี่ยนแปลงสำเร็จเท่านั้น
+func (r *EventRepository) Publish(ctx context.Context, ext sqlx.ExtContext, event models.DomainEvent) error {
+	return publishEvent(ctx, ext, event)
+}
+
+// Due คืนค่า event ที่ถึงเวลาส่งให้ subscriber เรียงตามลำดับที่เกิด ไม่เกิน limit รายการ
+func (r *EventRepository) Due(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	events := []models.OutboxEvent{}
+	query := "SELECT " + outboxColumns + " FROM event_outbox WHERE status = ? AND next_attempt_at <= ? ORDER BY id LIMIT ?"
+	if err := r.DB.SelectContext(ctx, &events, r.DB.Rebind(query), models.OutboxPending, now, limit); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Claim จอง event ที่ถึงเวลา (ณ now) ไว้จนถึง until เพื่อไม่ให้ worker อื่น (หลาย instance) ส่งซ้ำระหว่างที่กำลังส่ง
+// คืนค่า false เมื่อ worker อื่นจองไปแล้วหรือสถานะเปลี่ยนไปแล้ว
+func (r *EventRepository) Claim(ctx context.Context, id int, now, until time.Time) (bool, error) {
+	query := "UPDATE event_outbox SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), until, id, models.OutboxPending, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// RecordAttempt บันทึกผลการส่งหนึ่งรอบ
+// สำเร็จ (processErr เป็น nil) = processed, ไม่สำเร็จและ next ไม่เป็น nil = pending รอส่งซ้ำ, ไม่สำเร็จและ next เป็น nil = failed
+func (r *EventRepository) RecordAttempt(ctx context.Context, id int, now time.Time, processErr error, next *time.Time) error {
+	status := models.OutboxProcessed
+	var lastError *string
+	var processedAt *time.Time
+	if processErr != nil {
+		status = models.OutboxFailed
+		if next != nil {
+			status = models.OutboxPending
+		}
+		text := truncateError(processErr)
+		lastError = &text
+	} else {
+		processedAt = &now
+	}
+	query := "UPDATE event_outbox SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_error = ?, processed_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), status, next, lastError, processedAt, id)
+	return err
+}
+
+// Consumed ตรวจว่า subscriber ประมวลผล event นี้สำเร็จไปแล้วหรือไม่ (อ่านใน transaction ของ subscriber)
+func (r *EventRepository) Consumed(ctx context.Context, tx *sqlx.Tx, subscriber, eventID string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM event_consumptions WHERE subscriber = ? AND event_id = ?"
+	if err := tx.GetContext(ctx, &count, tx.Rebind(query), subscriber, eventID); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// MarkConsumed บันทึกว่า subscriber ประมวลผล event สำเร็จ ใน transaction เดียวกับงานของ subscriber
+// worker สองตัวที่ประมวลผล event เดียวกันพร้อมกันจะชน Primary Key และ rollback ไปหนึ่งตัว
+func (r *EventRepository) MarkConsumed(ctx context.Context, tx *sqlx.Tx, subscriber, eventID string, now time.Time) error {
+	query := "INSERT INTO event_consumptions (subscriber, event_id, processed_at) VALUES (?, ?, ?)"
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), subscriber, eventID, now)
+	return err
+}
+
+// publishEvent บันทึก event ลง outbox พร้อม event_id ใหม่ ให้ส่งในรอบถัดไปของ worker
+func publishEvent(ctx context.Context, ext sqlx.ExtContext, event models.DomainEvent) error {
+	eventID, err := utils.GenerateEventID()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	query := "INSERT INTO event_outbox (event_id, name, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, 0, ?, ?)"
+	_, err = database.InsertID(ctx, ext, query, eventID, event.EventName(), string(payload), models.OutboxPending, now, now)
+	return err
+}
+
+// truncateError คืนค่าข้อความของ err ที่ตัดให้ไม่เกิน maxErrorLength ตัวอักษร (ตามขนาดคอลัมน์ last_error)
+func truncateError(err error) string {
+	text := err.Error()
+	if runes := []rune(text); len(runes) > maxErrorLength {
+		text = string(runes[:maxErrorLength])
+	}
+	return text
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// groupColumns คอลัมน์ของตาราง user_groups ที่อ่านเข้าสู่ models.Group
+const groupColumns = "id, organization_id, name, description, created_at, updated_at"
+
+// ErrGroupExists คืนค่าเมื่อมีกลุ่มที่ใช้ชื่อนี้ในองค์กรเดียวกันอยู่แล้ว
+var ErrGroupExists = errors.New("มีกลุ่มที่ใช้ชื่อนี้ในองค์กรอยู่แล้ว")
+
+// GroupRepository จัดการข้อมูลในตาราง user_groups และ group_members
+// เมื่อกำหนด TenantID (ผ่าน ForTenant) จะสร้างและเห็นเฉพาะกลุ่มขององค์กรนั้น
+// ส่วนกลุ่มระดับระบบ (organization_id = NULL) เห็นได้เฉพาะเมื่อไม่จำกัดองค์กร
+type GroupRepository struct {
+	DB       *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+	TenantID int      // องค์กรที่จำกัดขอบเขต (0 = ทุกองค์กร สำหรับ Admin ของระบบ)
+}
+
+// NewGroupRepository สร้าง GroupRepository ใหม่
+func NewGroupRepository(db *sqlx.DB) *GroupRepository {
+	return &GroupRepository{DB: db}
+}
+
+// ForTenant คืนค่า GroupRepository ที่จำกัดขอบเขตไว้ที่องค์กร tenantID (0 = ทุกองค์กร)
+func (r *GroupRepository) ForTenant(tenantID int) *GroupRepository {
+	return &GroupRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// scope คืนค่าเงื่อนไขจำกัดองค์กรของตาราง user_groups สำหรับต่อท้าย WHERE พร้อม args
+func (r *GroupRepository) scope() (string, []interface{}) {
+	if r.TenantID == 0 {
+		return "", nil
+	}
+	return " AND organization_id = ?", []interface{}{r.TenantID}
+}
+
+// Create บันทึกกลุ่มใหม่ในองค์กรของขอบเขต (ไม่จำกัดองค์กร = กลุ่มระดับระบบ) และคืนค่า ID
+// คืนค่า ErrGroupExists หากชื่อซ้ำกับกลุ่มอื่นในองค์กรเดียวกัน
+func (r *GroupRepository) Create(ctx context.Context, group *models.Group) (int, error) {
+	group.OrganizationID = nil
+	if r.TenantID != 0 {
+		tenantID := r.TenantID
+		group.OrganizationID = &tenantID
+	}
+	if err := r.checkName(ctx, group); err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	query := "INSERT INTO user_groups (organization_id, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, group.OrganizationID, group.Name, group.Description, now, now)
+}
+
+// List คืนค่ากลุ่มทั้งหมดในขอบเขต เรียงตามชื่อ
+func (r *GroupRepository) List(ctx context.Context) ([]models.Group, error) {
+	groups := []models.Group{}
+	cond, args := r.scope()
+	query := "SELECT " + groupColumns + " FROM user_groups WHERE 1 = 1" + cond + " ORDER BY name, id"
+	if err := r.DB.SelectContext(ctx, &groups, r.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetByID ค้นหากลุ่มในขอบเขตตาม ID
+func (r *GroupRepository) GetByID(ctx context.Context, id int) (*models.Group, error) {
+	var group models.Group
+	cond, args := r.scope()
+	query := "SELECT " + groupColumns + " FROM user_groups WHERE id = ?" + cond
+	if err := r.DB.GetContext(ctx, &group, r.DB.Rebind(query), append([]interface{}{id}, args...)...); err != nil {
+		return nil, notFound(err)
+	}
+	return &group, nil
+}
+
+// Update บันทึกชื่อและคำอธิบายของกลุ่ม (ไม่ย้ายองค์กร)
+// คืนค่า ErrGroupExists หากชื่อใหม่ซ้ำกับกลุ่มอื่นในองค์กรเดียวกัน
+func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error {
+	if err := r.checkName(ctx, group); err != nil {
+		return err
+	}
+	query := "UPDATE user_groups SET name = ?, description = ?, updated_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), group.Name, group.Description, time.Now().UTC(), group.ID)
+	return err
+}
+
+// Delete ลบกลุ่มในขอบเขตพร้อมสมาชิกทั้งหมด (ON DELETE CASCADE) คืนค่า false หากไม่พบ
+func (r *GroupRepository) Delete(ctx context.Context, id int) (bool, error) {
+	cond, args := r.scope()
+	query := "DELETE FROM user_groups WHERE id = ?" + cond
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), append([]interface{}{id}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Members คืนค่าสมาชิกของกลุ่มพร้อมข้อมูลผู้ใช้ เรียงตามลำดับที่เข้าร่วม
+func (r *GroupRepository) Members(ctx context.Context, groupID int) ([]models.GroupMember, error) {
+	members := []models.GroupMember{}
+	query := "SELECT gm.user_id, u.username, u.email, gm.role, gm.created_at FROM group_members gm" +
+		" JOIN users u ON u.id = gm.user_id WHERE gm.group_id = ? ORDER BY gm.id"
+	if err := r.DB.SelectContext(ctx, &members, r.DB.Rebind(query), groupID); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// MemberRole คืนค่า role ของผู้ใช้ในกลุ่ม หรือ ErrNotFound หากไม่ได้เป็นสมาชิก
+func (r *GroupRepository) MemberRole(ctx context.Context, groupID, userID int) (string, error) {
+	var role string
+	query := "SELECT role FROM group_members WHERE group_id = ? AND user_id = ?"
+	if err := r.DB.GetContext(ctx, &role, r.DB.Rebind(query), groupID, userID); err != nil {
+		return "", notFound(err)
+	}
+	return role, nil
+}
+
+// SetMember เพิ่มผู้ใช้เข้ากลุ่ม หรือเปลี่ยน role หากเป็นสมาชิกอยู่แล้ว
+// คืนค่า true เมื่อเพิ่มสมาชิกใหม่
+func (r *GroupRepository) SetMember(ctx context.Context, groupID, userID int, role string) (bool, error) {
+	query := "UPDATE group_members SET role = ? WHERE group_id = ? AND user_id = ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), role, groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected > 0 {
+		return false, err
+	}
+	query = "INSERT INTO group_members (group_id, user_id, role, created_at) VALUES (?, ?, ?, ?)"
+	if _, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), groupID, userID, role, time.Now().UTC()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveMember นำผู้ใช้ออกจากกลุ่ม คืนค่า false หากไม่ได้เป็นสมาชิก
+func (r *GroupRepository) RemoveMember(ctx context.Context, groupID, userID int) (bool, error) {
+	query := "DELETE FROM group_members WHERE group_id = ? AND user_id = ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), groupID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ForUser คืนค่ากลุ่มในขอบเขตที่ผู้ใช้เป็นสมาชิกพร้อม role เรียงตามชื่อกลุ่ม
+func (r *GroupRepository) ForUser(ctx context.Context, userID int) ([]models.GroupMembership, error) {
+	groups := []models.GroupMembership{}
+	cond, args := r.scope()
+	query := "SELECT g.id, g.organization_id, g.name, g.description, g.created_at, g.updated_at, gm.role" +
+		" FROM group_members gm JOIN user_groups g ON g.id = gm.group_id WHERE gm.user_id = ?" + cond + " ORDER BY g.name, g.id"
+	if err := r.DB.SelectContext(ctx, &groups, r.DB.Rebind(query), append([]interface{}{userID}, args...)...); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// InAnyGroup ตรวจว่าผู้ใช้เป็นสมาชิกของกลุ่มในขอบเขตที่มีชื่ออยู่ใน names อย่างน้อยหนึ่งกลุ่มหรือไม่
+func (r *GroupRepository) InAnyGroup(ctx context.Context, userID int, names []string) (bool, error) {
+	if len(names) == 0 {
+		return false, nil
+	}
+	cond, args := r.scope()
+	query, inArgs, err := sqlx.In("SELECT COUNT(*) FROM group_members gm JOIN user_groups g ON g.id = gm.group_id"+
+		" WHERE gm.user_id = ? AND g.name IN (?)"+cond, append([]interface{}{userID, names}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	var count int
+	if err := r.DB.GetContext(ctx, &count, r.DB.Rebind(query), inArgs...); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// checkName คืนค่า ErrGroupExists หากมีกลุ่มอื่นในองค์กรเดียวกับ group ที่ใช้ชื่อเดียวกัน
+func (r *GroupRepository) checkName(ctx context.Context, group *models.Group) error {
+	var count int
+	query := "SELECT COUNT(*) FROM user_groups WHERE name = ? AND id <> ? AND organization_id IS NULL"
+	args := []interface{}{group.Name, group.ID}
+	if group.OrganizationID != nil {
+		query = "SELECT COUNT(*) FROM user_groups WHERE name = ? AND id <> ? AND organization_id = ?"
+		args = append(args, *group.OrganizationID)
+	}
+	if err := r.DB.GetContext(ctx, &count, r.DB.Rebind(query), args...); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrGroupExists
+	}
+	return nil
+}
+
+// removeFromGroups นำผู้ใช้ออกจากกลุ่มขององค์กร orgID (0 = ทุกกลุ่ม) ใช้ได้ทั้งกับ *sqlx.DB และ *sqlx.Tx
+// ใช้ตอนลบผู้ใช้หรือนำผู้ใช้ออกจากองค์กร เพื่อไม่ให้ยังมีสิทธิ์ผ่านกลุ่มขององค์กรที่ออกไปแล้ว
+func removeFromGroups(ctx context.Context, ext sqlx.ExtContext, userID, orgID int) error {
+	query := "DELETE FROM group_members WHERE user_id = ?"
+	args := []interface{}{userID}
+	if orgID != 0 {
+		query += " AND group_id IN (SELECT id FROM user_groups WHERE organization_id = ?)"
+		args = append(args, orgID)
+	}
+	_, err := ext.ExecContext(ctx, ext.Rebind(query), args...)
+	return err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxUsernameAttempts จำนวนครั้งสูงสุดที่ลองเติมตัวเลขท้ายชื่อผู้ใช้เมื่อชื่อซ้ำ
+const maxUsernameAttempts = 100
+
+// IdentityRepository จัดการข้อมูลในตาราง linked_identities
+type IdentityRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewIdentityRepository สร้าง IdentityRepository ใหม่
+func NewIdentityRepository(db *sqlx.DB) *IdentityRepository {
+	return &IdentityRepository{DB: db}
+}
+
+// FindUser ค้นหาผู้ใช้ที่เชื่อมกับบัญชี provider + subject ไว้แล้ว
+func (r *IdentityRepository) FindUser(ctx context.Context, provider, subject string) (*models.User, error) {
+	var user models.User
+	query := "SELECT " + userColumns + " FROM linked_identities i" +
+		" JOIN users u ON u.id = i.user_id WHERE i.provider = ? AND i.subject = ?"
+	if err := r.DB.GetContext(ctx, &user, r.DB.Rebind(query), provider, subject); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// LinkByEmail เชื่อมบัญชี provider กับผู้ใช้ที่มีอีเมลเดียวกัน (ไม่สนตัวพิมพ์เล็ก-ใหญ่)
+// หากยังไม่มีผู้ใช้ที่ใช้อีเมลนี้ จะสร้าง newUser ขึ้นใหม่ (ชื่อผู้ใช้ที่ซ้ำจะถูกเติมตัวเลขท้าย)
+// หรือคืนค่า ErrNotFound เมื่อ newUser เป็น nil (ไม่อนุญาตให้สร้างบัญชีใหม่)
+// ทำทั้งหมดใน transaction เดียว (ผู้ใช้ที่สร้างใหม่มี event UserRegistered ใน outbox) คืนค่าผู้ใช้ที่ถูกเชื่อม และ true เมื่อเป็นผู้ใช้ที่สร้างใหม่
+// ผู้เรียกต้องตรวจแล้วว่า provider ยืนยันอีเมลนี้แล้ว
+func (r *IdentityRepository) LinkByEmail(ctx context.Context, identity *models.LinkedIdentity, newUser *models.User) (*models.User, bool, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	var user models.User
+	created := false
+	query := "SELECT " + userColumns + " FROM users u WHERE LOWER(u.email) = LOWER(?)"
+	err = tx.GetContext(ctx, &user, tx.Rebind(query), identity.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows) && newUser == nil:
+		return nil, false, ErrNotFound
+	case errors.Is(err, sql.ErrNoRows):
+		user = *newUser
+		if user.Username, err = uniqueUsername(ctx, tx, user.Username); err != nil {
+			return nil, false, err
+		}
+		now := time.Now()
+		query = "INSERT INTO users (username, email, password, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+		if user.ID, err = database.InsertID(ctx, tx, query, user.Username, user.Email, user.Password, user.Role, now, now); err != nil {
+			return nil, false, err
+		}
+		user.Password = ""
+		created = true
+		if err := publishUserRegistered(ctx, tx, user.ID); err != nil {
+			return nil, false, err
+		}
+	case err != nil:
+		return nil, false, err
+	}
+
+	now := time.Now()
+	query = "INSERT INTO linked_identities (user_id, provider, subject, email, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+	if _, err := database.InsertID(ctx, tx, query, user.ID, identity.Provider, identity.Subject, identity.Email, now, now); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return &user, created, nil
+}
+
+// uniqueUsername คืนค่า base หากยังไม่มีผู้ใช้ชื่อนี้ มิฉะนั้นเติม _2, _3, ... ท้ายชื่อ
+// (ตัดชื่อให้ยาวไม่เกิน 20 ตัวอักษรตามกฎของ UserRegister)
+func uniqueUsername(ctx context.Context, tx *sqlx.Tx, base string) (string, error) {
+	query := tx.Rebind("SELECT COUNT(*) FROM users WHERE username = ?")
+	candidate := base
+	for i := 2; i <= maxUsernameAttempts+1; i++ {
+		var count int
+		if err := tx.GetContext(ctx, &count, query, candidate); err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		suffix := fmt.Sprintf("_%d", i)
+		candidate = base[:min(len(base), 20-len(suffix))] + suffix
+	}
+	return "", fmt.Errorf("ไม่สามารถหาชื่อผู้ใช้ที่ไม่ซ้ำจาก %q ได้", base)
+}
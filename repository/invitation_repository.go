@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// invitationColumns คอลัมน์ของตาราง invitations ที่อ่านเข้าสู่ models.Invitation
+const invitationColumns = "id, email, role, token_hash, invited_by, user_id, organization_id, expires_at, sent_at, accepted_at, revoked_at, created_at, updated_at"
+
+// ErrInvitationClosed คืนค่าเมื่อรับคำเชิญที่ถูกรับไปแล้ว ถูกเพิกถอน หรือหมดอายุ
+var ErrInvitationClosed = errors.New("คำเชิญถูกใช้ไปแล้ว ถูกเพิกถอน หรือหมดอายุ")
+
+// ErrUserExists คืนค่าเมื่อมีผู้ใช้ที่ใช้อีเมลหรือชื่อผู้ใช้นี้อยู่แล้ว
+var ErrUserExists = errors.New("มีผู้ใช้นี้อยู่แล้ว")
+
+// InvitationRepository จัดการข้อมูลในตาราง invitations
+// เมื่อกำหนด TenantID (ผ่าน ForTenant) จะสร้างและเห็นเฉพาะคำเชิญขององค์กรนั้น
+type InvitationRepository struct {
+	DB       *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+	TenantID int      // องค์กรที่จำกัดขอบเขต (0 = ทุกองค์กร สำหรับ Admin ของระบบ)
+}
+
+// NewInvitationRepository สร้าง InvitationRepository ใหม่
+func NewInvitationRepository(db *sqlx.DB) *InvitationRepository {
+	return &InvitationRepository{DB: db}
+}
+
+// ForTenant คืนค่า InvitationRepository ที่จำกัดขอบเขตไว้ที่องค์กร tenantID (0 = ทุกองค์กร)
+func (r *InvitationRepository) ForTenant(tenantID int) *InvitationRepository {
+	return &InvitationRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// scope คืนค่าเงื่อนไขจำกัดองค์กรสำหรับต่อท้าย WHERE พร้อม args
+func (r *InvitationRepository) scope() (string, []interface{}) {
+	if r.TenantID == 0 {
+		return "", nil
+	}
+	return " AND organization_id = ?", []interface{}{r.TenantID}
+}
+
+// Create บันทึกคำเชิญใหม่และคืนค่า ID (คำเชิญเป็นขององค์กรในขอบเขต ถ้ามี)
+func (r *InvitationRepository) Create(ctx context.Context, inv *models.Invitation) (int, error) {
+	now := time.Now().UTC()
+	if r.TenantID != 0 {
+		tenantID := r.TenantID
+		inv.OrganizationID = &tenantID
+	}
+	query := "INSERT INTO invitations (email, role, token_hash, invited_by, organization_id, expires_at, sent_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, inv.Email, inv.Role, inv.TokenHash, inv.InvitedBy, inv.OrganizationID, inv.ExpiresAt.UTC(), now, now, now)
+}
+
+// List คืนค่าคำเชิญทั้งหมดในขอบเขต เรียงจากใหม่ไปเก่า
+func (r *InvitationRepository) List(ctx context.Context) ([]models.Invitation, error) {
+	invitations := []models.Invitation{}
+	cond, args := r.scope()
+	query := "SELECT " + invitationColumns + " FROM invitations WHERE 1 = 1" + cond + " ORDER BY id DESC"
+	if err := r.DB.SelectContext(ctx, &invitations, r.DB.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// GetByID ค้นหาคำเชิญในขอบเขตตาม ID
+func (r *InvitationRepository) GetByID(ctx context.Context, id int) (*models.Invitation, error) {
+	var inv models.Invitation
+	cond, args := r.scope()
+	query := "SELECT " + invitationColumns + " FROM invitations WHERE id = ?" + cond
+	if err := r.DB.GetContext(ctx, &inv, r.DB.Rebind(query), append([]interface{}{id}, args...)...); err != nil {
+		return nil, notFound(err)
+	}
+	return &inv, nil
+}
+
+// GetByTokenHash ค้นหาคำเชิญจาก hash ของ token
+func (r *InvitationRepository) GetByTokenHash(ctx context.Context, hash string) (*models.Invitation, error) {
+	var inv models.Invitation
+	query := "SELECT " + invitationColumns + " FROM invitations WHERE token_hash = ?"
+	if err := r.DB.GetContext(ctx, &inv, r.DB.Rebind(query), hash); err != nil {
+		return nil, notFound(err)
+	}
+	return &inv, nil
+}
+
+// HasPending ตรวจว่ามีคำเชิญที่ยังรับได้สำหรับอีเมลนี้อยู่หรือไม่ (ไม่สนตัวพิมพ์เล็ก-ใหญ่)
+func (r *InvitationRepository) HasPending(ctx context.Context, email string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM invitations WHERE LOWER(email) = LOWER(?)" +
+		" AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?"
+	if err := r.DB.GetContext(ctx, &count, r.DB.Rebind(query), email, time.Now().UTC()); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Renew เปลี่ยน token และเวลาหมดอายุของคำเชิญที่ยังไม่ถูกรับหรือเพิกถอน (ใช้ตอนส่งคำเชิญใหม่)
+// token เดิมจะใช้ไม่ได้อีก คืนค่า false หากคำเชิญถูกรับหรือเพิกถอนไปแล้ว
+func (r *InvitationRepository) Renew(ctx context.Context, id int, tokenHash string, expiresAt time.Time) (bool, error) {
+	now := time.Now().UTC()
+	cond, args := r.scope()
+	query := "UPDATE invitations SET token_hash = ?, expires_at = ?, sent_at = ?, updated_at = ?" +
+		" WHERE id = ? AND accepted_at IS NULL AND revoked_at IS NULL" + cond
+	args = append([]interface{}{tokenHash, expiresAt.UTC(), now, now, id}, args...)
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Revoke เพิกถอนคำเชิญที่ยังไม่ถูกรับ คืนค่า false หากคำเชิญถูกรับหรือเพิกถอนไปแล้ว
+func (r *InvitationRepository) Revoke(ctx context.Context, id int) (bool, error) {
+	now := time.Now().UTC()
+	cond, args := r.scope()
+	query := "UPDATE invitations SET revoked_at = ?, updated_at = ? WHERE id = ? AND accepted_at IS NULL AND revoked_at IS NULL" + cond
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), append([]interface{}{now, now, id}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Accept รับคำเชิญ: สร้างผู้ใช้ด้วยอีเมลและ role ของคำเชิญ บันทึกรหัสผ่านแรกลงประวัติ (เก็บ keep รายการ)
+// บันทึก event UserRegistered และปิดคำเชิญใน transaction เดียวกัน คำเชิญขององค์กรสร้างผู้ใช้ role user ของระบบ
+// และเพิ่มเป็นสมาชิกขององค์กรด้วย role ของคำเชิญ คำเชิญจึงถูกใช้ได้เพียงครั้งเดียวแม้มี request พร้อมกัน
+// คืนค่า ErrInvitationClosed หากคำเชิญใช้ไม่ได้แล้ว และ ErrUserExists หากอีเมลหรือชื่อผู้ใช้ซ้ำ
+func (r *InvitationRepository) Accept(ctx context.Context, inv *models.Invitation, user *models.User, keep int) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	query := "UPDATE invitations SET accepted_at = ?, updated_at = ?" +
+		" WHERE id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?"
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), now, now, inv.ID, now)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrInvitationClosed
+	}
+
+	var count int
+	query = "SELECT COUNT(*) FROM users WHERE LOWER(email) = LOWER(?) OR username = ?"
+	if err := tx.GetContext(ctx, &count, tx.Rebind(query), inv.Email, user.Username); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrUserExists
+	}
+
+	user.Email, user.Role, user.Status = inv.Email, inv.Role, models.UserStatusActive
+	if inv.OrganizationID != nil {
+		user.Role = "user"
+	}
+	user.CreatedAt, user.UpdatedAt = now, now
+	query = "INSERT INTO users (username, email, password, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+	if user.ID, err = database.InsertID(ctx, tx, query, user.Username, user.Email, user.Password, user.Role, now, now); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("UPDATE invitations SET user_id = ? WHERE id = ?"), user.ID, inv.ID); err != nil {
+		return err
+	}
+	if inv.OrganizationID != nil {
+		if err := addMember(ctx, tx, *inv.OrganizationID, user.ID, inv.Role); err != nil {
+			return err
+		}
+	}
+	if err := recordPassword(ctx, tx, user.ID, user.Password, keep); err != nil {
+		return err
+	}
+	if err := publishUserRegistered(ctx, tx, user.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// oauthClientColumns คอลัมน์ของตาราง oauth_clients ที่อ่านเข้าสู่ models.OAuthClient
+const oauthClientColumns = "id, client_id, secret_hash, name, redirect_uris, grant_types, scopes, first_party, created_at, updated_at"
+
+// OAuthRepository จัดการข้อมูลของ OAuth2 authorization server
+// (oauth_clients, oauth_authorization_codes, oauth_refresh_tokens, oauth_consents, oauth_revoked_tokens)
+type OAuthRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewOAuthRepository สร้าง OAuthRepository ใหม่
+func NewOAuthRepository(db *sqlx.DB) *OAuthRepository {
+	return &OAuthRepository{DB: db}
+}
+
+// CreateClient ลงทะเบียนแอปใหม่และคืนค่า ID
+func (r *OAuthRepository) CreateClient(ctx context.Context, client *models.OAuthClient) (int, error) {
+	query := "INSERT INTO oauth_clients (client_id, secret_hash, name, redirect_uris, grant_types, scopes, first_party) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, client.ClientID, client.SecretHash, client.Name, client.RedirectURIs, client.GrantTypes, client.Scopes, client.FirstParty)
+}
+
+// ListClients คืนค่าแอปทั้งหมด เรียงจากใหม่ไปเก่า
+func (r *OAuthRepository) ListClients(ctx context.Context) ([]models.OAuthClient, error) {
+	clients := []models.OAuthClient{}
+	query := "SELECT " + oauthClientColumns + " FROM oauth_clients ORDER BY id DESC"
+	if err := r.DB.SelectContext(ctx, &clients, query); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// GetClientByID ค้นหาแอปตาม ID
+func (r *OAuthRepository) GetClientByID(ctx context.Context, id int) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	query := "SELECT " + oauthClientColumns + " FROM oauth_clients WHERE id = ?"
+	if err := r.DB.GetContext(ctx, &client, r.DB.Rebind(query), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &client, nil
+}
+
+// GetClient ค้นหาแอปตาม client_id
+func (r *OAuthRepository) GetClient(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	query := "SELECT " + oauthClientColumns + " FROM oauth_clients WHERE client_id = ?"
+	if err := r.DB.GetContext(ctx, &client, r.DB.Rebind(query), clientID); err != nil {
+		return nil, notFound(err)
+	}
+	return &client, nil
+}
+
+// DeleteClient ลบแอปพร้อม code, refresh token และความยินยอมทั้งหมดของแอป
+// (access token ที่ออกไปแล้วจะใช้ได้จนหมดอายุ แต่ introspection จะตอบว่าไม่ active)
+func (r *OAuthRepository) DeleteClient(ctx context.Context, clientID string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// ลบตารางลูกเองก่อน เพราะ SQLite จะไม่ทำ ON DELETE CASCADE หากไม่ได้เปิด foreign_keys
+	for _, table := range []string{"oauth_authorization_codes", "oauth_refresh_tokens", "oauth_consents", "oauth_clients"} {
+		if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM "+table+" WHERE client_id = ?"), clientID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CreateCode บันทึก authorization code ใหม่
+func (r *OAuthRepository) CreateCode(ctx context.Context, code *models.OAuthCode) error {
+	query := "INSERT INTO oauth_authorization_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	_, err := database.InsertID(ctx, r.DB, query, code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, code.Scopes, code.CodeChallenge, code.ExpiresAt.UTC())
+	return err
+}
+
+// ConsumeCode อ่านและลบ authorization code ใน transaction เดียว (code ใช้ได้ครั้งเดียว)
+// คืนค่า ErrNotFound เมื่อไม่พบ code หรือ code ถูกใช้ไปแล้ว
+// ผู้เรียกต้องตรวจเวลาหมดอายุ, client และ redirect URI เอง
+func (r *OAuthRepository) ConsumeCode(ctx context.Context, codeHash string) (*models.OAuthCode, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var code models.OAuthCode
+	query := "SELECT id, code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at, created_at FROM oauth_authorization_codes WHERE code_hash = ?"
+	if err := tx.GetContext(ctx, &code, tx.Rebind(query), codeHash); err != nil {
+		return nil, notFound(err)
+	}
+
+	// ตรวจจำนวนแถวที่ถูกลบ เพื่อให้ request ที่แลก code เดียวกันพร้อมกันสำเร็จได้เพียงรายเดียว
+	result, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM oauth_authorization_codes WHERE id = ?"), code.ID)
+	if err != nil {
+		return nil, err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if affected == 0 {
+		return nil, ErrNotFound
+	}
+
+	// ลบ code ที่หมดอายุแล้วของทุกแอปไปพร้อมกัน
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM oauth_authorization_codes WHERE expires_at < ?"), time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return &code, tx.Commit()
+}
+
+// CreateRefreshToken บันทึก refresh token ใหม่
+func (r *OAuthRepository) CreateRefreshToken(ctx context.Context, token *models.OAuthRefreshToken) error {
+	query := "INSERT INTO oauth_refresh_tokens (token_hash, client_id, user_id, scopes, expires_at) VALUES (?, ?, ?, ?, ?)"
+	_, err := database.InsertID(ctx, r.DB, query, token.TokenHash, token.ClientID, token.UserID, token.Scopes, token.ExpiresAt.UTC())
+	return err
+}
+
+// GetRefreshToken ค้นหา refresh token ตาม hash (รวม token ที่ถูกเพิกถอนแล้ว)
+func (r *OAuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.OAuthRefreshToken, error) {
+	var token models.OAuthRefreshToken
+	query := "SELECT id, token_hash, client_id, user_id, scopes, expires_at, revoked_at, created_at FROM oauth_refresh_tokens WHERE token_hash = ?"
+	if err := r.DB.GetContext(ctx, &token, r.DB.Rebind(query), tokenHash); err != nil {
+		return nil, notFound(err)
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken เพิกถอน refresh token คืนค่า false หากถูกเพิกถอนไปก่อนแล้ว
+// (ใช้ตอนหมุนเวียน token เพื่อให้ request ที่ใช้ token เดียวกันพร้อมกันสำเร็จได้เพียงรายเดียว)
+func (r *OAuthRepository) RevokeRefreshToken(ctx context.Context, id int) (bool, error) {
+	query := "UPDATE oauth_refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), time.Now().UTC(), id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// RevokeRefreshTokens เพิกถอน refresh token ทั้งหมดที่แอปได้รับจากผู้ใช้
+// ใช้เมื่อพบว่า refresh token ที่ถูกหมุนเวียนไปแล้วถูกนำกลับมาใช้ซ้ำ (สัญญาณว่า token รั่วไหล)
+func (r *OAuthRepository) RevokeRefreshTokens(ctx context.Context, clientID string, userID int) error {
+	query := "UPDATE oauth_refresh_tokens SET revoked_at = ? WHERE client_id = ? AND user_id = ? AND revoked_at IS NULL"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), time.Now().UTC(), clientID, userID)
+	return err
+}
+
+// GetConsent คืนค่า scope ที่ผู้ใช้เคยอนุญาตให้แอป (ว่าง = ยังไม่เคยอนุญาต)
+func (r *OAuthRepository) GetConsent(ctx context.Context, userID int, clientID string) (string, error) {
+	var scopes string
+	query := "SELECT scopes FROM oauth_consents WHERE user_id = ? AND client_id = ?"
+	err := r.DB.GetContext(ctx, &scopes, r.DB.Rebind(query), userID, clientID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return scopes, err
+}
+
+// SaveConsent บันทึก scope ที่ผู้ใช้อนุญาตให้แอป (แทนที่ค่าเดิม)
+func (r *OAuthRepository) SaveConsent(ctx context.Context, userID int, clientID, scopes string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var count int
+	if err := tx.GetContext(ctx, &count, tx.Rebind("SELECT COUNT(*) FROM oauth_consents WHERE user_id = ? AND client_id = ?"), userID, clientID); err != nil {
+		return err
+	}
+	if count > 0 {
+		query := "UPDATE oauth_consents SET scopes = ?, updated_at = ? WHERE user_id = ? AND client_id = ?"
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), scopes, now, userID, clientID)
+	} else {
+		query := "INSERT INTO oauth_consents (user_id, client_id, scopes, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), userID, clientID, scopes, now, now)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeAccessToken บันทึก jti ของ access token ที่ถูกเพิกถอน ไว้จนถึงเวลาที่ token หมดอายุ
+// และลบรายการที่หมดอายุแล้ว (token เหล่านั้นใช้ไม่ได้อยู่แล้ว)
+func (r *OAuthRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM oauth_revoked_tokens WHERE jti = ? OR expires_at < ?"), jti, time.Now().UTC()); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("INSERT INTO oauth_revoked_tokens (jti, expires_at) VALUES (?, ?)"), jti, expiresAt.UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// IsAccessTokenRevoked ตรวจว่า access token ที่มี jti นี้ถูกเพิกถอนแล้วหรือไม่
+func (r *OAuthRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM oauth_revoked_tokens WHERE jti = ?"
+	if err := r.DB.GetContext(ctx, &count, r.DB.Rebind(query), jti); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
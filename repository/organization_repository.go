@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// organizationColumns คอลัมน์ของตาราง organizations ที่อ่านเข้าสู่ models.Organization
+const organizationColumns = "id, slug, name, created_at, updated_at"
+
+// ErrOrganizationExists คืนค่าเมื่อมีองค์กรที่ใช้ slug นี้อยู่แล้ว
+var ErrOrganizationExists = errors.New("มีองค์กรที่ใช้ slug นี้อยู่แล้ว")
+
+// OrganizationRepository จัดการข้อมูลในตาราง organizations และ memberships
+type OrganizationRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewOrganizationRepository สร้าง OrganizationRepository ใหม่
+func NewOrganizationRepository(db *sqlx.DB) *OrganizationRepository {
+	return &OrganizationRepository{DB: db}
+}
+
+// Create บันทึกองค์กรใหม่และคืนค่า ID คืนค่า ErrOrganizationExists หาก slug ซ้ำ
+func (r *OrganizationRepository) Create(ctx context.Context, org *models.Organization) (int, error) {
+	if _, err := r.GetBySlug(ctx, org.Slug); err == nil {
+		return 0, ErrOrganizationExists
+	} else if !errors.Is(err, ErrNotFound) {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	query := "INSERT INTO organizations (slug, name, created_at, updated_at) VALUES (?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, org.Slug, org.Name, now, now)
+}
+
+// List คืนค่าองค์กรทั้งหมด เรียงตาม ID
+func (r *OrganizationRepository) List(ctx context.Context) ([]models.Organization, error) {
+	orgs := []models.Organization{}
+	query := "SELECT " + organizationColumns + " FROM organizations ORDER BY id"
+	if err := r.DB.SelectContext(ctx, &orgs, query); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// GetByID ค้นหาองค์กรตาม ID
+func (r *OrganizationRepository) GetByID(ctx context.Context, id int) (*models.Organization, error) {
+	var org models.Organization
+	query := "SELECT " + organizationColumns + " FROM organizations WHERE id = ?"
+	if err := r.DB.GetContext(ctx, &org, r.DB.Rebind(query), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &org, nil
+}
+
+// GetBySlug ค้นหาองค์กรตาม slug
+func (r *OrganizationRepository) GetBySlug(ctx context.Context, slug string) (*models.Organization, error) {
+	var org models.Organization
+	query := "SELECT " + organizationColumns + " FROM organizations WHERE slug = ?"
+	if err := r.DB.GetContext(ctx, &org, r.DB.Rebind(query), slug); err != nil {
+		return nil, notFound(err)
+	}
+	return &org, nil
+}
+
+// ForUser คืนค่าองค์กรที่ผู้ใช้เป็นสมาชิกพร้อม role เรียงตามลำดับที่เข้าร่วม
+func (r *OrganizationRepository) ForUser(ctx context.Context, userID int) ([]models.OrganizationMembership, error) {
+	orgs := []models.OrganizationMembership{}
+	query := "SELECT o.id, o.slug, o.name, o.created_at, o.updated_at, m.role FROM memberships m" +
+		" JOIN organizations o ON o.id = m.organization_id WHERE m.user_id = ? ORDER BY m.id"
+	if err := r.DB.SelectContext(ctx, &orgs, r.DB.Rebind(query), userID); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// MemberRole คืนค่า role ของผู้ใช้ในองค์กร หรือ ErrNotFound หากไม่ได้เป็นสมาชิก
+func (r *OrganizationRepository) MemberRole(ctx context.Context, orgID, userID int) (string, error) {
+	var role string
+	query := "SELECT role FROM memberships WHERE organization_id = ? AND user_id = ?"
+	if err := r.DB.GetContext(ctx, &role, r.DB.Rebind(query), orgID, userID); err != nil {
+		return "", notFound(err)
+	}
+	return role, nil
+}
+
+// SetMember เพิ่มผู้ใช้เข้าองค์กร หรือเปลี่ยน role หากเป็นสมาชิกอยู่แล้ว
+// คืนค่า true เมื่อเพิ่มสมาชิกใหม่
+func (r *OrganizationRepository) SetMember(ctx context.Context, orgID, userID int, role string) (bool, error) {
+	query := "UPDATE memberships SET role = ? WHERE organization_id = ? AND user_id = ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), role, orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected > 0 {
+		return false, err
+	}
+	if err := addMember(ctx, r.DB, orgID, userID, role); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveMember นำผู้ใช้ออกจากองค์กรและกลุ่มขององค์กร คืนค่า false หากไม่ได้เป็นสมาชิก
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, orgID, userID int) (bool, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM memberships WHERE organization_id = ? AND user_id = ?"
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	if err := removeFromGroups(ctx, tx, userID, orgID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// addMember เพิ่มแถวใน memberships ใช้ได้ทั้งกับ *sqlx.DB และ *sqlx.Tx
+func addMember(ctx context.Context, ext sqlx.ExtContext, orgID, userID int, role string) error {
+	query := "INSERT INTO memberships (organization_id, user_id, role, created_at) VALUES (?, ?, ?, ?)"
+	_, err := ext.ExecContext(ctx, ext.Rebind(query), orgID, userID, role, time.Now().UTC())
+	return err
+}
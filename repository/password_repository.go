@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PasswordRepository จัดการรหัสผ่านของผู้ใช้และตาราง password_history
+type PasswordRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewPasswordRepository สร้าง PasswordRepository ใหม่
+func NewPasswordRepository(db *sqlx.DB) *PasswordRepository {
+	return &PasswordRepository{DB: db}
+}
+
+// Recent คืนค่า hash ของรหัสผ่าน limit รายการล่าสุดของผู้ใช้ เรียงจากใหม่ไปเก่า
+func (r *PasswordRepository) Recent(ctx context.Context, userID, limit int) ([]string, error) {
+	hashes := []string{}
+	if limit <= 0 {
+		return hashes, nil
+	}
+	query := "SELECT password_hash FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?"
+	if err := r.DB.SelectContext(ctx, &hashes, r.DB.Rebind(query), userID, limit); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// Record บันทึก hash ของรหัสผ่านที่ผู้ใช้เพิ่งตั้งลงในประวัติ และเก็บไว้เพียง keep รายการล่าสุด
+// keep เป็น 0 (ปิดการตรวจซ้ำ) จะลบประวัติของผู้ใช้ทั้งหมด
+// ใช้ได้ทั้งกับ *sqlx.DB และ *sqlx.Tx ผู้เรียกที่สร้างผู้ใช้ควรส่ง transaction เดียวกัน เพื่อไม่ให้มีผู้ใช้ที่ไม่มีประวัติรหัสผ่านแรก
+func (r *PasswordRepository) Record(ctx context.Context, ext sqlx.ExtContext, userID int, hash string, keep int) error {
+	return recordPassword(ctx, ext, userID, hash, keep)
+}
+
+// Change เปลี่ยนรหัสผ่านของผู้ใช้และบันทึกประวัติใน transaction เดียวกัน
+func (r *PasswordRepository) Change(ctx context.Context, userID int, hash string, keep int) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE users SET password = ?, updated_at = ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), hash, time.Now(), userID); err != nil {
+		return err
+	}
+	if err := recordPassword(ctx, tx, userID, hash, keep); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Rehash แทนที่ hash ของรหัสผ่านเดิมด้วย hash ใหม่ของรหัสผ่านเดียวกัน (เปลี่ยนอัลกอริธึมหรือพารามิเตอร์)
+// อัปเดตเฉพาะเมื่อ hash ในฐานข้อมูลยังเป็น oldHash เพื่อไม่ทับรหัสผ่านที่ผู้ใช้เพิ่งเปลี่ยน
+// ไม่บันทึกลงประวัติ เพราะรหัสผ่านไม่ได้เปลี่ยน คืนค่า false ถ้า hash ในฐานข้อมูลไม่ใช่ oldHash แล้ว
+func (r *PasswordRepository) Rehash(ctx context.Context, userID int, oldHash, newHash string) (bool, error) {
+	query := "UPDATE users SET password = ? WHERE id = ? AND password = ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), newHash, userID, oldHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// recordPassword เพิ่ม hash ลงในประวัติแล้วลบรายการที่เก่ากว่า keep รายการล่าสุด
+// หา id ของรายการที่เก่าที่สุดที่ต้องเก็บก่อนแล้วค่อยลบ เพราะ MySQL ไม่รองรับ LIMIT ใน subquery ของ IN
+func recordPassword(ctx context.Context, ext sqlx.ExtContext, userID int, hash string, keep int) error {
+	if keep <= 0 {
+		_, err := ext.ExecContext(ctx, ext.Rebind("DELETE FROM password_history WHERE user_id = ?"), userID)
+		return err
+	}
+
+	insert := "INSERT INTO password_history (user_id, password_hash, created_at) VALUES (?, ?, ?)"
+	if _, err := ext.ExecContext(ctx, ext.Rebind(insert), userID, hash, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	var oldest int
+	query := "SELECT id FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT 1 OFFSET ?"
+	err := sqlx.GetContext(ctx, ext, &oldest, ext.Rebind(query), userID, keep-1)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = ext.ExecContext(ctx, ext.Rebind("DELETE FROM password_history WHERE user_id = ? AND id < ?"), userID, oldest)
+	return err
+}
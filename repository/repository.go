@@ -0,0 +1,19 @@
+// Package repository รวมการเข้าถึงฐานข้อมูลของแต่ละตาราง
+// query ทั้งหมดเขียนด้วย placeholder แบบ ? และถูก Rebind ตาม driver (mysql, postgres, sqlite)
+package repository
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// ErrNotFound คืนค่าเมื่อไม่พบข้อมูลที่ค้นหา
+var ErrNotFound = errors.New("ไม่พบข้อมูล")
+
+// notFound แปลง sql.ErrNoRows เป็น ErrNotFound เพื่อให้ผู้เรียกไม่ต้องรู้จัก database/sql
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// sessionColumns คอลัมน์ของตาราง sessions ที่อ่านเข้าสู่ models.Session
+const sessionColumns = "id, user_id, jti, device, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at, impersonator_id"
+
+// SessionRepository จัดการข้อมูลในตาราง sessions
+type SessionRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewSessionRepository สร้าง SessionRepository ใหม่
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{DB: db}
+}
+
+// Create บันทึก session ใหม่และคืนค่า ID
+// session ที่หมดอายุแล้วของผู้ใช้คนเดียวกันจะถูกลบไปพร้อมกัน เพื่อไม่ให้ตารางโตไม่สิ้นสุด
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) (int, error) {
+	now := time.Now().UTC()
+	purge := "DELETE FROM sessions WHERE user_id = ? AND expires_at < ?"
+	if _, err := r.DB.ExecContext(ctx, r.DB.Rebind(purge), session.UserID, now); err != nil {
+		return 0, err
+	}
+
+	query := "INSERT INTO sessions (user_id, jti, device, user_agent, ip_address, created_at, last_seen_at, expires_at, impersonator_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, session.UserID, session.JTI, session.Device, session.UserAgent,
+		session.IPAddress, now, now, session.ExpiresAt.UTC(), session.ImpersonatorID)
+}
+
+// ListActive คืนค่า session ที่ยังใช้งานได้ของผู้ใช้ เรียงจากที่ใช้งานล่าสุด
+func (r *SessionRepository) ListActive(ctx context.Context, userID int) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY last_seen_at DESC, id DESC"
+	if err := r.DB.SelectContext(ctx, &sessions, r.DB.Rebind(query), userID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// GetByJTI ค้นหา session ตาม jti ของ token (ใช้ตอนตรวจสอบ token ในแต่ละ request)
+func (r *SessionRepository) GetByJTI(ctx context.Context, jti string) (*models.Session, error) {
+	var session models.Session
+	query := "SELECT " + sessionColumns + " FROM sessions WHERE jti = ?"
+	if err := r.DB.GetContext(ctx, &session, r.DB.Rebind(query), jti); err != nil {
+		return nil, notFound(err)
+	}
+	return &session, nil
+}
+
+// Revoke เพิกถอน session ของผู้ใช้ที่กำหนด
+// คืนค่า false เมื่อไม่พบ session ที่ยังไม่ถูกเพิกถอนของผู้ใช้นี้
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id int) (bool, error) {
+	query := "UPDATE sessions SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), time.Now().UTC(), id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// RevokeOthers เพิกถอน session ทั้งหมดของผู้ใช้ยกเว้น keepID (เช่น หลังเปลี่ยนรหัสผ่าน)
+// keepID เป็น 0 จะเพิกถอนทุก session
+func (r *SessionRepository) RevokeOthers(ctx context.Context, userID, keepID int) error {
+	query := "UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id <> ? AND revoked_at IS NULL"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), time.Now().UTC(), userID, keepID)
+	return err
+}
+
+// TouchLastSeen อัปเดตเวลาที่ใช้งาน session ล่าสุด
+func (r *SessionRepository) TouchLastSeen(ctx context.Context, id int, at time.Time) error {
+	query := "UPDATE sessions SET last_seen_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), at.UTC(), id)
+	return err
+}
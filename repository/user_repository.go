@@ -0,0 +1,555 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// userColumns คอลัมน์ของตาราง users (alias u) ที่อ่านเข้าสู่ models.User (ไม่รวมรหัสผ่าน)
+const userColumns = "u.id, u.username, u.email, u.role, u.status, u.suspension_reason, u.suspended_until, u.created_at, u.updated_at," +
+	" u.display_name, u.bio, u.locale, u.timezone, u.phone, u.metadata, u.avatar_key"
+
+// UserRepository จัดการข้อมูลในตาราง users สำหรับงานของ Admin และโปรไฟล์
+// เมื่อกำหนด TenantID (ผ่าน ForTenant) ทุก query จะเห็นเฉพาะผู้ใช้ที่เป็นสมาชิกขององค์กรนั้น
+type UserRepository struct {
+	DB       *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+	TenantID int      // องค์กรที่จำกัดขอบเขต (0 = ทุกองค์กร สำหรับ Admin ของระบบ)
+}
+
+// NewUserRepository สร้าง UserRepository ใหม่ที่ไม่จำกัดองค์กร
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{DB: db}
+}
+
+// ForTenant คืนค่า UserRepository ที่จำกัดขอบเขตไว้ที่องค์กร tenantID (0 = ทุกองค์กร)
+func (r *UserRepository) ForTenant(tenantID int) *UserRepository {
+	return &UserRepository{DB: r.DB, TenantID: tenantID}
+}
+
+// selectUsers สร้าง SELECT ของผู้ใช้ที่จำกัดองค์กรแล้ว พร้อม args เริ่มต้น
+// ผู้เรียกต่อเงื่อนไขด้วย " WHERE ..." ได้ทันที
+func (r *UserRepository) selectUsers() (string, []interface{}) {
+	query := "SELECT " + userColumns + " FROM users u"
+	if r.TenantID == 0 {
+		return query, nil
+	}
+	return query + " JOIN memberships m ON m.user_id = u.id AND m.organization_id = ?", []interface{}{r.TenantID}
+}
+
+// List คืนค่าผู้ใช้ทั้งหมดในขอบเขต เรียงตาม ID (ไม่รวมรหัสผ่าน)
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	users := []models.User{}
+	query, args := r.selectUsers()
+	if err := r.DB.SelectContext(ctx, &users, r.DB.Rebind(query+" ORDER BY u.id"), args...); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetByID ค้นหาผู้ใช้ในขอบเขตตาม ID (ไม่รวมรหัสผ่าน)
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	var user models.User
+	query, args := r.selectUsers()
+	if err := r.DB.GetContext(ctx, &user, r.DB.Rebind(query+" WHERE u.id = ?"), append(args, id)...); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// AccountStatus อ่านสถานะบัญชีของผู้ใช้ (ไม่จำกัดองค์กร) สำหรับตรวจทุก request
+func (r *UserRepository) AccountStatus(ctx context.Context, id int) (*models.AccountStatus, error) {
+	var status models.AccountStatus
+	query := "SELECT status, suspended_until FROM users WHERE id = ?"
+	if err := r.DB.GetContext(ctx, &status, r.DB.Rebind(query), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &status, nil
+}
+
+// UpdateProfile บันทึกข้อมูลโปรไฟล์ของผู้ใช้ (ชื่อที่แสดง, แนะนำตัว, ภาษา, เขตเวลา, เบอร์โทรศัพท์, metadata)
+// พร้อม event UserUpdated ใน outbox ใน transaction เดียวกัน
+func (r *UserRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := "UPDATE users SET display_name = ?, bio = ?, locale = ?, timezone = ?, phone = ?, metadata = ?, updated_at = ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), user.DisplayName, user.Bio, user.Locale, user.Timezone,
+		user.Phone, user.Metadata, time.Now().UTC(), user.ID); err != nil {
+		return err
+	}
+	if err := publishUserUpdated(ctx, tx, user.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetAvatar เปลี่ยน key ของรูปโปรไฟล์ (nil = ลบรูป) พร้อม event UserUpdated ใน outbox ใน transaction เดียวกัน
+// และคืนค่า key เดิมเพื่อให้ผู้เรียกลบไฟล์เก่า
+func (r *UserRepository) SetAvatar(ctx context.Context, id int, key *string) (*string, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old *string
+	if err := tx.GetContext(ctx, &old, tx.Rebind("SELECT avatar_key FROM users WHERE id = ?"), id); err != nil {
+		return nil, notFound(err)
+	}
+	query := "UPDATE users SET avatar_key = ?, updated_at = ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), key, time.Now().UTC(), id); err != nil {
+		return nil, err
+	}
+	if err := publishUserUpdated(ctx, tx, id); err != nil {
+		return nil, err
+	}
+	return old, tx.Commit()
+}
+
+// Delete ลบผู้ใช้ในขอบเขตพร้อมการเป็นสมาชิกของกลุ่ม คืนค่า false หากไม่พบ
+// เมื่อจำกัดองค์กร จะนำผู้ใช้ออกจากองค์กรและกลุ่มขององค์กร และลบบัญชีเฉพาะเมื่อไม่เหลือองค์กรอื่นและไม่ใช่ Admin ของระบบ
+// (Admin ขององค์กรหนึ่งจึงลบบัญชีที่องค์กรอื่นยังใช้อยู่ไม่ได้)
+func (r *UserRepository) Delete(ctx context.Context, id int) (bool, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if deleted, err := r.delete(ctx, tx, id); err != nil || !deleted {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// delete ลบผู้ใช้ใน transaction ของผู้เรียกตามกฎของ Delete (ผู้เรียก commit เอง)
+// บัญชีที่ถูกลบจริงจะบันทึก event UserDeleted (พร้อมข้อมูลก่อนลบ) ลง outbox ใน transaction เดียวกัน
+func (r *UserRepository) delete(ctx context.Context, tx *sqlx.Tx, id int) (bool, error) {
+	user, err := getUser(ctx, tx, id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if r.TenantID == 0 {
+		if err := removeFromGroups(ctx, tx, id, 0); err != nil {
+			return false, err
+		}
+		result, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM users WHERE id = ?"), id)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		return true, publishEvent(ctx, tx, models.UserDeleted{User: user.ConvertToResponse()})
+	}
+
+	query := "DELETE FROM memberships WHERE organization_id = ? AND user_id = ?"
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), r.TenantID, id)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	if err := removeFromGroups(ctx, tx, id, r.TenantID); err != nil {
+		return false, err
+	}
+
+	query = "DELETE FROM users WHERE id = ? AND role <> 'admin' AND NOT EXISTS (SELECT 1 FROM memberships WHERE user_id = ?)"
+	result, err = tx.ExecContext(ctx, tx.Rebind(query), id, id)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		// บัญชียังอยู่ (องค์กรอื่นยังใช้หรือเป็น Admin ของระบบ) จึงไม่ใช่ UserDeleted
+		return true, err
+	}
+	return true, publishEvent(ctx, tx, models.UserDeleted{User: user.ConvertToResponse()})
+}
+
+// Taken คืนค่าอีเมล (ตัวพิมพ์เล็ก) และชื่อผู้ใช้ที่มีบัญชีใช้อยู่แล้วจากรายการที่ระบุ (ตรวจทุกองค์กร)
+func (r *UserRepository) Taken(ctx context.Context, emails, usernames []string) (map[string]bool, map[string]bool, error) {
+	takenEmails, takenUsernames := map[string]bool{}, map[string]bool{}
+	if len(emails) == 0 && len(usernames) == 0 {
+		return takenEmails, takenUsernames, nil
+	}
+	// sqlx.In ไม่รับ slice ว่าง จึงใช้ค่าที่ไม่มีทางตรงกับบัญชีใดแทน
+	if len(emails) == 0 {
+		emails = []string{""}
+	}
+	if len(usernames) == 0 {
+		usernames = []string{""}
+	}
+	query, args, err := sqlx.In("SELECT LOWER(email) AS email, username FROM users WHERE LOWER(email) IN (?) OR username IN (?)", emails, usernames)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rows []struct {
+		Email    string `db:"email"`
+		Username string `db:"username"`
+	}
+	if err := r.DB.SelectContext(ctx, &rows, r.DB.Rebind(query), args...); err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		takenEmails[row.Email] = true
+		takenUsernames[row.Username] = true
+	}
+	return takenEmails, takenUsernames, nil
+}
+
+// Import สร้างผู้ใช้ทั้งหมดพร้อม event UserRegistered ของแต่ละคนใน transaction เดียว (สำเร็จทั้งหมดหรือไม่สร้างเลย)
+// ผู้ใช้ต้องมีรหัสผ่านที่ hash แล้ว เมื่อจำกัดองค์กรจะเพิ่มผู้ใช้เป็นสมาชิก role user ขององค์กรนั้นด้วย
+// คืนค่า ErrUserExists หากอีเมลหรือชื่อผู้ใช้ถูกใช้ไประหว่างตรวจสอบและบันทึก
+func (r *UserRepository) Import(ctx context.Context, users []*models.User, keep int) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	for _, user := range users {
+		var count int
+		query := "SELECT COUNT(*) FROM users WHERE LOWER(email) = LOWER(?) OR username = ?"
+		if err := tx.GetContext(ctx, &count, tx.Rebind(query), user.Email, user.Username); err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUserExists
+		}
+
+		user.CreatedAt, user.UpdatedAt = now, now
+		query = "INSERT INTO users (username, email, password, role, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"
+		if user.ID, err = database.InsertID(ctx, tx, query, user.Username, user.Email, user.Password, user.Role, now, now); err != nil {
+			return err
+		}
+		if r.TenantID != 0 {
+			if err := addMember(ctx, tx, r.TenantID, user.ID, "user"); err != nil {
+				return err
+			}
+		}
+		if err := recordPassword(ctx, tx, user.ID, user.Password, keep); err != nil {
+			return err
+		}
+		if err := publishUserRegistered(ctx, tx, user.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Rows เปิด cursor ของผู้ใช้ทั้งหมดในขอบเขต เรียงตาม ID สำหรับอ่านทีละแถวด้วย StructScan ลง models.User
+// ใช้แทน List เมื่อผู้ใช้มีจำนวนมาก (เช่น export) ผู้เรียกต้อง Close เสมอ
+func (r *UserRepository) Rows(ctx context.Context) (*sqlx.Rows, error) {
+	query, args := r.selectUsers()
+	return r.DB.QueryxContext(ctx, r.DB.Rebind(query+" ORDER BY u.id"), args...)
+}
+
+// maxBatchUsers จำนวนผู้ใช้สูงสุดต่อคำสั่ง batch (รวมผู้ใช้ที่ตรงกับ filter)
+const maxBatchUsers = 1000
+
+// ErrBatchTooLarge คืนค่าเมื่อ filter ของคำสั่ง batch ตรงกับผู้ใช้เกิน maxBatchUsers ราย
+var ErrBatchTooLarge = errors.New("คำสั่งเดียวจัดการผู้ใช้ได้ไม่เกิน 1000 ราย กรุณาระบุเงื่อนไขให้แคบลง")
+
+// Batch ดำเนินการกับผู้ใช้หลายรายในขอบเขตภายใน transaction เดียว และบันทึก audit log หนึ่งรายการของทั้งคำสั่ง
+// ผู้ใช้ที่ติดข้อห้ามถูกข้าม (ไม่ยกเลิกทั้งคำสั่ง): ผู้ดูแลจัดการบัญชีตัวเองไม่ได้ (ยกเว้น enable)
+// และห้ามลบ ลด role หรือระงับ Admin ที่ใช้งานได้คนสุดท้ายของระบบหรือขององค์กร
+// audit.ActorID คือผู้ดูแลที่สั่ง ส่วนรายละเอียดของ audit ถูกเติมจากคำสั่งและผลลัพธ์
+func (r *UserRepository) Batch(ctx context.Context, req *models.UserBatchRequest, audit *models.AuditLog) (*models.UserBatchResponse, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, users, err := r.batchTargets(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	memberRoles, err := r.memberRoles(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// จำนวน Admin ที่ใช้งานได้ ลดลงทุกครั้งที่คำสั่งนำสิทธิ์ Admin ออกจากผู้ใช้หนึ่งราย
+	var platformAdmins, tenantAdmins int
+	query := "SELECT COUNT(*) FROM users WHERE role = 'admin' AND status = 'active'"
+	if err := tx.GetContext(ctx, &platformAdmins, query); err != nil {
+		return nil, err
+	}
+	if r.TenantID != 0 {
+		query = "SELECT COUNT(*) FROM memberships m JOIN users u ON u.id = m.user_id" +
+			" WHERE m.organization_id = ? AND m.role = 'admin' AND u.status = 'active'"
+		if err := tx.GetContext(ctx, &tenantAdmins, tx.Rebind(query), r.TenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.UserBatchResponse{Action: req.Action, Matched: len(users), Results: make([]models.UserBatchResult, 0, len(ids))}
+	now := time.Now().UTC()
+	for _, id := range ids {
+		result := models.UserBatchResult{ID: id, Status: models.UserBatchOK}
+		user, found := users[id]
+		role := user.Role
+		if r.TenantID != 0 {
+			role = memberRoles[id]
+		}
+
+		switch {
+		case !found:
+			result.Status = models.UserBatchNotFound
+		case audit.ActorID != nil && *audit.ActorID == id && req.Action != models.UserBatchEnable:
+			result.Status, result.Reason = models.UserBatchSkipped, "self"
+		case req.Action == models.UserBatchSetRole && role == req.Role,
+			req.Action == models.UserBatchDisable && sameSuspension(&user, req, now),
+			req.Action == models.UserBatchEnable && user.Status == models.UserStatusActive:
+			result.Status = models.UserBatchUnchanged
+		}
+		if result.Status != models.UserBatchOK {
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		// set_role ในองค์กรและการลบในองค์กรไม่กระทบ role ของระบบ ส่วนการระงับบัญชีกระทบทั้งสองระดับ
+		active := user.Status == models.UserStatusActive
+		removesPlatformAdmin := active && user.Role == "admin" &&
+			(req.Action == models.UserBatchDisable || r.TenantID == 0 && req.Action != models.UserBatchEnable)
+		removesTenantAdmin := active && r.TenantID != 0 && role == "admin" && req.Action != models.UserBatchEnable
+		if removesPlatformAdmin && platformAdmins <= 1 || removesTenantAdmin && tenantAdmins <= 1 {
+			result.Status, result.Reason = models.UserBatchSkipped, "last_admin"
+			response.Results = append(response.Results, result)
+			continue
+		}
+		if removesPlatformAdmin {
+			platformAdmins--
+		}
+		if removesTenantAdmin {
+			tenantAdmins--
+		}
+
+		if err := r.applyBatch(ctx, tx, req, id, now); err != nil {
+			return nil, err
+		}
+		response.Succeeded++
+		response.Results = append(response.Results, result)
+	}
+
+	// รายละเอียดเก็บ ID แยกตามผลลัพธ์ (ไม่เก็บผลรายแถวเต็ม เพื่อให้บันทึกมีขนาดเล็ก)
+	outcomes := map[string][]int{}
+	for _, result := range response.Results {
+		key := result.Status
+		if result.Reason != "" {
+			key += ":" + result.Reason
+		}
+		outcomes[key] = append(outcomes[key], result.ID)
+	}
+	details := map[string]interface{}{"action": req.Action, "matched": response.Matched, "succeeded": response.Succeeded, "results": outcomes}
+	if req.Role != "" {
+		details["role"] = req.Role
+	}
+	if req.Reason != "" {
+		details["reason"] = req.Reason
+	}
+	if req.Until != nil {
+		details["until"] = req.Until.UTC()
+	}
+	if req.Filter != nil {
+		details["filter"] = req.Filter
+	}
+	if err := recordAudit(ctx, tx, audit, details); err != nil {
+		return nil, err
+	}
+	response.AuditID = audit.ID
+	return response, tx.Commit()
+}
+
+// batchTargets คืนค่า ID ตามลำดับที่จะดำเนินการ และผู้ใช้ในขอบเขตที่พบ (ตาม ID)
+// ระบุ IDs: ID ซ้ำถูกตัดออก และ ID ที่ไม่พบยังอยู่ในลำดับ (ผลเป็น not_found)
+// ระบุ Filter: ผู้ใช้ที่ตรงทุกเงื่อนไขเรียงตาม ID ไม่เกิน maxBatchUsers ราย
+func (r *UserRepository) batchTargets(ctx context.Context, tx *sqlx.Tx, req *models.UserBatchRequest) ([]int, map[int]models.User, error) {
+	query, args := r.selectUsers()
+	var ids []int
+	if req.Filter == nil {
+		seen := map[int]bool{}
+		for _, id := range req.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		var err error
+		if query, args, err = sqlx.In(query+" WHERE u.id IN (?)", append(args, ids)...); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		where, filterArgs := r.filterUsers(req.Filter)
+		query += " WHERE " + where + " ORDER BY u.id LIMIT ?"
+		args = append(append(args, filterArgs...), maxBatchUsers+1)
+	}
+
+	var found []models.User
+	if err := tx.SelectContext(ctx, &found, tx.Rebind(query), args...); err != nil {
+		return nil, nil, err
+	}
+	if len(found) > maxBatchUsers {
+		return nil, nil, ErrBatchTooLarge
+	}
+	users := make(map[int]models.User, len(found))
+	for _, user := range found {
+		users[user.ID] = user
+		if req.Filter != nil {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids, users, nil
+}
+
+// filterUsers สร้างเงื่อนไข WHERE ของ models.UserFilter (ต่อจาก selectUsers) พร้อม args
+// role ในองค์กรหมายถึง role ในองค์กร (memberships.role)
+func (r *UserRepository) filterUsers(filter *models.UserFilter) (string, []interface{}) {
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Role != "" {
+		if r.TenantID != 0 {
+			conditions = append(conditions, "m.role = ?")
+		} else {
+			conditions = append(conditions, "u.role = ?")
+		}
+		args = append(args, filter.Role)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "u.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.EmailDomain != "" {
+		conditions = append(conditions, "LOWER(u.email) LIKE ?")
+		args = append(args, "%@"+strings.ToLower(filter.EmailDomain))
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "u.created_at > ?")
+		args = append(args, filter.CreatedAfter.UTC())
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "u.created_at < ?")
+		args = append(args, filter.CreatedBefore.UTC())
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// memberRoles คืนค่า role ในองค์กรของผู้ใช้ที่ระบุ (ว่างเมื่อไม่จำกัดองค์กร)
+func (r *UserRepository) memberRoles(ctx context.Context, tx *sqlx.Tx, ids []int) (map[int]string, error) {
+	roles := map[int]string{}
+	if r.TenantID == 0 || len(ids) == 0 {
+		return roles, nil
+	}
+	query, args, err := sqlx.In("SELECT user_id, role FROM memberships WHERE organization_id = ? AND user_id IN (?)", r.TenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		UserID int    `db:"user_id"`
+		Role   string `db:"role"`
+	}
+	if err := tx.SelectContext(ctx, &rows, tx.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		roles[row.UserID] = row.Role
+	}
+	return roles, nil
+}
+
+// applyBatch ดำเนินการของคำสั่ง batch กับผู้ใช้หนึ่งราย (ผ่านการตรวจข้อห้ามแล้ว)
+func (r *UserRepository) applyBatch(ctx context.Context, tx *sqlx.Tx, req *models.UserBatchRequest, id int, now time.Time) error {
+	var err error
+	switch req.Action {
+	case models.UserBatchDelete:
+		_, err = r.delete(ctx, tx, id)
+	case models.UserBatchSetRole:
+		if r.TenantID != 0 {
+			_, err = tx.ExecContext(ctx, tx.Rebind("UPDATE memberships SET role = ? WHERE organization_id = ? AND user_id = ?"), req.Role, r.TenantID, id)
+		} else {
+			_, err = tx.ExecContext(ctx, tx.Rebind("UPDATE users SET role = ?, updated_at = ? WHERE id = ?"), req.Role, now, id)
+		}
+	case models.UserBatchDisable:
+		// เพิกถอน session ทั้งหมดเพื่อให้ token ที่ออกไปแล้วใช้ไม่ได้ทันที
+		var reason *string
+		if req.Reason != "" {
+			reason = &req.Reason
+		}
+		var until *time.Time
+		if req.Until != nil {
+			utc := req.Until.UTC()
+			until = &utc
+		}
+		query := "UPDATE users SET status = ?, suspension_reason = ?, suspended_until = ?, updated_at = ? WHERE id = ?"
+		if _, err = tx.ExecContext(ctx, tx.Rebind(query), models.UserStatusSuspended, reason, until, now, id); err == nil {
+			_, err = tx.ExecContext(ctx, tx.Rebind("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL"), now, id)
+		}
+	case models.UserBatchEnable:
+		query := "UPDATE users SET status = ?, suspension_reason = NULL, suspended_until = NULL, updated_at = ? WHERE id = ?"
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), models.UserStatusActive, now, id)
+	}
+	// role ในองค์กรไม่ใช่ข้อมูลของบัญชี จึงไม่ใช่ UserUpdated (การลบบันทึก UserDeleted ใน delete แล้ว)
+	if err != nil || req.Action == models.UserBatchDelete || req.Action == models.UserBatchSetRole && r.TenantID != 0 {
+		return err
+	}
+	return publishUserUpdated(ctx, tx, id)
+}
+
+// sameSuspension ตรวจว่าผู้ใช้ถูกระงับอยู่ด้วยเหตุผลและเวลาสิ้นสุดเดียวกับคำสั่ง disable แล้ว
+// (การระงับที่หมดเวลาแล้ว หรือคำสั่งที่เปลี่ยนเหตุผล/เวลาสิ้นสุด ถือว่าต้องดำเนินการ)
+func sameSuspension(user *models.User, req *models.UserBatchRequest, now time.Time) bool {
+	if user.EffectiveStatus(now) != models.UserStatusSuspended {
+		return false
+	}
+	reason := ""
+	if user.SuspensionReason != nil {
+		reason = *user.SuspensionReason
+	}
+	if reason != req.Reason || (user.SuspendedUntil == nil) != (req.Until == nil) {
+		return false
+	}
+	return req.Until == nil || user.SuspendedUntil.Equal(*req.Until)
+}
+
+// getUser อ่านผู้ใช้ตาม ID (ไม่จำกัดองค์กร ไม่รวมรหัสผ่าน) ใน transaction ของผู้เรียก
+func getUser(ctx context.Context, tx *sqlx.Tx, id int) (*models.User, error) {
+	var user models.User
+	if err := tx.GetContext(ctx, &user, tx.Rebind("SELECT "+userColumns+" FROM users u WHERE u.id = ?"), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// publishUserRegistered บันทึก event UserRegistered ของผู้ใช้ที่เพิ่งสร้างใน transaction ลง outbox
+func publishUserRegistered(ctx context.Context, tx *sqlx.Tx, id int) error {
+	user, err := getUser(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	return publishEvent(ctx, tx, models.UserRegistered{User: user.ConvertToResponse()})
+}
+
+// publishUserUpdated บันทึก event UserUpdated พร้อมข้อมูลล่าสุดของผู้ใช้ใน transaction ลง outbox
+func publishUserUpdated(ctx context.Context, tx *sqlx.Tx, id int) error {
+	user, err := getUser(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	return publishEvent(ctx, tx, models.UserUpdated{User: user.ConvertToResponse()})
+}
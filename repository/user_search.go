@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/search"
+)
+
+// maxSearchCandidates จำนวนผู้สมัครสูงสุดต่อขั้นของการค้นหา (ตรงตัว และพิมพ์ผิด)
+const maxSearchCandidates = 500
+
+// searchColumns คอลัมน์ที่ค้นหา (ต้องตรงกับดัชนี ft_users_search ของ MySQL)
+var searchColumns = []string{"u.username", "u.email", "u.display_name"}
+
+// Search คืนค่าผู้สมัครของคำค้น terms (ตัวพิมพ์เล็กจาก search.Terms) ในขอบเขต เพื่อนำไปจัดอันดับด้วย package search
+// ขั้นแรกหาผู้ใช้ที่มีคำค้นอยู่ตรงตัว เรียงตามความตรงในฐานข้อมูล (ตรงทั้งข้อความ > ขึ้นต้น > มีอยู่ภายใน
+// และ MySQL ใช้คะแนนของดัชนี FULLTEXT ร่วมด้วย)
+// ขั้นที่สองหาผู้ใช้ที่มี bigram/trigram ของคำค้น เรียงตามจำนวน n-gram ที่ตรง เพื่อให้พบผู้ใช้แม้คำค้นพิมพ์ผิด
+// แต่ละขั้นได้ไม่เกิน maxSearchCandidates ราย (ผู้ใช้ที่ตรงที่สุดจึงอยู่ในผู้สมัครเสมอ) ผู้ใช้ที่ซ้ำกันถูกรวมเป็นรายเดียว
+// truncated เป็น true เมื่อขั้นใดขั้นหนึ่งมีผู้ใช้ที่ตรงเกิน maxSearchCandidates (ผู้ที่ตรงน้อยกว่าถูกตัดออก)
+func (r *UserRepository) Search(ctx context.Context, terms []string) (users []models.User, truncated bool, err error) {
+	exact, exactArgs := likeAny(terms)
+	order, orderArgs := exactRelevance(terms)
+	if against := fulltextQuery(terms); r.DB.DriverName() == database.DriverMySQL && against != "" {
+		match := "MATCH (" + strings.Join(searchColumns, ", ") + ") AGAINST (? IN BOOLEAN MODE)"
+		exact = "(" + exact + " OR " + match + ")"
+		exactArgs = append(exactArgs, against)
+		order += " DESC, " + match
+		orderArgs = append(orderArgs, against)
+	}
+	users, truncated, err = r.searchCandidates(ctx, exact, exactArgs, order+" DESC", orderArgs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	patterns := search.Patterns(terms)
+	fuzzy, fuzzyArgs := likeAny(patterns)
+	order, orderArgs = patternMatches(patterns)
+	more, moreTruncated, err := r.searchCandidates(ctx, fuzzy, fuzzyArgs, order+" DESC", orderArgs)
+	if err != nil {
+		return nil, false, err
+	}
+	seen := make(map[int]bool, len(users))
+	for _, user := range users {
+		seen[user.ID] = true
+	}
+	for _, user := range more {
+		if !seen[user.ID] {
+			users = append(users, user)
+		}
+	}
+	return users, truncated || moreTruncated, nil
+}
+
+// searchCandidates อ่านผู้ใช้ในขอบเขตที่ตรงเงื่อนไข where เรียงตาม order (ตามด้วย u.id) ไม่เกิน maxSearchCandidates ราย
+// และคืนค่า true เมื่อยังมีผู้ใช้ที่ตรงเงื่อนไขเหลืออยู่
+func (r *UserRepository) searchCandidates(ctx context.Context, where string, whereArgs []interface{}, order string, orderArgs []interface{}) ([]models.User, bool, error) {
+	query, args := r.selectUsers()
+	query += " WHERE " + where + " ORDER BY " + order + ", u.id LIMIT ?"
+	args = append(append(append(args, whereArgs...), orderArgs...), maxSearchCandidates+1)
+
+	users := []models.User{}
+	if err := r.DB.SelectContext(ctx, &users, r.DB.Rebind(query), args...); err != nil {
+		return nil, false, err
+	}
+	if len(users) > maxSearchCandidates {
+		return users[:maxSearchCandidates], true, nil
+	}
+	return users, false, nil
+}
+
+// exactRelevance สร้างนิพจน์คะแนนความตรงของคำค้นสำหรับ ORDER BY พร้อม args
+// ต่อคำต่อคอลัมน์: ตรงทั้งข้อความ = 3, ขึ้นต้นด้วยคำค้น = 2, มีอยู่ภายใน = 1 แล้วรวมทุกคำและคอลัมน์
+func exactRelevance(terms []string) (string, []interface{}) {
+	var cases []string
+	var args []interface{}
+	for _, term := range terms {
+		escaped := escapeLike(term)
+		for _, column := range searchColumns {
+			cases = append(cases, "CASE WHEN LOWER("+column+") = ? THEN 3"+
+				" WHEN LOWER("+column+") LIKE ? ESCAPE '!' THEN 2"+
+				" WHEN LOWER("+column+") LIKE ? ESCAPE '!' THEN 1 ELSE 0 END")
+			args = append(args, term, escaped+"%", "%"+escaped+"%")
+		}
+	}
+	return sumExpression(cases), args
+}
+
+// patternMatches สร้างนิพจน์จำนวน n-gram ที่ตรง (ต่อคอลัมน์) สำหรับ ORDER BY พร้อม args
+// ผู้ใช้ที่พิมพ์ผิดน้อยมี n-gram ที่ตรงมากกว่าผู้ใช้ที่ตรงเพียง n-gram ทั่วไปบางตัว
+func patternMatches(patterns []string) (string, []interface{}) {
+	var cases []string
+	var args []interface{}
+	for _, pattern := range patterns {
+		like := "%" + escapeLike(strings.ToLower(pattern)) + "%"
+		for _, column := range searchColumns {
+			cases = append(cases, "CASE WHEN LOWER("+column+") LIKE ? ESCAPE '!' THEN 1 ELSE 0 END")
+			args = append(args, like)
+		}
+	}
+	return sumExpression(cases), args
+}
+
+// sumExpression รวมนิพจน์ตัวเลขด้วย + (ไม่มีนิพจน์ = 0)
+func sumExpression(parts []string) string {
+	if len(parts) == 0 {
+		return "0"
+	}
+	return "(" + strings.Join(parts, " + ") + ")"
+}
+
+// likeAny สร้างเงื่อนไขว่าคอลัมน์ใดก็ได้ใน searchColumns มีข้อความใดก็ได้ใน values (ไม่สนตัวพิมพ์) พร้อม args
+func likeAny(values []string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	for _, value := range values {
+		pattern := "%" + escapeLike(strings.ToLower(value)) + "%"
+		for _, column := range searchColumns {
+			conditions = append(conditions, "LOWER("+column+") LIKE ? ESCAPE '!'")
+			args = append(args, pattern)
+		}
+	}
+	if len(conditions) == 0 {
+		return "1 = 0", nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// escapeLike escape อักขระพิเศษของ LIKE ด้วย ! (ใช้คู่กับ ESCAPE '!' ที่เหมือนกันทุก driver)
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
+
+// fulltextQuery สร้างคำค้นของ MATCH ... AGAINST แบบ BOOLEAN MODE ให้ทุกคำตรงแบบขึ้นต้น (word*)
+// ตัดเครื่องหมายที่เป็น operator ของ BOOLEAN MODE ออก (คำสั้นกว่าขนาดขั้นต่ำของดัชนีจะถูก MySQL ข้ามเอง)
+func fulltextQuery(terms []string) string {
+	var words []string
+	for _, term := range terms {
+		for _, word := range strings.FieldsFunc(term, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+		}) {
+			words = append(words, word+"*")
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/database"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// webhookColumns คอลัมน์ของตาราง webhooks ที่อ่านเข้าสู่ models.Webhook
+const webhookColumns = "id, url, description, events, secret, active, created_at, updated_at"
+
+// deliveryColumns คอลัมน์ของตาราง webhook_deliveries (alias d) ที่อ่านเข้าสู่ models.WebhookDelivery
+const deliveryColumns = "d.id, d.webhook_id, d.event_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at," +
+	" d.last_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at"
+
+// maxErrorLength ความยาวสูงสุดของ last_error เป็นตัวอักษร (ตามขนาดคอลัมน์)
+const maxErrorLength = 1000
+
+// WebhookRepository จัดการข้อมูลในตาราง webhooks และ webhook_deliveries (outbox)
+type WebhookRepository struct {
+	DB *sqlx.DB // การเชื่อมต่อฐานข้อมูล
+}
+
+// NewWebhookRepository สร้าง WebhookRepository ใหม่
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+// Create บันทึก webhook ใหม่และคืนค่า ID
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) (int, error) {
+	now := time.Now().UTC()
+	query := "INSERT INTO webhooks (url, description, events, secret, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	return database.InsertID(ctx, r.DB, query, webhook.URL, webhook.Description, webhook.Events, webhook.Secret, webhook.Active, now, now)
+}
+
+// List คืนค่า webhook ทั้งหมด เรียงตาม ID
+func (r *WebhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	webhooks := []models.Webhook{}
+	if err := r.DB.SelectContext(ctx, &webhooks, "SELECT "+webhookColumns+" FROM webhooks ORDER BY id"); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetByID ค้นหา webhook ตาม ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*models.Webhook, error) {
+	var webhook models.Webhook
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE id = ?"
+	if err := r.DB.GetContext(ctx, &webhook, r.DB.Rebind(query), id); err != nil {
+		return nil, notFound(err)
+	}
+	return &webhook, nil
+}
+
+// Update บันทึก URL, คำอธิบาย, event, secret และสถานะการเปิดใช้ของ webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	query := "UPDATE webhooks SET url = ?, description = ?, events = ?, secret = ?, active = ?, updated_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), webhook.URL, webhook.Description, webhook.Events, webhook.Secret,
+		webhook.Active, time.Now().UTC(), webhook.ID)
+	return err
+}
+
+// Delete ลบ webhook พร้อมบันทึกการส่งทั้งหมด คืนค่า false หากไม่พบ
+func (r *WebhookRepository) Delete(ctx context.Context, id int) (bool, error) {
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind("DELETE FROM webhooks WHERE id = ?"), id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Enqueue เพิ่มการส่ง event ให้ทุก webhook ที่เปิดใช้และสมัครรับ event นี้ (ไม่มี webhook = ไม่บันทึก)
+// ทุกแถวของ event เดียวกันใช้ eventID (ID ของ domain event) และ payload เดียวกัน
+func (r *WebhookRepository) Enqueue(ctx context.Context, ext sqlx.ExtContext, event, eventID string, occurredAt time.Time, data interface{}) error {
+	var webhooks []models.Webhook
+	if err := sqlx.SelectContext(ctx, ext, &webhooks, ext.Rebind("SELECT "+webhookColumns+" FROM webhooks WHERE active = ?"), true); err != nil {
+		return err
+	}
+	var targets []int
+	for i := range webhooks {
+		if webhooks[i].Subscribes(event) {
+			targets = append(targets, webhooks[i].ID)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(models.WebhookPayload{ID: eventID, Event: event, CreatedAt: occurredAt, Data: data})
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, webhookID := range targets {
+		if _, err := insertDelivery(ctx, ext, webhookID, eventID, event, string(payload), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliveries คืนค่าบันทึกการส่งของ webhook จากใหม่ไปเก่า ไม่เกิน limit รายการ
+// beforeID มากกว่า 0 จะคืนเฉพาะบันทึกที่เก่ากว่า ID นั้น (ใช้แบ่งหน้าต่อจากรายการสุดท้าย)
+func (r *WebhookRepository) Deliveries(ctx context.Context, webhookID, beforeID, limit int) ([]models.WebhookDelivery, error) {
+	deliveries := []models.WebhookDelivery{}
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries d WHERE d.webhook_id = ?"
+	args := []interface{}{webhookID}
+	if beforeID > 0 {
+		query += " AND d.id < ?"
+		args = append(args, beforeID)
+	}
+	query += " ORDER BY d.id DESC LIMIT ?"
+	if err := r.DB.SelectContext(ctx, &deliveries, r.DB.Rebind(query), append(args, limit)...); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// GetDelivery ค้นหาบันทึกการส่งตาม ID ของ webhook ที่ระบุ
+func (r *WebhookRepository) GetDelivery(ctx context.Context, webhookID, id int) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries d WHERE d.id = ? AND d.webhook_id = ?"
+	if err := r.DB.GetContext(ctx, &delivery, r.DB.Rebind(query), id, webhookID); err != nil {
+		return nil, notFound(err)
+	}
+	return &delivery, nil
+}
+
+// Redeliver เพิ่มการส่งใหม่ของ event เดิม (event_id และ payload เดิม) ให้ worker ส่งทันที และคืนค่าการส่งใหม่
+func (r *WebhookRepository) Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	id, err := insertDelivery(ctx, r.DB, delivery.WebhookID, delivery.EventID, delivery.Event, delivery.Payload, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return r.GetDelivery(ctx, delivery.WebhookID, id)
+}
+
+// PendingDelivery การส่งที่ถึงเวลาพร้อมปลายทางและ secret ของ webhook
+type PendingDelivery struct {
+	models.WebhookDelivery
+	URL    string `db:"url"`    // ปลายทาง
+	Secret string `db:"secret"` // secret สำหรับเซ็น
+}
+
+// Due คืนค่าการส่งที่ถึงเวลาของ webhook ที่เปิดใช้อยู่ เรียงตามเวลาที่ควรส่ง ไม่เกิน limit รายการ
+// การส่งของ webhook ที่ปิดไว้ยังค้างอยู่ และถูกส่งเมื่อเปิดใช้อีกครั้ง
+func (r *WebhookRepository) Due(ctx context.Context, now time.Time, limit int) ([]PendingDelivery, error) {
+	deliveries := []PendingDelivery{}
+	query := "SELECT " + deliveryColumns + ", w.url, w.secret FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id" +
+		" WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active = ? ORDER BY d.next_attempt_at, d.id LIMIT ?"
+	if err := r.DB.SelectContext(ctx, &deliveries, r.DB.Rebind(query), models.WebhookDeliveryPending, now, true, limit); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Claim จองการส่งที่ถึงเวลา (ณ now) ไว้จนถึง until เพื่อไม่ให้ worker อื่น (หลาย instance) ส่งซ้ำระหว่างที่กำลังส่ง
+// คืนค่า false เมื่อ worker อื่นจองไปแล้วหรือสถานะเปลี่ยนไปแล้ว
+func (r *WebhookRepository) Claim(ctx context.Context, id int, now, until time.Time) (bool, error) {
+	query := "UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ? AND status = ? AND next_attempt_at <= ?"
+	result, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), until, id, models.WebhookDeliveryPending, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// RecordAttempt บันทึกผลการส่งหนึ่งครั้ง
+// สำเร็จ (deliveryErr เป็น nil) = delivered, ไม่สำเร็จและ next ไม่เป็น nil = pending รอส่งซ้ำ, ไม่สำเร็จและ next เป็น nil = failed
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id int, now time.Time, responseStatus *int, deliveryErr error, next *time.Time) error {
+	status := models.WebhookDeliveryDelivered
+	var lastError *string
+	var deliveredAt *time.Time
+	if deliveryErr != nil {
+		status = models.WebhookDeliveryFailed
+		if next != nil {
+			status = models.WebhookDeliveryPending
+		}
+		text := truncateError(deliveryErr)
+		lastError = &text
+	} else {
+		deliveredAt = &now
+	}
+	query := "UPDATE webhook_deliveries SET status = ?, attempts = attempts + 1, next_attempt_at = ?, last_attempt_at = ?," +
+		" response_status = ?, last_error = ?, delivered_at = ? WHERE id = ?"
+	_, err := r.DB.ExecContext(ctx, r.DB.Rebind(query), status, next, now, responseStatus, lastError, deliveredAt, id)
+	return err
+}
+
+// insertDelivery เพิ่มการส่งที่รอส่งทันทีลง outbox
+func insertDelivery(ctx context.Context, ext sqlx.ExtContext, webhookID int, eventID, event, payload string, now time.Time) (int, error) {
+	query := "INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)" +
+		" VALUES (?, ?, ?, ?, ?, 0, ?, ?)"
+	return database.InsertID(ctx, ext, query, webhookID, eventID, event, payload, models.WebhookDeliveryPending, now, now)
+}
@@ -2,23 +2,55 @@ package routes
 
 import (
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/Sing254463/GoTemplate/Backend/config"
 	"github.com/Sing254463/GoTemplate/Backend/controllers"
 	"github.com/Sing254463/GoTemplate/Backend/middleware"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
+	"github.com/jmoiron/sqlx"
 )
 
 // SetupRoutes ฟังก์ชันสำหรับตั้งค่าเส้นทาง (routes) ทั้งหมดของ API
-// รับพารามิเตอร์ app (Fiber app) และ cfg (configuration)
-func SetupRoutes(app *fiber.App, cfg *config.Config) {
+// รับพารามิเตอร์ app (Fiber app), store (การตั้งค่าที่ reload ได้) และ db (การเชื่อมต่อฐานข้อมูล)
+func SetupRoutes(app *fiber.App, store *config.Store, db *sqlx.DB) {
 	// สร้างและเตรียมคอนโทรลเลอร์สำหรับจัดการคำร้องขอ
 	// authController จัดการเรื่องการลงทะเบียน, เข้าสู่ระบบ, และโปรไฟล์
-	authController := controllers.NewAuthController(cfg)
+	authController := controllers.NewAuthController(store, db)
 	// userController จัดการเรื่องข้อมูลผู้ใช้ (สำหรับ admin เท่านั้น)
-	userController := controllers.NewUserController(cfg)
+	userController := controllers.NewUserController(store, db)
+	// apiKeyController จัดการ API key ของ client แบบ service-to-service (สำหรับ admin เท่านั้น)
+	apiKeyController := controllers.NewAPIKeyController(store, db)
+	// oauthController จัดการการเข้าสู่ระบบผ่าน Google/GitHub (OIDC/OAuth2)
+	oauthController := controllers.NewOAuthController(store, db)
+	// oauthServerController ทำหน้าที่ OAuth2 authorization server ให้แอปของพาร์ทเนอร์
+	oauthServerController := controllers.NewOAuthServerController(store, db)
+	// oauthClientController จัดการการลงทะเบียนแอปกับ authorization server (สำหรับ admin เท่านั้น)
+	oauthClientController := controllers.NewOAuthClientController(store, db)
+	// webhookController จัดการ webhook ที่ส่ง event ของผู้ใช้ไปยังระบบภายนอก (สำหรับ admin เท่านั้น)
+	webhookController := controllers.NewWebhookController(store, db)
+	// sessionController จัดการ session การเข้าสู่ระบบ (อุปกรณ์) ของผู้ใช้
+	sessionController := controllers.NewSessionController(store, db)
+	// invitationController จัดการคำเชิญผู้ใช้ (สำหรับ admin ของระบบหรือขององค์กร)
+	invitationController := controllers.NewInvitationController(store, db)
+	// organizationController จัดการองค์กร (tenant) และสมาชิก (สร้าง/จัดการสมาชิกเฉพาะ admin ของระบบ)
+	organizationController := controllers.NewOrganizationController(store, db)
+	// groupController จัดการกลุ่ม (ทีม) ของผู้ใช้ในองค์กรและสมาชิกของกลุ่ม
+	groupController := controllers.NewGroupController(store, db)
+	// profileController จัดการข้อมูลโปรไฟล์และรูปโปรไฟล์ของผู้ใช้ที่เข้าสู่ระบบ
+	profileController := controllers.NewProfileController(store, db)
+	// auditLogController แสดงบันทึกการกระทำของผู้ดูแล (สำหรับ admin ของระบบหรือขององค์กร)
+	auditLogController := controllers.NewAuditLogController(store, db)
+	// oauthTokens ใช้ตรวจการเพิกถอน access token ของแอปใน JWTMiddleware
+	oauthTokens := repository.NewOAuthRepository(db)
+	// sessions ใช้ตรวจว่า session ของ token ผู้ใช้ยังไม่ถูกเพิกถอนใน JWTMiddleware
+	sessions := repository.NewSessionRepository(db)
+	// accountStatuses จำสถานะบัญชีของผู้ใช้ (JWT_STATUS_CACHE_TTL) เพื่อปฏิเสธ token และ API key ของบัญชีที่ถูกระงับ
+	accountStatuses := middleware.NewAccountStatusCache(store, repository.NewUserRepository(db))
 
 	// ตั้งค่าเส้นทางสำหรับ Swagger UI (เอกสาร API)
 	// เส้นทาง /swagger แสดงหน้า Swagger UI หลัก
@@ -29,6 +61,16 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 	// เส้นทาง /swagger/* สำหรับไฟล์ static ของ Swagger
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
+	// ให้บริการไฟล์ที่อัปโหลด (รูปโปรไฟล์) จากโฟลเดอร์ของ driver local
+	// เมื่อ STORAGE_PUBLIC_URL เป็น URL เต็ม (CDN/web server) ไฟล์ถูกให้บริการจากที่อื่น
+	if cfg := store.Get().Storage; cfg.Driver == "local" && strings.HasPrefix(cfg.PublicURL, "/") {
+		app.Static(cfg.PublicURL, cfg.LocalDir, fiber.Static{MaxAge: 86400})
+	}
+
+	// metadata ของ OAuth2 authorization server (RFC 8414) ต้องอยู่ที่ root ของ issuer
+	// ไม่เผยแพร่ /.well-known/openid-configuration เพราะระบบไม่ใช่ OpenID Provider (ไม่มี id_token และ jwks_uri)
+	app.Get("/.well-known/oauth-authorization-server", oauthServerController.Discovery)
+
 	// สร้างกลุ่มเส้นทางหลักสำหรับ API version 1
 	// ทุกเส้นทาง API จะเริ่มต้นด้วย /api/v1
 	api := app.Group("/api/v1")
@@ -36,6 +78,7 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 	// เส้นทางตรวจสอบสถานะเซิร์ฟเวอร์ (Health Check)
 	// ใช้เพื่อตรวจสอบว่าเซิร์ฟเวอร์ทำงานปกติหรือไม่
 	api.Get("/health", func(c *fiber.Ctx) error {
+		cfg := store.Get()
 		return c.JSON(fiber.Map{
 			"status":      "ok",
 			"message":     "GoTemplate API is running!",
@@ -49,6 +92,7 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 
 	// เส้นทางสำหรับแสดงข้อมูลเวอร์ชัน
 	api.Get("/version", func(c *fiber.Ctx) error {
+		cfg := store.Get()
 		return c.JSON(fiber.Map{
 			"app_name":    cfg.App.Name,
 			"version":     cfg.App.Version,
@@ -61,23 +105,124 @@ func SetupRoutes(app *fiber.App, cfg *config.Config) {
 	// กลุ่มเส้นทางสำหรับการจัดการการยืนยันตัวตน (Authentication)
 	// เส้นทางเหล่านี้เปิดให้สาธารณะเข้าถึงได้ (ไม่ต้องเข้าสู่ระบบ)
 	auth := api.Group("/auth")
-	auth.Post("/register", authController.Register) // ลงทะเบียนผู้ใช้ใหม่
-	auth.Post("/login", authController.Login)       // เข้าสู่ระบบ
+	auth.Post("/register", authController.Register)          // ลงทะเบียนผู้ใช้ใหม่ (ปิดได้ด้วย REGISTRATION_ENABLED)
+	auth.Post("/accept-invite", authController.AcceptInvite) // รับคำเชิญและตั้งชื่อผู้ใช้/รหัสผ่าน
+	auth.Post("/login", authController.Login)                // เข้าสู่ระบบ
+
+	// เข้าสู่ระบบผ่านผู้ให้บริการภายนอก (provider: google, github)
+	auth.Get("/oauth/:provider/start", oauthController.StartOAuth)       // redirect ไปหน้าเข้าสู่ระบบของ provider
+	auth.Get("/oauth/:provider/callback", oauthController.OAuthCallback) // รับผลจาก provider และออก JWT
+
+	// endpoint ของ OAuth2 authorization server ที่แอปเรียกโดยตรง (ยืนยันตัวตนด้วย client_id/client_secret)
+	oauthServer := api.Group("/oauth")
+	oauthServer.Post("/token", oauthServerController.Token)           // แลก code / client credentials / refresh token เป็น access token
+	oauthServer.Post("/introspect", oauthServerController.Introspect) // ตรวจสถานะ token (RFC 7662)
+	oauthServer.Post("/revoke", oauthServerController.Revoke)         // เพิกถอน token (RFC 7009)
 
 	// กลุ่มเส้นทางที่ต้องมีการยืนยันตัวตน (Protected Routes)
-	// ต้องส่ง JWT Token ใน Authorization header จึงจะเข้าถึงได้
+	// ต้องส่ง JWT Token (Authorization: Bearer หรือ cookie ของ session) หรือ API key (X-API-Key / Authorization: ApiKey) จึงจะเข้าถึงได้
+	// API key ถูกจำกัดเพิ่มเติมด้วย scope ของแต่ละเส้นทาง (RequireScope)
 	protected := api.Group("")
-	protected.Use(middleware.JWTMiddleware(cfg.JWT.Secret)) // ใช้ middleware ตรวจสอบ JWT
+	protected.Use(middleware.Authenticate(
+		middleware.JWTMiddleware(store, oauthTokens, sessions, accountStatuses),          // ผู้ใช้ที่เข้าสู่ระบบด้วย JWT และแอปที่ได้ access token จาก OAuth2
+		middleware.APIKeyMiddleware(repository.NewAPIKeyRepository(db), accountStatuses), // client ที่ใช้ API key
+	), middleware.AuditImpersonation(repository.NewAuditRepository(db)), // บันทึกทุก request ของ token ที่ Admin เข้าสู่ระบบแทนผู้ใช้ (รวมที่ถูก middleware ถัดไปปฏิเสธ)
+		middleware.CSRFMiddleware(store),                                             // ตรวจ CSRF token เมื่อยืนยันตัวตนด้วย cookie ของ session
+		middleware.TenantMiddleware(store, repository.NewOrganizationRepository(db))) // เลือกองค์กรจาก header, subdomain หรือ token
 
 	// เส้นทางที่ต้องเข้าสู่ระบบสำหรับข้อมูลส่วนตัว
 	authProtected := protected.Group("/auth")
-	authProtected.Get("/profile", authController.GetProfile) // ดูข้อมูลโปรไฟล์ตนเอง
+	authProtected.Get("/profile", middleware.RequireScope(models.ScopeProfileRead), authController.GetProfile)                       // ดูข้อมูลโปรไฟล์ตนเอง
+	authProtected.Patch("/profile", middleware.RequireScope(models.ScopeProfileWrite), profileController.UpdateProfile)              // แก้ไขข้อมูลโปรไฟล์ (ชื่อที่แสดง, ภาษา, เขตเวลา ฯลฯ)
+	authProtected.Post("/profile/avatar", middleware.RequireScope(models.ScopeProfileWrite), profileController.UploadAvatar)         // อัปโหลดรูปโปรไฟล์ (multipart field avatar)
+	authProtected.Delete("/profile/avatar", middleware.RequireScope(models.ScopeProfileWrite), profileController.DeleteAvatar)       // ลบรูปโปรไฟล์
+	authProtected.Get("/profile/groups", middleware.RequireScope(models.ScopeProfileRead), groupController.GetMyGroups)              // ดูกลุ่มที่เป็นสมาชิกในองค์กรที่เลือก
+	authProtected.Post("/logout", authController.Logout)                                                                             // ออกจากระบบ (เพิกถอน session และลบ cookie)
+	authProtected.Post("/password", middleware.BlockImpersonation(), authController.ChangePassword)                                  // เปลี่ยนรหัสผ่าน (เพิกถอน session อื่น)
+	authProtected.Get("/sessions", sessionController.GetMySessions)                                                                  // ดูอุปกรณ์ที่เข้าสู่ระบบอยู่
+	authProtected.Delete("/sessions/:id", middleware.BlockImpersonation(), sessionController.RevokeMySession)                        // ออกจากระบบบนอุปกรณ์อื่น
+	authProtected.Get("/organizations", middleware.RequireScope(models.ScopeProfileRead), organizationController.GetMyOrganizations) // ดูองค์กรที่เป็นสมาชิก
 
 	// กลุ่มเส้นทางสำหรับจัดการผู้ใช้ (User Management)
-	// ต้องเป็น Admin เท่านั้นถึงจะเข้าถึงได้
+	// ต้องเป็น Admin ของระบบ หรือ Admin ขององค์กร (เห็นเฉพาะผู้ใช้ในองค์กรที่เลือก) ถึงจะเข้าถึงได้
 	users := protected.Group("/users")
-	users.Use(middleware.AdminMiddleware())         // ใช้ middleware ตรวจสอบสิทธิ์ Admin
-	users.Get("/", userController.GetAllUsers)      // ดูรายชื่อผู้ใช้ทั้งหมด
-	users.Get("/:id", userController.GetUserByID)   // ดูข้อมูลผู้ใช้ตาม ID
-	users.Delete("/:id", userController.DeleteUser) // ลบผู้ใช้ตาม ID
+	users.Use(middleware.TenantAdminMiddleware()) // ใช้ middleware ตรวจสอบสิทธิ์ Admin ของระบบหรือขององค์กร
+	// คำเชิญ, import, batch และ export ต้องลงทะเบียนก่อน /:id เพื่อไม่ให้ถูกอ่านเป็น ID ผู้ใช้
+	users.Post("/invitations", middleware.RequireScope(models.ScopeUsersWrite), invitationController.CreateInvitation)            // เชิญผู้ใช้ด้วยอีเมลและ role
+	users.Get("/invitations", middleware.RequireScope(models.ScopeUsersRead), invitationController.GetInvitations)                // ดูรายการคำเชิญ
+	users.Post("/invitations/:id/resend", middleware.RequireScope(models.ScopeUsersWrite), invitationController.ResendInvitation) // ส่งคำเชิญใหม่ (token ใหม่)
+	users.Delete("/invitations/:id", middleware.RequireScope(models.ScopeUsersWrite), invitationController.RevokeInvitation)      // เพิกถอนคำเชิญ
+	users.Post("/import", middleware.RequireScope(models.ScopeUsersWrite), userController.ImportUsers)                            // นำเข้าผู้ใช้จาก CSV/NDJSON (dry_run=true = ตรวจอย่างเดียว)
+	users.Post("/batch", middleware.RequireScope(models.ScopeUsersWrite), userController.BatchUsers)                              // จัดการผู้ใช้หลายราย (delete, set_role, disable = ระงับ, enable)
+	users.Get("/export", middleware.RequireScope(models.ScopeUsersRead), userController.ExportUsers)                              // ส่งออกผู้ใช้เป็น CSV/NDJSON แบบ streaming
+	users.Get("/search", middleware.RequireScope(models.ScopeUsersRead), userController.SearchUsers)                              // ค้นหาผู้ใช้แบบทนการพิมพ์ผิด เรียงตามความตรง (q, page, per_page)
+	users.Get("/", middleware.RequireScope(models.ScopeUsersRead), userController.GetAllUsers)                                    // ดูรายชื่อผู้ใช้ทั้งหมด
+	users.Get("/:id", middleware.RequireScope(models.ScopeUsersRead), userController.GetUserByID)                                 // ดูข้อมูลผู้ใช้ตาม ID
+	users.Delete("/:id", middleware.RequireScope(models.ScopeUsersWrite), userController.DeleteUser)                              // ลบผู้ใช้ตาม ID
+	users.Post("/:id/suspend", middleware.RequireScope(models.ScopeUsersWrite), userController.SuspendUser)                       // ระงับบัญชี (เฉพาะ Admin ของระบบ)
+	users.Post("/:id/impersonate", middleware.BlockImpersonation(), userController.ImpersonateUser)                               // เข้าสู่ระบบแทนผู้ใช้ (เฉพาะ Admin ของระบบ, token อายุสั้น)
+	users.Post("/:id/reinstate", middleware.RequireScope(models.ScopeUsersWrite), userController.ReinstateUser)                   // ยกเลิกการระงับหรือเปิดใช้บัญชี (เฉพาะ Admin ของระบบ)
+	users.Get("/:id/sessions", middleware.RequireScope(models.ScopeUsersRead), sessionController.GetUserSessions)                 // ดูอุปกรณ์ที่ผู้ใช้เข้าสู่ระบบอยู่
+	users.Delete("/:id/sessions/:sid", middleware.RequireScope(models.ScopeUsersWrite), sessionController.RevokeUserSession)      // เพิกถอน session ของผู้ใช้
+
+	// กลุ่มเส้นทางสำหรับจัดการองค์กรและสมาชิก (เฉพาะ Admin ของระบบ)
+	organizations := protected.Group("/organizations")
+	organizations.Use(middleware.AdminMiddleware())
+	organizations.Post("/", middleware.RequireScope(models.ScopeUsersWrite), organizationController.CreateOrganization)                // สร้างองค์กร
+	organizations.Get("/", middleware.RequireScope(models.ScopeUsersRead), organizationController.GetOrganizations)                    // ดูรายการองค์กร
+	organizations.Put("/:id/members/:userId", middleware.RequireScope(models.ScopeUsersWrite), organizationController.SetMember)       // เพิ่มสมาชิกหรือเปลี่ยน role ในองค์กร
+	organizations.Delete("/:id/members/:userId", middleware.RequireScope(models.ScopeUsersWrite), organizationController.RemoveMember) // นำสมาชิกออกจากองค์กร
+
+	// บันทึกการกระทำของผู้ดูแล (Admin ขององค์กรเห็นเฉพาะขององค์กรที่เลือก)
+	auditLogs := protected.Group("/audit-logs")
+	auditLogs.Use(middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersRead))
+	auditLogs.Get("/", auditLogController.GetAuditLogs) // ดูบันทึกจากใหม่ไปเก่า
+
+	// กลุ่มเส้นทางสำหรับจัดการกลุ่มของผู้ใช้ในองค์กรที่เลือก
+	// สร้าง/แก้ไข/ลบกลุ่มต้องเป็น Admin ของระบบหรือขององค์กร ส่วนสมาชิกจัดการได้โดย owner ของกลุ่มด้วย (ตรวจใน controller)
+	groups := protected.Group("/groups")
+	groups.Post("/", middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersWrite), groupController.CreateGroup)      // สร้างกลุ่ม
+	groups.Get("/", middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersRead), groupController.GetGroups)          // ดูรายการกลุ่ม
+	groups.Get("/:id", middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersRead), groupController.GetGroup)        // ดูข้อมูลกลุ่มตาม ID
+	groups.Patch("/:id", middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersWrite), groupController.UpdateGroup)  // แก้ไขชื่อและคำอธิบายของกลุ่ม
+	groups.Delete("/:id", middleware.TenantAdminMiddleware(), middleware.RequireScope(models.ScopeUsersWrite), groupController.DeleteGroup) // ลบกลุ่มพร้อมสมาชิก
+	groups.Get("/:id/members", middleware.RequireScope(models.ScopeUsersRead), groupController.GetGroupMembers)                             // ดูสมาชิกของกลุ่ม
+	groups.Put("/:id/members/:userId", middleware.RequireScope(models.ScopeUsersWrite), groupController.SetGroupMember)                     // เพิ่มสมาชิกหรือเปลี่ยน role ในกลุ่ม
+	groups.Delete("/:id/members/:userId", middleware.RequireScope(models.ScopeUsersWrite), groupController.RemoveGroupMember)               // นำสมาชิกออกจากกลุ่ม
+
+	// กลุ่มเส้นทางสำหรับจัดการ API key (เฉพาะ Admin)
+	// key ที่ใช้เรียกเส้นทางเหล่านี้ต้องมี scope api_keys:manage
+	apiKeys := protected.Group("/api-keys")
+	apiKeys.Use(middleware.AdminMiddleware(), middleware.RequireScope(models.ScopeAPIKeysManage))
+	apiKeys.Post("/", apiKeyController.CreateAPIKey)      // สร้าง API key (แสดง key เต็มครั้งเดียว)
+	apiKeys.Get("/", apiKeyController.GetAPIKeys)         // ดูรายการ API key
+	apiKeys.Get("/:id", apiKeyController.GetAPIKey)       // ดูข้อมูล API key ตาม ID
+	apiKeys.Patch("/:id", apiKeyController.UpdateAPIKey)  // แก้ไขชื่อ, scope, เวลาหมดอายุ
+	apiKeys.Delete("/:id", apiKeyController.RevokeAPIKey) // เพิกถอน API key
+
+	// ขั้นตอนอนุญาตแอป (authorization code flow) เรียกจากหน้า login/consent ของ frontend ด้วย token ของผู้ใช้
+	oauthAuthorize := protected.Group("/oauth/authorize")
+	oauthAuthorize.Get("/", oauthServerController.Authorize)                                          // ตรวจคำขอ: ออก code ทันที หรือแจ้งว่าต้องขอความยินยอม
+	oauthAuthorize.Post("/", middleware.BlockImpersonation(), oauthServerController.ApproveAuthorize) // ผู้ใช้อนุญาตหรือปฏิเสธ (เจ้าของบัญชีเท่านั้น)
+
+	// กลุ่มเส้นทางสำหรับลงทะเบียนแอปกับ OAuth2 authorization server (เฉพาะ Admin)
+	// key ที่ใช้เรียกเส้นทางเหล่านี้ต้องมี scope oauth_clients:manage
+	oauthClients := protected.Group("/oauth/clients")
+	oauthClients.Use(middleware.AdminMiddleware(), middleware.RequireScope(models.ScopeOAuthClients))
+	oauthClients.Post("/", oauthClientController.CreateOAuthClient)      // ลงทะเบียนแอป (แสดง client secret ครั้งเดียว)
+	oauthClients.Get("/", oauthClientController.GetOAuthClients)         // ดูรายการแอป
+	oauthClients.Get("/:id", oauthClientController.GetOAuthClient)       // ดูข้อมูลแอปตาม ID
+	oauthClients.Delete("/:id", oauthClientController.DeleteOAuthClient) // ลบแอปพร้อม token ทั้งหมด
+
+	// กลุ่มเส้นทางสำหรับจัดการ webhook และดูบันทึกการส่ง (เฉพาะ Admin)
+	// key ที่ใช้เรียกเส้นทางเหล่านี้ต้องมี scope webhooks:manage
+	webhooks := protected.Group("/webhooks")
+	webhooks.Use(middleware.AdminMiddleware(), middleware.RequireScope(models.ScopeWebhooks))
+	webhooks.Post("/", webhookController.CreateWebhook)                                        // ลงทะเบียน webhook (แสดง secret ครั้งเดียว)
+	webhooks.Get("/", webhookController.GetWebhooks)                                           // ดูรายการ webhook
+	webhooks.Get("/:id", webhookController.GetWebhook)                                         // ดูข้อมูล webhook ตาม ID
+	webhooks.Patch("/:id", webhookController.UpdateWebhook)                                    // แก้ไข URL, event, สถานะ หรือสร้าง secret ใหม่
+	webhooks.Delete("/:id", webhookController.DeleteWebhook)                                   // ลบ webhook พร้อมบันทึกการส่ง
+	webhooks.Get("/:id/deliveries", webhookController.GetWebhookDeliveries)                    // ดูบันทึกการส่ง
+	webhooks.Post("/:id/deliveries/:deliveryId/redeliver", webhookController.RedeliverWebhook) // ส่ง event เดิมซ้ำ
 }
@@ -0,0 +1,291 @@
+// Package search ให้คะแนนและไฮไลต์ข้อความที่ตรงกับคำค้นแบบทนต่อการพิมพ์ผิด (fuzzy)
+// ฐานข้อมูลใช้หาผู้สมัคร (candidate) อย่างหยาบด้วย FULLTEXT หรือ LIKE ของ n-gram แล้ว package นี้จัดอันดับอีกครั้ง
+// จึงได้ผลลัพธ์เหมือนกันทุก driver
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinQueryLength ความยาวขั้นต่ำของคำค้น (ตัวอักษร) สั้นกว่านี้ตรงกับผู้ใช้เกือบทั้งหมด
+const MinQueryLength = 2
+
+// MinScore คะแนนต่ำสุดที่ถือว่าตรงกับคำค้น (เช่น คำค้นสองคำที่พิมพ์ผิดเล็กน้อยทั้งคู่ยังผ่าน)
+const MinScore = 0.3
+
+// maxPatterns จำนวน pattern สูงสุดของ Patterns (จำกัดขนาดของ WHERE)
+const maxPatterns = 16
+
+// คะแนนของการตรงแต่ละแบบ (1 = ตรงทั้งข้อความ)
+const (
+	scoreExact       = 1.0  // ตรงทั้งข้อความ
+	scorePrefix      = 0.9  // ข้อความขึ้นต้นด้วยคำค้น
+	scoreWord        = 0.85 // ตรงทั้งคำ
+	scoreWordPrefix  = 0.8  // คำขึ้นต้นด้วยคำค้น
+	scoreContains    = 0.7  // มีคำค้นอยู่ภายใน
+	scoreTypo        = 0.6  // พิมพ์ผิด 1 ตัวจากทั้งคำ (ลดลง 0.1 ต่อตัวที่ผิดเพิ่ม)
+	scoreTypoPrefix  = 0.5  // พิมพ์ผิด 1 ตัวจากส่วนต้นของคำ (ลดลง 0.1 ต่อตัวที่ผิดเพิ่ม)
+	typoPenalty      = 0.1
+	exactTermLength  = 3 // คำที่สั้นกว่าหรือเท่ากับนี้ต้องตรงตัว (พิมพ์ผิด 1 ตัวของคำสั้นตรงกับคำอื่นแทบทุกคำ)
+	shortTermLength  = 5 // คำที่สั้นกว่าหรือเท่ากับนี้ทนการพิมพ์ผิดได้ 1 ตัว ยาวกว่าได้ 2 ตัว
+	bigramTermLength = 5 // คำที่สั้นกว่าหรือเท่ากับนี้ใช้ bigram หาผู้สมัคร (trigram ของคำสั้นที่พิมพ์ผิดมักไม่เหลือส่วนที่ตรง)
+)
+
+// Terms แยกคำค้นเป็นคำตัวพิมพ์เล็ก (ตัดช่องว่างและเครื่องหมาย ยกเว้นเครื่องหมายในอีเมล) โดยไม่เอาคำซ้ำ
+// และคำที่สั้นกว่า MinQueryLength (ตัวอักษรเดียวตรงกับเกือบทุกข้อความ)
+func Terms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, term := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !isWordRune(r) && !strings.ContainsRune("@._-+", r)
+	}) {
+		if !seen[term] && utf8.RuneCountInString(term) >= MinQueryLength {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Patterns คืนค่าส่วนของคำ (ตัวพิมพ์เล็ก) สำหรับหาผู้สมัครด้วย LIKE '%pattern%'
+// คำสั้นใช้ bigram คำยาวใช้ trigram ผู้ใช้ที่พิมพ์ผิดเล็กน้อยจึงยังมีส่วนที่ตรงอย่างน้อยหนึ่ง pattern
+func Patterns(terms []string) []string {
+	var patterns []string
+	seen := map[string]bool{}
+	for _, term := range terms {
+		runes := []rune(term)
+		size := 3
+		if len(runes) <= bigramTermLength {
+			size = 2
+		}
+		if len(runes) <= size {
+			size = len(runes)
+		}
+		for i := 0; i+size <= len(runes); i++ {
+			gram := string(runes[i : i+size])
+			if !seen[gram] {
+				seen[gram] = true
+				patterns = append(patterns, gram)
+			}
+		}
+	}
+	if len(patterns) > maxPatterns {
+		patterns = patterns[:maxPatterns]
+	}
+	return patterns
+}
+
+// Range ช่วงที่ตรงกับคำค้นในข้อความ (ตำแหน่งเป็นจำนวนตัวอักษร ไม่ใช่ byte)
+type Range struct {
+	Start, End int
+}
+
+// Field ข้อความหนึ่ง field ที่ใช้เทียบกับคำค้น
+type Field struct {
+	Name   string  // ชื่อ field ใน Result.Highlights เช่น username
+	Text   string  // ข้อความต้นฉบับ
+	Weight float64 // น้ำหนักของ field (1 = เต็ม)
+}
+
+// Result ผลการเทียบคำค้นกับทุก field ของรายการหนึ่ง
+type Result struct {
+	Score      float64           // คะแนนเฉลี่ยของทุกคำ (0 = ไม่ตรงเลย, 1 = ตรงทุกคำทั้งข้อความ)
+	Highlights map[string]string // field ที่ตรง เป็น HTML ที่ escape แล้วและครอบส่วนที่ตรงด้วย <mark>
+}
+
+// Score เทียบทุกคำค้นกับทุก field คะแนนของแต่ละคำคือการตรงที่ดีที่สุด (คูณน้ำหนักของ field) จาก field ใดก็ได้
+// คำค้นหลายคำจึงตรงต่าง field กันได้ เช่น ชื่อผู้ใช้กับชื่อที่แสดง
+func Score(terms []string, fields []Field) Result {
+	result := Result{Highlights: map[string]string{}}
+	if len(terms) == 0 {
+		return result
+	}
+	ranges := make([][]Range, len(fields))
+	texts := make([][]rune, len(fields))
+	words := make([][]Range, len(fields))
+	for i, field := range fields {
+		texts[i] = []rune(strings.Map(unicode.ToLower, field.Text)) // ทีละตัวอักษร ตำแหน่งจึงตรงกับต้นฉบับ
+		words[i] = splitWords(texts[i])
+	}
+
+	for _, term := range terms {
+		best := 0.0
+		for i, field := range fields {
+			if len(texts[i]) == 0 {
+				continue
+			}
+			score, matched := scoreTerm(texts[i], words[i], []rune(term))
+			ranges[i] = append(ranges[i], matched...)
+			if score*field.Weight > best {
+				best = score * field.Weight
+			}
+		}
+		result.Score += best
+	}
+	result.Score /= float64(len(terms))
+
+	for i, field := range fields {
+		if len(ranges[i]) > 0 {
+			result.Highlights[field.Name] = highlight(field.Text, ranges[i])
+		}
+	}
+	return result
+}
+
+// scoreTerm หาการตรงที่ดีที่สุดของคำค้นหนึ่งคำในข้อความ (ตัวพิมพ์เล็ก)
+func scoreTerm(text []rune, words []Range, term []rune) (float64, []Range) {
+	if index := indexRunes(text, term, 0); index >= 0 {
+		ranges := allOccurrences(text, term)
+		switch {
+		case len(text) == len(term):
+			return scoreExact, ranges
+		case index == 0:
+			return scorePrefix, ranges
+		}
+		best := scoreContains
+		for _, r := range ranges {
+			for _, w := range words {
+				if w.Start == r.Start {
+					if w.End == r.End {
+						best = scoreWord
+					} else if best < scoreWordPrefix {
+						best = scoreWordPrefix
+					}
+				}
+			}
+		}
+		return best, ranges
+	}
+
+	// พิมพ์ผิด: เทียบกับทั้งคำ และกับส่วนต้นของคำที่ยาวเท่าคำค้น
+	if len(term) <= exactTermLength {
+		return 0, nil
+	}
+	tolerance := 1
+	if len(term) > shortTermLength {
+		tolerance = 2
+	}
+	best, bestRange := 0.0, Range{}
+	for _, w := range words {
+		word := text[w.Start:w.End]
+		if d := distance(word, term); d <= tolerance {
+			if score := scoreTypo - typoPenalty*float64(d-1); score > best {
+				best, bestRange = score, w
+			}
+		}
+		if len(word) > len(term) {
+			if d := distance(word[:len(term)], term); d <= tolerance {
+				if score := scoreTypoPrefix - typoPenalty*float64(d-1); score > best {
+					best, bestRange = score, Range{w.Start, w.Start + len(term)}
+				}
+			}
+		}
+	}
+	if best == 0 {
+		return 0, nil
+	}
+	return best, []Range{bestRange}
+}
+
+// highlight คืนค่าข้อความที่ escape HTML แล้ว โดยครอบช่วงที่ตรงด้วย <mark></mark> (ช่วงที่ซ้อนกันถูกรวม)
+func highlight(text string, ranges []Range) string {
+	sorted := append([]Range(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	merged := []Range{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	runes := []rune(text)
+	var b strings.Builder
+	pos := 0
+	for _, r := range merged {
+		b.WriteString(html.EscapeString(string(runes[pos:r.Start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[r.Start:r.End])))
+		b.WriteString("</mark>")
+		pos = r.End
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:])))
+	return b.String()
+}
+
+// splitWords คืนค่าช่วงของคำในข้อความ (คั่นด้วยตัวที่ไม่ใช่ส่วนของคำ เช่น @ . _ ช่องว่าง)
+func splitWords(text []rune) []Range {
+	var words []Range
+	start := -1
+	for i, r := range text {
+		wordRune := isWordRune(r)
+		if wordRune && start < 0 {
+			start = i
+		} else if !wordRune && start >= 0 {
+			words = append(words, Range{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, Range{start, len(text)})
+	}
+	return words
+}
+
+// isWordRune ตรวจว่าเป็นส่วนของคำ: ตัวอักษร ตัวเลข หรือเครื่องหมายกำกับ (เช่น สระบน/ล่างและวรรณยุกต์ของภาษาไทย)
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// indexRunes หาตำแหน่งแรกของ sub ใน text ตั้งแต่ from (-1 = ไม่พบ)
+func indexRunes(text, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(text); i++ {
+		if string(text[i:i+len(sub)]) == string(sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// allOccurrences คืนค่าทุกช่วงที่ sub ปรากฏใน text (ไม่ซ้อนกัน)
+func allOccurrences(text, sub []rune) []Range {
+	var ranges []Range
+	for i := indexRunes(text, sub, 0); i >= 0; i = indexRunes(text, sub, i+len(sub)) {
+		ranges = append(ranges, Range{i, i + len(sub)})
+	}
+	return ranges
+}
+
+// distance ระยะ Damerau-Levenshtein แบบ optimal string alignment (การสลับตัวอักษรที่ติดกันนับเป็น 1)
+func distance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
@@ -0,0 +1,183 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestTerms ตรวจการแยกคำค้น: ตัวพิมพ์เล็ก เก็บเครื่องหมายในอีเมล ไม่เอาคำซ้ำและคำที่สั้นกว่า MinQueryLength
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"John Smith", []string{"john", "smith"}},
+		{"  john, JOHN; j ", []string{"john"}},
+		{"john.doe+dev@example.com", []string{"john.doe+dev@example.com"}},
+		{"สมชาย ใจดี", []string{"สมชาย", "ใจดี"}},
+		{"a b", nil},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, ต้องการ %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+// TestPatterns ตรวจ n-gram ที่ใช้หาผู้สมัคร: bigram ของคำสั้น (คำ 2 ตัวอักษรได้ตัวเอง), trigram ของคำยาว,
+// ไม่ซ้ำ และไม่เกิน maxPatterns
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		want  []string
+	}{
+		{"two characters", []string{"ab"}, []string{"ab"}},
+		{"two multi-byte characters", []string{"สม"}, []string{"สม"}},
+		{"short term uses bigrams", []string{"jon"}, []string{"jo", "on"}},
+		{"bigram limit", []string{"smith"}, []string{"sm", "mi", "it", "th"}},
+		{"long term uses trigrams", []string{"johnson"}, []string{"joh", "ohn", "hns", "nso", "son"}},
+		{"duplicates removed", []string{"abab", "ab"}, []string{"ab", "ba"}},
+		{"capped", []string{"abcdefghijklmnopqrstuvwxyz"}, []string{
+			"abc", "bcd", "cde", "def", "efg", "fgh", "ghi", "hij",
+			"ijk", "jkl", "klm", "lmn", "mno", "nop", "opq", "pqr",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Patterns(tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Patterns(%q) = %q, ต้องการ %q", tt.terms, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestScore ตรวจคะแนนของการตรงแต่ละแบบ รวมการพิมพ์ผิดที่ยังผ่าน MinScore และคำสั้นที่ต้องตรงตัว
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		terms  []string
+		text   string
+		want   float64
+		passes bool // คะแนน >= MinScore
+	}{
+		{"exact", []string{"john"}, "John", scoreExact, true},
+		{"prefix", []string{"john"}, "johnny", scorePrefix, true},
+		{"word", []string{"smith"}, "John Smith", scoreWord, true},
+		{"word prefix", []string{"smi"}, "John Smith", scoreWordPrefix, true},
+		{"contains", []string{"ohn"}, "john", scoreContains, true},
+		{"word with combining marks", []string{"ใจดี"}, "สมชาย ใจดี", scoreWord, true},
+		{"transposition", []string{"jonh"}, "john", scoreTypo, true},
+		{"substitution", []string{"smyth"}, "John Smith", scoreTypo, true},
+		{"typo in prefix", []string{"jhon"}, "johnny", scoreTypoPrefix, true},
+		{"two typos in long term", []string{"jonhsno"}, "johnson", scoreTypo - typoPenalty, true},
+		{"typo in short term", []string{"jon"}, "jan", 0, false},
+		{"too many typos", []string{"jxnh"}, "john", 0, false},
+		{"average of terms", []string{"john", "zzzz"}, "John", scoreExact / 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.terms, []Field{{Name: "name", Text: tt.text, Weight: 1}}).Score
+			if !almostEqual(got, tt.want) {
+				t.Errorf("Score(%q, %q) = %v, ต้องการ %v", tt.terms, tt.text, got, tt.want)
+			}
+			if passes := got >= MinScore; passes != tt.passes {
+				t.Errorf("Score(%q, %q) = %v ผ่าน MinScore = %v, ต้องการ %v", tt.terms, tt.text, got, passes, tt.passes)
+			}
+		})
+	}
+}
+
+// TestScoreFieldWeight ตรวจว่าคะแนนคูณน้ำหนักของ field และแต่ละคำใช้ field ที่ได้คะแนนสูงสุด
+func TestScoreFieldWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		terms  []string
+		fields []Field
+		want   float64
+	}{
+		{
+			"weighted field",
+			[]string{"john"},
+			[]Field{{Name: "email", Text: "john", Weight: 0.9}},
+			0.9,
+		},
+		{
+			"best field wins",
+			[]string{"john"},
+			[]Field{{Name: "email", Text: "john", Weight: 0.9}, {Name: "username", Text: "johnny", Weight: 1}},
+			0.9, // ตรงทั้งข้อความใน email (1 * 0.9) เท่ากับขึ้นต้นใน username (0.9 * 1)
+		},
+		{
+			"heavier field wins",
+			[]string{"john"},
+			[]Field{{Name: "email", Text: "john", Weight: 0.5}, {Name: "username", Text: "john", Weight: 1}},
+			1,
+		},
+		{
+			"terms match different fields",
+			[]string{"john", "smith"},
+			[]Field{{Name: "username", Text: "john", Weight: 1}, {Name: "display_name", Text: "Mr Smith", Weight: 0.8}},
+			(scoreExact + scoreWord*0.8) / 2,
+		},
+		{
+			"empty field ignored",
+			[]string{"john"},
+			[]Field{{Name: "display_name", Text: "", Weight: 1}, {Name: "username", Text: "john", Weight: 1}},
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.terms, tt.fields).Score; !almostEqual(got, tt.want) {
+				t.Errorf("Score = %v, ต้องการ %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestScoreHighlights ตรวจไฮไลต์: escape HTML, ครอบส่วนที่ตรงด้วย <mark> ตามตำแหน่งตัวอักษร (รวมอักษรหลาย byte)
+// รวมช่วงที่ซ้อนกัน และไม่มีไฮไลต์ของ field ที่ไม่ตรง
+func TestScoreHighlights(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		text  string
+		want  string // "" = ไม่มีไฮไลต์
+	}{
+		{"prefix", []string{"john"}, "Johnny", "<mark>John</mark>ny"},
+		{"html escaped", []string{"smith"}, "<b>Smith</b> & co", "&lt;b&gt;<mark>Smith</mark>&lt;/b&gt; &amp; co"},
+		{"escaped inside mark", []string{"a&b"}, "a&b co", "<mark>a&amp;b</mark> co"},
+		{"every occurrence", []string{"an"}, "Ann Anderson", "<mark>An</mark>n <mark>An</mark>derson"},
+		{"thai", []string{"ชาย"}, "สมชาย ใจดี", "สม<mark>ชาย</mark> ใจดี"},
+		{"multi-byte before match", []string{"muller"}, "Zoë Müller", "Zoë <mark>Müller</mark>"},
+		{"typo", []string{"jonh"}, "<john>", "&lt;<mark>john</mark>&gt;"},
+		{"overlapping terms merged", []string{"john", "ohnny"}, "johnny", "<mark>johnny</mark>"},
+		{"no match", []string{"zzzz"}, "<john>", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Score(tt.terms, []Field{{Name: "name", Text: tt.text, Weight: 1}})
+			got, ok := result.Highlights["name"]
+			if tt.want == "" {
+				if ok {
+					t.Errorf("ไฮไลต์ = %q, ต้องการไม่มี", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ไฮไลต์ = %q, ต้องการ %q", got, tt.want)
+			}
+			if plain := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(got); strings.Contains(plain, "<") {
+				t.Errorf("ไฮไลต์ %q มี HTML ที่ไม่ได้ escape", got)
+			}
+		})
+	}
+}
+
+// almostEqual เทียบคะแนนโดยไม่สนความคลาดเคลื่อนของทศนิยม
+func almostEqual(a, b float64) bool {
+	const epsilon = 1e-9
+	return a-b < epsilon && b-a < epsilon
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Listen เริ่มให้บริการ app บน PORT
+//   - ไม่ได้กำหนด TLS_CERT_FILE: ให้บริการผ่าน HTTP ตามเดิม
+//   - กำหนด TLS_CERT_FILE/TLS_KEY_FILE: ให้บริการผ่าน HTTPS และเปิด listener สำหรับ redirect
+//     HTTP → HTTPS บน TLS_REDIRECT_PORT (ถ้ากำหนด)
+//
+// ไม่ใช้ app.ListenTLS เพราะโหลดใบรับรองเพียงครั้งเดียว จึงสร้าง TLS listener เอง
+// โดยใช้ GetCertificate ที่โหลดใบรับรองที่ถูกหมุนเวียนใหม่อัตโนมัติ
+//
+// ฟังก์ชันนี้จะ block จนกว่าเซิร์ฟเวอร์หยุดทำงาน
+func Listen(app *fiber.App, cfg *config.Config) error {
+	addr := ":" + cfg.Server.Port
+	if !cfg.TLS.Enabled() {
+		return app.Listen(addr)
+	}
+
+	tlsConfig, err := NewTLSConfig(cfg.TLS)
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if cfg.TLS.RedirectPort != "" {
+		go func() {
+			if err := listenRedirect(cfg.TLS.RedirectPort, cfg.Server.Port); err != nil {
+				log.Printf("❌ HTTP → HTTPS redirect listener หยุดทำงาน: %v", err)
+			}
+		}()
+	}
+
+	return app.Listener(tls.NewListener(ln, tlsConfig))
+}
+
+// Scheme คืนค่า "https" เมื่อเปิด TLS และ "http" เมื่อไม่เปิด (ใช้แสดง URL ตอนเริ่มระบบ)
+func Scheme(cfg *config.Config) string {
+	if cfg.TLS.Enabled() {
+		return "https"
+	}
+	return "http"
+}
+
+// listenRedirect เปิด HTTP listener ที่ตอบทุก request ด้วย 308 Permanent Redirect ไปยัง HTTPS
+// ใช้ 308 แทน 301 เพื่อให้ client ส่ง method และ body เดิม (เช่น POST) ไปยังปลายทาง
+func listenRedirect(port, httpsPort string) error {
+	redirect := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           redirect,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	log.Printf("↪️  HTTP → HTTPS redirect listening on port %s", port)
+	if err := srv.ListenAndServe(); err != nil {
+		return fmt.Errorf("พอร์ต %s: %w", port, err)
+	}
+	return nil
+}
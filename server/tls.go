@@ -0,0 +1,107 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// certCheckInterval ระยะเวลาขั้นต่ำระหว่างการตรวจว่าไฟล์ใบรับรองถูกแทนที่หรือไม่
+const certCheckInterval = 10 * time.Second
+
+// tlsVersions แปลงค่า TLS_MIN_VERSION เป็นค่าคงที่ของ crypto/tls
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// certReloader โหลดใบรับรองจากไฟล์ และโหลดใหม่เมื่อไฟล์ถูกแก้ไข (ตรวจจากเวลาแก้ไขไฟล์)
+// ใช้กับ tls.Config.GetCertificate เพื่อให้ใบรับรองที่ต่ออายุแล้วมีผลโดยไม่ต้อง restart
+type certReloader struct {
+	certFile, keyFile string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time // เวลาแก้ไขล่าสุดของไฟล์ (ค่าที่ใหม่กว่าระหว่าง cert และ key)
+	checkedAt time.Time // เวลาที่ตรวจไฟล์ครั้งล่าสุด
+}
+
+// newCertReloader โหลดใบรับรองครั้งแรก คืนค่า error หากไฟล์ไม่ถูกต้อง
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate คืนค่าใบรับรองปัจจุบัน และโหลดใหม่หากไฟล์เปลี่ยนตั้งแต่การตรวจครั้งก่อน
+// หากโหลดใบรับรองใหม่ไม่สำเร็จ (เช่น เขียนไฟล์ไม่ครบ) จะใช้ใบรับรองเดิมต่อและลองใหม่ในรอบถัดไป
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) >= certCheckInterval {
+		r.checkedAt = time.Now()
+		modTime, err := r.latestModTime()
+		if err != nil {
+			log.Printf("⚠️  ตรวจสอบไฟล์ใบรับรองไม่สำเร็จ ใช้ใบรับรองเดิมต่อ: %v", err)
+		} else if modTime.After(r.modTime) {
+			if err := r.load(modTime); err != nil {
+				log.Printf("⚠️  โหลดใบรับรองใหม่ไม่สำเร็จ ใช้ใบรับรองเดิมต่อ: %v", err)
+			} else {
+				log.Println("🔐 โหลดใบรับรอง TLS ใหม่สำเร็จ")
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+// load อ่านคู่ใบรับรองและ key จากไฟล์
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("ไม่สามารถโหลดใบรับรอง TLS ได้: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// latestModTime คืนค่าเวลาแก้ไขล่าสุดของไฟล์ใบรับรองและ key
+// os.Stat อ่านผ่าน symlink จึงรองรับการหมุนใบรับรองแบบเปลี่ยน symlink (เช่น Kubernetes secret)
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// NewTLSConfig สร้าง tls.Config จากการตั้งค่า TLS_* พร้อมการโหลดใบรับรองใหม่อัตโนมัติ
+func NewTLSConfig(cfg *config.TLSConfig) (*tls.Config, error) {
+	reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     tlsVersions[cfg.MinVersion],
+		GetCertificate: reloader.GetCertificate,
+		NextProtos:     []string{"http/1.1"},
+	}, nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage เก็บไฟล์ในโฟลเดอร์ของเครื่อง (STORAGE_DRIVER=local)
+// ไฟล์ให้บริการผ่าน static route ของเซิร์ฟเวอร์ที่ PublicURL หรือผ่าน web server/CDN ที่ชี้มาที่โฟลเดอร์เดียวกัน
+type LocalStorage struct {
+	Dir       string // โฟลเดอร์เก็บไฟล์
+	PublicURL string // URL หรือ path ที่ใช้สร้างลิงก์ของไฟล์
+}
+
+// Put เขียนไฟล์ลงไฟล์ชั่วคราวแล้วเปลี่ยนชื่อ เพื่อไม่ให้ผู้อ่านเห็นไฟล์ที่เขียนไม่ครบ
+func (s *LocalStorage) Put(_ context.Context, key string, r io.Reader, _ string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// DeletePrefix ลบไฟล์ที่ key ขึ้นต้นด้วย prefix และโฟลเดอร์ที่ว่างหลังการลบ
+func (s *LocalStorage) DeletePrefix(_ context.Context, prefix string) error {
+	cleaned, err := cleanKey(prefix)
+	if err != nil {
+		return err
+	}
+	// คง "/" ท้าย prefix ไว้ เพื่อให้ avatars/12/ ไม่ตรงกับ avatars/123/
+	if strings.HasSuffix(prefix, "/") {
+		cleaned += "/"
+	}
+	prefix = cleaned
+	root := filepath.Join(s.Dir, filepath.FromSlash(path.Dir(prefix)))
+	err = filepath.WalkDir(root, func(file string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.Dir, file)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); !entry.IsDir() && strings.HasPrefix(key, prefix) {
+			return os.Remove(file)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(prefix, "/") {
+		// ลบเฉพาะโฟลเดอร์ของ prefix ที่ว่างแล้ว (ไม่ลบโฟลเดอร์แม่ที่ Put อื่นอาจกำลังเขียนอยู่)
+		os.Remove(filepath.Join(s.Dir, filepath.FromSlash(prefix)))
+	}
+	return nil
+}
+
+// URL ต่อ key เข้ากับ PublicURL
+func (s *LocalStorage) URL(key string) string {
+	return strings.TrimSuffix(s.PublicURL, "/") + "/" + key
+}
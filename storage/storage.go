@@ -0,0 +1,38 @@
+// Package storage เก็บไฟล์ที่อัปโหลด (blob) ผ่าน backend ที่เลือกด้วย STORAGE_DRIVER
+// ไฟล์ถูกอ้างถึงด้วย key แบบ path ที่คั่นด้วย "/" เช่น avatars/12/3f9c.../256.jpg
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+)
+
+// Storage เก็บ ลบ และสร้างลิงก์สาธารณะของไฟล์
+type Storage interface {
+	// Put เขียนไฟล์ที่ key (ทับไฟล์เดิมถ้ามี)
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// DeletePrefix ลบทุกไฟล์ที่ key ขึ้นต้นด้วย prefix (ไม่มีไฟล์ = ไม่ถือว่าผิดพลาด)
+	DeletePrefix(ctx context.Context, prefix string) error
+	// URL คืนค่าลิงก์สาธารณะของไฟล์
+	URL(key string) string
+}
+
+// New สร้าง Storage ตาม STORAGE_DRIVER
+func New(cfg *config.StorageConfig) Storage {
+	return &LocalStorage{Dir: cfg.LocalDir, PublicURL: cfg.PublicURL}
+}
+
+// cleanKey ตรวจว่า key เป็น path แบบสัมพัทธ์ที่ไม่ออกนอกที่เก็บไฟล์ (ไม่มี .. หรือขึ้นต้นด้วย /)
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if key == "" || cleaned != strings.TrimSuffix(key, "/") || strings.HasPrefix(cleaned, "/") ||
+		cleaned == ".." || strings.HasPrefix(cleaned, "../") || strings.Contains(key, "\\") {
+		return "", errors.New("key ของไฟล์ไม่ถูกต้อง: " + key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,99 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName ชื่อ instrumentation ที่ใช้สร้าง span ภายในแอปพลิเคชัน
+const TracerName = "github.com/Sing254463/GoTemplate/Backend"
+
+// Tracer คืนค่า tracer ของแอปพลิเคชันจาก TracerProvider ที่ลงทะเบียนไว้
+// หากยังไม่ได้เรียก Setup จะได้ tracer แบบ no-op ที่ไม่บันทึกอะไรเลย
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Setup ฟังก์ชันสำหรับเริ่มต้น OpenTelemetry tracing ตามการตั้งค่า
+// จะสร้าง exporter ตาม cfg.Exporter (otlp, stdout, none) แล้วลงทะเบียน
+// TracerProvider และ W3C propagator (traceparent, baggage) เป็นค่า global
+// คืนค่าฟังก์ชัน shutdown ที่ต้องเรียกก่อนปิดโปรแกรมเพื่อส่ง span ที่ค้างอยู่
+func Setup(ctx context.Context, cfg *config.TracingConfig, app *config.AppConfig) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider(cfg, app, exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, // W3C traceparent / tracestate
+		propagation.Baggage{},      // W3C baggage
+	))
+
+	return provider.Shutdown, nil
+}
+
+// NewProvider สร้าง TracerProvider จาก exporter ที่กำหนด
+// แยกออกมาเพื่อให้การทดสอบส่ง exporter ของตัวเองได้ เช่น tracetest.NewInMemoryExporter()
+// หาก exporter เป็น nil จะได้ provider ที่สร้าง span ได้แต่ไม่ส่งออกไปที่ใด
+func NewProvider(cfg *config.TracingConfig, app *config.AppConfig, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(app.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("ไม่สามารถสร้าง resource ของ tracing ได้: %w", err)
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	if exporter != nil {
+		if cfg.Exporter == "otlp" {
+			// ส่งเป็นชุดเพื่อลดจำนวน request ไปยัง collector
+			options = append(options, sdktrace.WithBatcher(exporter))
+		} else {
+			// stdout และ exporter สำหรับทดสอบ ส่งทันทีเพื่อให้เห็นผลเมื่อ request จบ
+			options = append(options, sdktrace.WithSyncer(exporter))
+		}
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// newExporter สร้าง SpanExporter ตามชนิดที่กำหนดในการตั้งค่า
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case "otlp":
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, options...)
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case "none", "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("ไม่รู้จัก TRACING_EXPORTER: %q (รองรับ otlp, stdout, none)", cfg.Exporter)
+	}
+}
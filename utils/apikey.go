@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix ส่วนนำหน้าของทุก API key ช่วยให้เครื่องมือสแกนหา secret (เช่น GitHub secret scanning)
+// และผู้ใช้แยกออกได้ทันทีว่าเป็น key ของระบบนี้
+const APIKeyPrefix = "gtk_"
+
+// GenerateAPIKey สร้าง API key ใหม่ในรูปแบบ gtk_<prefix>_<secret>
+// คืนค่า key เต็ม (แสดงให้ผู้ใช้เพียงครั้งเดียว), prefix (เก็บไว้ค้นหา) และ hash (เก็บในฐานข้อมูล)
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	prefixBytes := make([]byte, 6)  // 12 ตัวอักษร hex สำหรับค้นหา key ในฐานข้อมูล
+	secretBytes := make([]byte, 32) // 256 bits ของความสุ่ม
+	if _, err := rand.Read(prefixBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(prefixBytes)
+	key = APIKeyPrefix + prefix + "_" + hex.EncodeToString(secretBytes)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// HashAPIKey คำนวณ SHA-256 ของ API key
+// ใช้ SHA-256 แทน bcrypt เพราะ key สุ่มมีความยาวพอจนเดาไม่ได้ และต้องตรวจสอบได้เร็วในทุก request
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseAPIKey แยก prefix ออกจาก API key คืนค่า false หากรูปแบบไม่ถูกต้อง
+func ParseAPIKey(key string) (prefix string, ok bool) {
+	rest, found := strings.CutPrefix(key, APIKeyPrefix)
+	if !found {
+		return "", false
+	}
+	prefix, secret, found := strings.Cut(rest, "_")
+	if !found || prefix == "" || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// CheckAPIKey เปรียบเทียบ key กับ hash ที่เก็บไว้แบบ constant-time
+func CheckAPIKey(hash, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashAPIKey(key))) == 1
+}
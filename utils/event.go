@@ -0,0 +1,24 @@
+package utils
+
+import "time"
+
+// EventIDPrefix ส่วนนำหน้าของ ID ของ domain event
+const EventIDPrefix = "evt_"
+
+// GenerateEventID สร้าง ID ของ domain event (สุ่ม 128 bits) ซึ่งเป็น idempotency key ของ subscriber และปลายทางของ webhook
+func GenerateEventID() (string, error) {
+	return randomHex(EventIDPrefix, 16)
+}
+
+// Backoff คืนค่าเวลารอก่อนลองใหม่หลังล้มเหลวครั้งที่ attempt (เริ่มที่ 1)
+// base × 2^(attempt-1) ไม่เกิน ceiling (หยุดเพิ่มเมื่อถึง ceiling จึงไม่ล้นแม้ attempt หรือ ceiling สูงมาก)
+func Backoff(base, ceiling time.Duration, attempt int) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait > 0 && wait < ceiling; i++ {
+		if wait > ceiling/2 {
+			return ceiling
+		}
+		wait *= 2
+	}
+	return min(wait, ceiling)
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// InviteTokenPrefix ส่วนนำหน้าของ token คำเชิญผู้ใช้
+const InviteTokenPrefix = "gti_"
+
+// GenerateInviteToken สร้าง token คำเชิญ (สุ่ม 256 bits) พร้อม hash สำหรับเก็บในฐานข้อมูล
+func GenerateInviteToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = InviteTokenPrefix + hex.EncodeToString(buf)
+	return token, HashInviteToken(token), nil
+}
+
+// HashInviteToken คำนวณ SHA-256 ของ token คำเชิญ (ค้นหาในฐานข้อมูลด้วย hash โดยตรง)
+func HashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
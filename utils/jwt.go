@@ -2,7 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"math/rand"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,23 +10,30 @@ import (
 // JWTClaims โครงสร้างสำหรับเก็บข้อมูลใน JWT token
 // ประกอบด้วยข้อมูลผู้ใช้และ claims มาตรฐานของ JWT
 type JWTClaims struct {
-	UserID               int    `json:"user_id"`  // ID ของผู้ใช้
-	Username             string `json:"username"` // ชื่อผู้ใช้
-	Role                 string `json:"role"`     // สิทธิ์ของผู้ใช้ (user/admin)
+	UserID               int    `json:"user_id"`             // ID ของผู้ใช้
+	Username             string `json:"username"`            // ชื่อผู้ใช้
+	Role                 string `json:"role"`                // สิทธิ์ของผู้ใช้ (user/admin)
+	ClientID             string `json:"client_id,omitempty"` // OAuth2 client ที่ได้รับ token (ว่าง = ผู้ใช้เข้าสู่ระบบเอง)
+	Scope                string `json:"scope,omitempty"`     // scope ที่ client ได้รับอนุญาต คั่นด้วยช่องว่าง
+	TenantID             int    `json:"tid,omitempty"`       // องค์กรที่เลือกตอนเข้าสู่ระบบ (0 = ไม่มี หรือทุกองค์กรสำหรับ Admin ของระบบ)
+	Act                  *Actor `json:"act,omitempty"`       // Admin ที่ใช้ token นี้แทนผู้ใช้ (impersonation, nil = ผู้ใช้เอง)
 	jwt.RegisteredClaims        // Claims มาตรฐาน (เวลาหมดอายุ, เวลาออก, ฯลฯ)
 }
 
+// Actor ผู้ที่กระทำแทนเจ้าของ token ตาม claim act ของ RFC 8693
+// (token ของการ impersonation มี user_id เป็นผู้ใช้เป้าหมาย และ act เป็น Admin ที่ออก token)
+type Actor struct {
+	Subject  string `json:"sub"`      // ID ของ Admin (string ตาม RFC 8693)
+	UserID   int    `json:"user_id"`  // ID ของ Admin
+	Username string `json:"username"` // ชื่อผู้ใช้ของ Admin
+}
+
 // generateJTI สร้าง JWT ID (JTI) ที่ unique สำหรับแต่ละ token
 // JTI ทำให้ token แต่ละครั้งที่ login จะไม่เหมือนกัน แม้ข้อมูลจะเหมือนเดิม
-// รูปแบบ: timestamp-nanosecond-random เช่น 1737148167123456789-5847
-// วิธีนี้ช่วยให้:
-// 1. Token unique ทุกครั้งที่ login
-// 2. สามารถติดตาม session แต่ละครั้งได้
-// 3. เพิ่มความปลอดภัยโดยไม่ต้องเก็บข้อมูลใน database
-func generateJTI() string {
-	// สร้าง JTI ที่ประกอบด้วย timestamp และ random number
-	// format: timestamp-nanosecond-random
-	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), rand.Intn(10000))
+// รูปแบบ: random 16 bytes จาก crypto/rand ในรูปแบบ hex (32 ตัวอักษร) เช่น 9f86d081884c7d659a2feaa0c55ad015
+// JTI เป็น ID ของ session และ key สำหรับเพิกถอน token จึงต้องเดาไม่ได้และไม่ซ้ำกัน
+func generateJTI() (string, error) {
+	return randomHex("", 16)
 }
 
 // GenerateJWT ฟังก์ชันสำหรับสร้าง JWT token พร้อม JTI
@@ -37,26 +43,35 @@ func generateJTI() string {
 // - userID: ID ของผู้ใช้ในฐานข้อมูล
 // - username: ชื่อผู้ใช้
 // - role: บทบาท (user/admin)
+// - tenantID: ID ขององค์กร (0 = ไม่ระบุองค์กร)
 // - secret: กุญแจลับสำหรับเซ็น token
 // - expire: ระยะเวลาหมดอายุ (เช่น "24h", "7d")
 //
 // Returns:
 // - string: JWT token ที่เซ็นแล้ว
 // - error: ข้อผิดพลาด (ถ้ามี)
-func GenerateJWT(userID int, username, role, secret string, expire time.Duration) (string, error) {
+func GenerateJWT(userID int, username, role string, tenantID int, secret string, expire time.Duration) (string, error) {
 
-	// สร้าง claims ที่มีข้อมูลผู้ใช้และเวลาหมดอายุ
 	// JTI (JWT ID) ทำให้ token unique ทุกครั้งที่ login ใหม่
+	jti, err := generateJTI()
+	if err != nil {
+		return "", fmt.Errorf("ไม่สามารถสร้าง JTI ได้: %w", err)
+	}
+
+	// สร้าง claims ที่มีข้อมูลผู้ใช้และเวลาหมดอายุ
 	claims := jwt.MapClaims{
 		"user_id":  userID,                        // ID ผู้ใช้ในฐานข้อมูล
 		"username": username,                      // ชื่อผู้ใช้
 		"role":     role,                          // บทบาท (user/admin)
-		"jti":      generateJTI(),                 // JWT ID - ทำให้ token unique
+		"jti":      jti,                           // JWT ID - ทำให้ token unique
 		"exp":      time.Now().Add(expire).Unix(), // เวลาหมดอายุ (Unix timestamp)
 		"iat":      time.Now().Unix(),             // เวลาที่สร้าง token (Issued At)
 		"iss":      "GoTemplate",                  // ผู้ออก token (Issuer)
 		"aud":      "GoTemplate-Users",            // ผู้รับ token (Audience)
 	}
+	if tenantID != 0 {
+		claims["tid"] = tenantID // องค์กรของ token (Tenant ID)
+	}
 
 	// สร้าง token ด้วย claims และ signing method (HMAC SHA-256)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -70,6 +85,28 @@ func GenerateJWT(userID int, username, role, secret string, expire time.Duration
 	return tokenString, nil
 }
 
+// GenerateAccessToken ฟังก์ชันสำหรับสร้าง JWT จาก JWTClaims
+// ใช้ทั้งกับ token ของผู้ใช้ที่เข้าสู่ระบบ (ผูกกับ session) และ access token ของ OAuth2 authorization server (มี client_id และ scope)
+// ผู้เรียกกำหนด UserID, Username, Role, ClientID, Scope, TenantID, Subject และ Issuer ไว้ใน claims
+// ฟังก์ชันจะเติม jti, iat, exp และ aud ลงใน claims ให้ (ผู้เรียกอ่าน claims.ID ไปบันทึก session หรือใช้เพิกถอนได้)
+func GenerateAccessToken(claims *JWTClaims, secret string, expire time.Duration) (string, error) {
+	jti, err := generateJTI()
+	if err != nil {
+		return "", fmt.Errorf("ไม่สามารถสร้าง JTI ได้: %w", err)
+	}
+	now := time.Now()
+	claims.ID = jti
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(expire))
+	claims.Audience = jwt.ClaimStrings{"GoTemplate-Users"}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("ไม่สามารถเซ็น token ได้: %w", err)
+	}
+	return tokenString, nil
+}
+
 // ParseJWT ฟังก์ชันสำหรับตรวจสอบและแยกข้อมูลจาก JWT token
 // รับ token string และกุญแจลับ แล้วคืนค่า claims หากถูกต้อง
 
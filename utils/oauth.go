@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// ส่วนนำหน้าของค่าที่ OAuth2 authorization server ออกให้ (แยกชนิดได้ทันทีเมื่อพบใน log หรือโค้ด)
+const (
+	OAuthClientIDPrefix     = "gtc_" // client_id (ไม่เป็นความลับ)
+	OAuthClientSecretPrefix = "gts_" // client secret
+	OAuthCodePrefix         = "gta_" // authorization code
+	OAuthRefreshTokenPrefix = "gtr_" // refresh token
+)
+
+// GenerateOAuthToken สร้างค่าสุ่ม 256 bits ที่มี prefix กำหนด พร้อม hash สำหรับเก็บในฐานข้อมูล
+// ใช้กับ client secret, authorization code และ refresh token
+func GenerateOAuthToken(prefix string) (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = prefix + hex.EncodeToString(buf)
+	return token, HashOAuthToken(token), nil
+}
+
+// HashOAuthToken คำนวณ SHA-256 ของค่าที่ได้จาก GenerateOAuthToken (ค่าสุ่มยาวพอจึงไม่ต้องใช้ bcrypt)
+func HashOAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateOAuthClientID สร้าง client_id ใหม่ (สุ่ม 128 bits)
+func GenerateOAuthClientID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return OAuthClientIDPrefix + hex.EncodeToString(buf), nil
+}
+
+// VerifyPKCE ตรวจว่า code_verifier ตรงกับ code_challenge แบบ S256 (RFC 7636)
+func VerifyPKCE(verifier, challenge string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,31 @@
+package utils
+
+import "testing"
+
+// TestVerifyPKCE ตรวจ code_verifier แบบ S256 ด้วยค่าจาก RFC 7636 Appendix B และปฏิเสธ verifier แบบ plain
+func TestVerifyPKCE(t *testing.T) {
+	const (
+		verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	)
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"RFC 7636 appendix B", verifier, challenge, true},
+		{"plain", verifier, verifier, false},
+		{"wrong verifier", verifier[:42] + "K", challenge, false},
+		{"padded challenge", verifier, challenge + "=", false},
+		{"empty verifier", "", challenge, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPKCE(tt.verifier, tt.challenge); got != tt.want {
+				t.Errorf("VerifyPKCE(%q, %q) = %v, ต้องการ %v", tt.verifier, tt.challenge, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,16 +2,60 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 // Response โครงสร้างสำหรับส่งผลลัพธ์กลับไปยัง client
 // ใช้เป็นรูปแบบมาตรฐานสำหรับทุก API response
 type Response struct {
-	Status  bool        `json:"status"`          // สถานะความสำเร็จ (true/false)
-	Message string      `json:"message"`         // ข้อความอธิบาย
-	Data    interface{} `json:"data,omitempty"`  // ข้อมูล (จะแสดงเมื่อสำเร็จ)
-	Error   string      `json:"error,omitempty"` // ข้อผิดพลาด (จะแสดงเมื่อมีข้อผิดพลาด)
+	Status  bool         `json:"status"`           // สถานะความสำเร็จ (true/false)
+	Message string       `json:"message"`          // ข้อความอธิบาย
+	Data    interface{}  `json:"data,omitempty"`   // ข้อมูล (จะแสดงเมื่อสำเร็จ)
+	Error   string       `json:"error,omitempty"`  // ข้อผิดพลาด (จะแสดงเมื่อมีข้อผิดพลาด)
+	Code    string       `json:"code,omitempty"`   // รหัสข้อผิดพลาดสำหรับให้ client ตรวจ เช่น validation_failed
+	Errors  []FieldError `json:"errors,omitempty"` // ข้อผิดพลาดราย field (เมื่อ Code เป็น validation_failed)
+}
+
+// CodeValidationFailed รหัสข้อผิดพลาดเมื่อข้อมูลไม่ผ่านการตรวจสอบ พร้อมรายละเอียดใน Errors
+const CodeValidationFailed = "validation_failed"
+
+// รหัสข้อผิดพลาดเมื่อบัญชีใช้งานไม่ได้ (ส่งตอนเข้าสู่ระบบและเมื่อ token/API key ของบัญชีนั้นถูกใช้)
+const (
+	CodeAccountSuspended = "account_suspended" // ถูกระงับโดย Admin (data.suspended_until = เวลาสิ้นสุด ถ้ามี)
+	CodeAccountPending   = "account_pending"   // บัญชียังไม่เปิดใช้งาน
+)
+
+// CodeImpersonationForbidden รหัสข้อผิดพลาดเมื่อ token ของการ impersonation เรียกเส้นทางที่ต้องเป็นเจ้าของบัญชีเท่านั้น
+const CodeImpersonationForbidden = "impersonation_forbidden"
+
+// FieldError ข้อผิดพลาดของ field หนึ่งใน request
+type FieldError struct {
+	Field   string `json:"field"`   // ชื่อ field ตาม JSON เช่น password
+	Code    string `json:"code"`    // รหัสของกฎที่ไม่ผ่าน เช่น too_short
+	Message string `json:"message"` // คำอธิบายสำหรับแสดงผู้ใช้
+}
+
+// Page ข้อมูลแบบแบ่งหน้าใน Data ของ SuccessResponse
+type Page struct {
+	Items       interface{} `json:"items"`                 // รายการของหน้านี้
+	Page        int         `json:"page"`                  // หน้าปัจจุบัน (เริ่มที่ 1)
+	PerPage     int         `json:"per_page"`              // จำนวนรายการต่อหน้า
+	Total       int         `json:"total"`                 // จำนวนรายการทั้งหมด
+	TotalPages  int         `json:"total_pages"`           // จำนวนหน้าทั้งหมด
+	Approximate bool        `json:"approximate,omitempty"` // true เมื่อ Total นับจากผู้สมัครที่ถูกจำกัดจำนวน (อาจมีรายการมากกว่านี้)
+}
+
+// NewPage สร้าง Page ของหน้า page จากจำนวนทั้งหมด total และคืนค่าช่วง [start, end) ของรายการในหน้านั้น
+// ผู้เรียกตัด slice ด้วย start:end แล้วกำหนด Items เอง (หน้าที่เกินจำนวนได้ช่วงว่าง)
+func NewPage(page, perPage, total int) (p Page, start, end int) {
+	p = Page{Page: page, PerPage: perPage, Total: total, TotalPages: (total + perPage - 1) / perPage}
+	if page > p.TotalPages {
+		return p, total, total
+	}
+	start = (page - 1) * perPage
+	return p, start, min(start+perPage, total)
 }
 
 // SuccessResponse ฟังก์ชันสำหรับส่ง response เมื่อสำเร็จ
@@ -51,3 +95,32 @@ func CreatedResponse(c *fiber.Ctx, message string, data interface{}) error {
 		Data:    data,    // ข้อมูลที่สร้างใหม่
 	})
 }
+
+// ValidationErrorResponse ฟังก์ชันสำหรับส่ง response เมื่อข้อมูลไม่ผ่านการตรวจสอบ (HTTP 400)
+// client อ่าน code=validation_failed และรายละเอียดราย field จาก errors เพื่อแสดงข้างช่องกรอกข้อมูล
+func ValidationErrorResponse(c *fiber.Ctx, message string, errs []FieldError) error {
+	return c.Status(fiber.StatusBadRequest).JSON(Response{
+		Status:  false,
+		Message: message,
+		Code:    CodeValidationFailed,
+		Errors:  errs,
+	})
+}
+
+// AccountStatusResponse ฟังก์ชันสำหรับส่ง response เมื่อบัญชีใช้งานไม่ได้ (HTTP 403)
+// status คือสถานะที่มีผลของบัญชี (suspended หรือ pending) client แยกกรณีได้จาก code
+func AccountStatusResponse(c *fiber.Ctx, status string, suspendedUntil *time.Time) error {
+	response := Response{
+		Status:  false,
+		Message: "บัญชีนี้ยังไม่เปิดใช้งาน กรุณาติดต่อผู้ดูแลระบบ",
+		Code:    CodeAccountPending,
+	}
+	if status == "suspended" {
+		response.Message = "บัญชีนี้ถูกระงับ กรุณาติดต่อผู้ดูแลระบบ"
+		response.Code = CodeAccountSuspended
+		if suspendedUntil != nil {
+			response.Data = fiber.Map{"suspended_until": suspendedUntil.UTC()}
+		}
+	}
+	return c.Status(fiber.StatusForbidden).JSON(response)
+}
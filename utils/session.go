@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetSessionCookies ตั้ง cookie ของ session (token แบบ HttpOnly) และ cookie ของ CSRF token (JavaScript อ่านได้)
+// ทั้งสอง cookie มีอายุเท่ากับ token และคืนค่า CSRF token ให้ส่งกลับใน response ด้วย
+func SetSessionCookies(c *fiber.Ctx, cfg *config.SessionConfig, secret, token string, expire time.Duration) (string, error) {
+	csrf, err := GenerateCSRFToken(secret, token)
+	if err != nil {
+		return "", err
+	}
+	maxAge := int(expire / time.Second)
+	c.Cookie(sessionCookie(cfg, cfg.CookieName, token, maxAge, true))
+	c.Cookie(sessionCookie(cfg, cfg.CSRFCookieName, csrf, maxAge, false))
+	return csrf, nil
+}
+
+// ClearSessionCookies ลบ cookie ของ session และ CSRF token
+func ClearSessionCookies(c *fiber.Ctx, cfg *config.SessionConfig) {
+	c.Cookie(sessionCookie(cfg, cfg.CookieName, "", -1, true))
+	c.Cookie(sessionCookie(cfg, cfg.CSRFCookieName, "", -1, false))
+}
+
+// sessionCookie สร้าง cookie ตามการตั้งค่า SESSION_COOKIE_*
+func sessionCookie(cfg *config.SessionConfig, name, value string, maxAge int, httpOnly bool) *fiber.Cookie {
+	cookie := &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     cfg.CookiePath,
+		Domain:   cfg.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   cfg.CookieSecure,
+		HTTPOnly: httpOnly,
+		SameSite: cfg.CookieSameSite,
+	}
+	if maxAge < 0 {
+		// fasthttp ไม่เขียน Max-Age ที่ติดลบ จึงลบ cookie ด้วย Expires ในอดีตแทน
+		cookie.Expires = time.Unix(0, 0)
+	}
+	return cookie
+}
+
+// GenerateCSRFToken สร้าง CSRF token ที่ผูกกับ session token ในรูปแบบ <random>.<HMAC>
+// การผูกกับ session ทำให้ผู้โจมตีที่ตั้ง cookie ให้เหยื่อได้ (เช่น จาก subdomain) ไม่สามารถสร้าง token ที่ใช้ได้เอง
+func GenerateCSRFToken(secret, session string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+	return nonce + "." + csrfSignature(secret, session, nonce), nil
+}
+
+// VerifyCSRFToken ตรวจว่า CSRF token สร้างจาก session token นี้ด้วย GenerateCSRFToken
+func VerifyCSRFToken(secret, session, token string) bool {
+	nonce, signature, found := strings.Cut(token, ".")
+	if !found || nonce == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(csrfSignature(secret, session, nonce)))
+}
+
+// csrfSignature คำนวณ HMAC-SHA256 ของ nonce และ hash ของ session token
+// ใช้ key ที่แยกจาก JWT_SECRET เพื่อไม่ให้ลายเซ็นนี้ถูกนำไปใช้เป็นลายเซ็นของ JWT
+func csrfSignature(secret, session, nonce string) string {
+	key := sha256.Sum256([]byte("csrf:" + secret))
+	sessionHash := sha256.Sum256([]byte(session))
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write([]byte(nonce))
+	mac.Write(sessionHash[:])
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// browserNames และ platformNames รายการคำใน User-Agent ที่ใช้เดาชื่ออุปกรณ์ (ตรวจตามลำดับ)
+// Edge และ Opera มีคำว่า Chrome อยู่ด้วย ส่วน Chrome มีคำว่า Safari จึงต้องตรวจก่อน
+var (
+	browserNames = []struct{ token, name string }{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"Chrome/", "Chrome"},
+		{"Safari/", "Safari"}, {"curl/", "curl"}, {"PostmanRuntime/", "Postman"}, {"okhttp/", "Android app"},
+	}
+	platformNames = []struct{ token, name string }{
+		{"iPhone", "iPhone"}, {"iPad", "iPad"}, {"Android", "Android"}, {"Windows", "Windows"},
+		{"Macintosh", "macOS"}, {"CrOS", "ChromeOS"}, {"Linux", "Linux"},
+	}
+)
+
+// DeviceName สร้างชื่ออุปกรณ์ที่อ่านง่ายจาก User-Agent เช่น "Chrome on Windows"
+// ใช้แสดงในรายการ session เท่านั้น ไม่ได้ใช้ตัดสินเรื่องความปลอดภัย
+func DeviceName(userAgent string) string {
+	browser, platform := "", ""
+	for _, b := range browserNames {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, p := range platformNames {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return "Unknown device"
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCSRFToken ตรวจว่า CSRF token ใช้ได้กับ session และ secret ที่สร้างเท่านั้น และ token ที่ถูกแก้ไขถูกปฏิเสธ
+func TestCSRFToken(t *testing.T) {
+	const (
+		secret  = "test-secret"
+		session = "session-token-a"
+	)
+	token, err := GenerateCSRFToken(secret, session)
+	if err != nil {
+		t.Fatalf("GenerateCSRFToken: %v", err)
+	}
+	nonce, signature, _ := strings.Cut(token, ".")
+
+	again, err := GenerateCSRFToken(secret, session)
+	if err != nil {
+		t.Fatalf("GenerateCSRFToken: %v", err)
+	}
+	if again == token {
+		t.Error("token สองครั้งของ session เดียวกันไม่ควรเหมือนกัน")
+	}
+
+	tests := []struct {
+		name    string
+		secret  string
+		session string
+		token   string
+		want    bool
+	}{
+		{"valid", secret, session, token, true},
+		{"second token", secret, session, again, true},
+		{"other session", secret, "session-token-b", token, false},
+		{"empty session", secret, "", token, false},
+		{"other secret", "other-secret", session, token, false},
+		{"tampered nonce", secret, session, "x" + nonce[1:] + "." + signature, false},
+		{"tampered signature", secret, session, nonce + "." + signature[:len(signature)-1] + "x", false},
+		{"swapped parts", secret, session, signature + "." + nonce, false},
+		{"missing signature", secret, session, nonce, false},
+		{"empty signature", secret, session, nonce + ".", false},
+		{"empty nonce", secret, session, "." + signature, false},
+		{"empty", secret, session, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyCSRFToken(tt.secret, tt.session, tt.token); got != tt.want {
+				t.Errorf("VerifyCSRFToken(%q) = %v, ต้องการ %v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
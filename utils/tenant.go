@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TenantFromRequest คืนค่า slug ขององค์กรที่ request ระบุ จาก header TENANT_HEADER
+// หรือ subdomain ของ TENANT_BASE_DOMAIN (เช่น acme.example.com → "acme") คืนค่าว่างเมื่อไม่ได้ระบุ
+func TenantFromRequest(c *fiber.Ctx, cfg *config.TenantConfig) string {
+	if slug := strings.TrimSpace(c.Get(cfg.Header)); slug != "" {
+		return strings.ToLower(slug)
+	}
+	if cfg.BaseDomain == "" {
+		return ""
+	}
+	host := strings.ToLower(c.Hostname())
+	if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	sub, found := strings.CutSuffix(host, "."+strings.ToLower(strings.TrimSuffix(cfg.BaseDomain, ".")))
+	// รับเฉพาะ subdomain ชั้นเดียว (a.b.example.com ไม่ถือว่าระบุองค์กร)
+	if !found || sub == "" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// WebhookSecretPrefix ส่วนนำหน้าของ secret สำหรับเซ็น payload
+const WebhookSecretPrefix = "whsec_"
+
+// WebhookSignatureHeader header ที่มีลายเซ็นของ payload ในรูปแบบ t=<unix>,v1=<hex>
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// GenerateWebhookSecret สร้าง secret ใหม่สำหรับเซ็น payload (สุ่ม 256 bits)
+func GenerateWebhookSecret() (string, error) {
+	return randomHex(WebhookSecretPrefix, 32)
+}
+
+// SignWebhook คำนวณค่า header X-Webhook-Signature ของ body ณ เวลา timestamp
+// ลายเซ็นคือ HMAC-SHA256 ของ "<unix timestamp>.<body>" ด้วย secret ปลายทางคำนวณซ้ำแล้วเทียบแบบ constant-time
+// และควรปฏิเสธ timestamp ที่เก่าเกินไป (เช่น 5 นาที) เพื่อกันการส่งซ้ำโดยผู้ไม่หวังดี
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomHex สร้างค่าสุ่มขนาด size bytes ในรูปแบบ hex ต่อท้าย prefix
+func randomHex(prefix string, size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
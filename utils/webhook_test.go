@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSignWebhook ตรวจลายเซ็นกับค่าที่คำนวณไว้ล่วงหน้า (HMAC-SHA256 ของ "<t>.<body>")
+// ค่าเดียวกันได้จาก: printf '1700000000.{"id":"evt_1"}' | openssl dgst -sha256 -hmac whsec_test
+func TestSignWebhook(t *testing.T) {
+	const want = "t=1700000000,v1=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"
+	timestamp := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+
+	if got := SignWebhook("whsec_test", timestamp, body); got != want {
+		t.Errorf("SignWebhook = %q, ต้องการ %q", got, want)
+	}
+
+	// timestamp, body และ secret ต่างกันต้องได้ลายเซ็นต่างกัน (timestamp ถูกเซ็นด้วย จึงแก้ t= อย่างเดียวไม่ได้)
+	for name, got := range map[string]string{
+		"timestamp": SignWebhook("whsec_test", timestamp.Add(time.Second), body),
+		"body":      SignWebhook("whsec_test", timestamp, []byte(`{"id":"evt_2"}`)),
+		"secret":    SignWebhook("whsec_other", timestamp, body),
+	} {
+		if _, v1, _ := strings.Cut(got, ",v1="); strings.HasSuffix(want, v1) {
+			t.Errorf("เปลี่ยน %s แล้วได้ลายเซ็นเดิม: %q", name, got)
+		}
+	}
+}
+
+// TestBackoff ตรวจการเพิ่มเวลารอแบบ exponential, การจำกัดที่ ceiling และไม่ล้นเมื่อ attempt หรือ ceiling สูงมาก
+func TestBackoff(t *testing.T) {
+	const maxDuration = time.Duration(1<<63 - 1)
+
+	tests := []struct {
+		name    string
+		base    time.Duration
+		ceiling time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{"first attempt", time.Second, time.Hour, 1, time.Second},
+		{"zero attempt", time.Second, time.Hour, 0, time.Second},
+		{"second attempt", time.Second, time.Hour, 2, 2 * time.Second},
+		{"fifth attempt", time.Second, time.Hour, 5, 16 * time.Second},
+		{"just below ceiling", time.Second, time.Hour, 12, 2048 * time.Second},
+		{"clamped", time.Second, time.Hour, 13, time.Hour},
+		{"base above ceiling", 2 * time.Hour, time.Hour, 1, time.Hour},
+		{"high attempt", time.Second, time.Hour, 1000, time.Hour},
+		{"max attempt", time.Second, time.Hour, int(^uint(0) >> 1), time.Hour},
+		{"huge ceiling", time.Second, maxDuration, 100, maxDuration},
+		{"huge ceiling below overflow", time.Second, maxDuration, 34, (1 << 33) * time.Second},
+		{"zero base", 0, time.Hour, int(^uint(0) >> 1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Backoff(tt.base, tt.ceiling, tt.attempt); got != tt.want {
+				t.Errorf("Backoff(%s, %s, %d) = %s, ต้องการ %s", tt.base, tt.ceiling, tt.attempt, got, tt.want)
+			}
+		})
+	}
+
+	// เวลารอต้องไม่ลดลงเมื่อ attempt เพิ่ม
+	previous := time.Duration(0)
+	for attempt := 1; attempt <= 100; attempt++ {
+		got := Backoff(time.Second, maxDuration, attempt)
+		if got < previous {
+			t.Fatalf("Backoff attempt %d = %s น้อยกว่า attempt ก่อนหน้า %s", attempt, got, previous)
+		}
+		previous = got
+	}
+}
@@ -0,0 +1,149 @@
+// Package webhook ส่ง event ของผู้ใช้จาก outbox (ตาราง webhook_deliveries) ไปยังปลายทางที่ Admin ลงทะเบียนไว้
+// รายการส่งถูกสร้างโดย subscriber ของ event bus (NewSubscriber) จาก domain event ใน event_outbox แล้ว Dispatcher ส่งแบบเบื้องหลังพร้อมลายเซ็น HMAC
+// การส่งเป็นแบบ at-least-once: ปลายทางอาจได้ event เดิมซ้ำ จึงควรตัดรายการซ้ำด้วย X-Webhook-Event-ID
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Sing254463/GoTemplate/Backend/config"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/Sing254463/GoTemplate/Backend/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// claimMargin เวลาที่จองการส่งเผื่อจาก WEBHOOK_TIMEOUT (worker ที่หยุดกลางคันจะถูกส่งซ้ำหลังเวลานี้)
+const claimMargin = 30 * time.Second
+
+// maxResponseSnippet จำนวน bytes ของ body ที่ปลายทางตอบกลับซึ่งเก็บไว้ใน last_error เมื่อไม่ได้ 2xx
+const maxResponseSnippet = 512
+
+// Dispatcher อ่านการส่งที่ถึงเวลาจาก outbox แล้วส่งไปยังปลายทาง บันทึกผล และกำหนดเวลาส่งซ้ำแบบ exponential backoff
+// อ่านการตั้งค่า WEBHOOK_* ล่าสุดทุกรอบ จึงเปลี่ยนค่าได้โดยไม่ต้อง restart
+type Dispatcher struct {
+	Config   *config.Store                 // การตั้งค่าระบบ
+	Webhooks *repository.WebhookRepository // การเข้าถึงตาราง webhooks และ webhook_deliveries
+	Client   *http.Client                  // HTTP client ที่ใช้ส่ง (ไม่ตาม redirect)
+}
+
+// NewDispatcher สร้าง Dispatcher ใหม่
+func NewDispatcher(store *config.Store, db *sqlx.DB) *Dispatcher {
+	return &Dispatcher{
+		Config:   store,
+		Webhooks: repository.NewWebhookRepository(db),
+		Client: &http.Client{
+			// redirect อาจพา payload ไปยังปลายทางที่ไม่ได้ลงทะเบียน จึงถือว่า 3xx เป็นการส่งไม่สำเร็จ
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// Run ส่ง webhook ทุก WEBHOOK_POLL_INTERVAL จนกว่า ctx จะถูกยกเลิก (เรียกใน goroutine แยก)
+// เมื่อ WEBHOOK_ENABLED=false จะข้ามรอบไป (event ยังถูกบันทึกใน outbox และส่งเมื่อเปิดอีกครั้ง)
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		cfg := d.Config.Get().Webhook
+		if cfg.Enabled {
+			if err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("⚠️  ไม่สามารถส่ง webhook ได้: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cfg.PollInterval):
+		}
+	}
+}
+
+// Dispatch ส่งการส่งที่ถึงเวลาหนึ่งรอบ (ไม่เกิน WEBHOOK_BATCH_SIZE รายการ ส่งพร้อมกัน) และรอจนส่งครบ
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	cfg := d.Config.Get()
+	now := time.Now().UTC()
+	deliveries, err := d.Webhooks.Due(ctx, now, cfg.Webhook.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		// จองก่อนส่ง instance อื่นที่อ่านรายการเดียวกันจะจองไม่ได้และข้ามไป
+		claimed, err := d.Webhooks.Claim(ctx, delivery.ID, now, now.Add(cfg.Webhook.Timeout+claimMargin))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, cfg, delivery)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// deliver ส่งหนึ่งรายการและบันทึกผล (สำเร็จ, รอส่งซ้ำ หรือ failed เมื่อครบ WEBHOOK_MAX_ATTEMPTS)
+func (d *Dispatcher) deliver(ctx context.Context, cfg *config.Config, delivery *repository.PendingDelivery) {
+	status, err := d.post(ctx, cfg, delivery)
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	now := time.Now().UTC()
+	var next *time.Time
+	if attempt := delivery.Attempts + 1; err != nil && attempt < cfg.Webhook.MaxAttempts {
+		at := now.Add(utils.Backoff(cfg.Webhook.Backoff, cfg.Webhook.BackoffMax, attempt))
+		next = &at
+	}
+	if err := d.Webhooks.RecordAttempt(ctx, delivery.ID, now, responseStatus, err, next); err != nil {
+		log.Printf("⚠️  ไม่สามารถบันทึกผลการส่ง webhook %d ได้: %v", delivery.ID, err)
+	}
+}
+
+// post ส่ง payload ที่เซ็นแล้วไปยังปลายทาง คืนค่า HTTP status (0 = ไม่ได้รับคำตอบ) และ error เมื่อไม่ได้ 2xx
+func (d *Dispatcher) post(ctx context.Context, cfg *config.Config, delivery *repository.PendingDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Webhook.Timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", cfg.App.Name+" Webhook/"+cfg.App.Version)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.ID))
+	req.Header.Set(utils.WebhookSignatureHeader, utils.SignWebhook(delivery.Secret, time.Now(), body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := fmt.Sprintf("ปลายทางตอบ HTTP %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(snippet)); text != "" {
+			message += ": " + text
+		}
+		return resp.StatusCode, errors.New(message)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Sing254463/GoTemplate/Backend/events"
+	"github.com/Sing254463/GoTemplate/Backend/models"
+	"github.com/Sing254463/GoTemplate/Backend/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// SubscriberName ชื่อ subscriber ของ webhook ใน event bus (idempotency key ใน event_consumptions ห้ามเปลี่ยน)
+const SubscriberName = "webhooks"
+
+// eventNames ชนิด event ของ webhook ที่ตรงกับ domain event แต่ละชื่อ
+var eventNames = map[string]string{
+	models.EventUserRegistered: models.WebhookUserRegistered,
+	models.EventUserLoggedIn:   models.WebhookUserLogin,
+	models.EventUserUpdated:    models.WebhookUserUpdated,
+	models.EventUserDeleted:    models.WebhookUserDeleted,
+}
+
+// NewSubscriber สร้าง subscriber ที่เพิ่มการส่งลง outbox ของ webhook (webhook_deliveries) สำหรับทุก webhook ที่สมัครรับ event
+// การส่งถูกบันทึกใน transaction ของ event bus จึงเกิดครั้งเดียวต่อ event และใช้ ID ของ domain event เป็น X-Webhook-Event-ID
+func NewSubscriber(db *sqlx.DB) events.Subscriber {
+	webhooks := repository.NewWebhookRepository(db)
+	names := make([]string, 0, len(eventNames))
+	for name := range eventNames {
+		names = append(names, name)
+	}
+	return events.Subscriber{
+		Name:   SubscriberName,
+		Events: names,
+		Handle: func(ctx context.Context, tx *sqlx.Tx, event *events.Envelope) error {
+			// payload ของ domain event เป็น data ของ webhook ตามเดิม เช่น {"user": {...}}
+			data := json.RawMessage(event.Payload)
+			return webhooks.Enqueue(ctx, tx, eventNames[event.Name], event.EventID, event.CreatedAt, data)
+		},
+	}
+}